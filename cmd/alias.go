@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var noteAliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage alternative names a note can be linked by",
+}
+
+var noteAliasAddCmd = &cobra.Command{
+	Use:   "add <slug-or-id> <alias>",
+	Short: "Add an alias to a note",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := db.AddNoteAlias(note.ID, args[1]); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printNoteAliases(cmd, note)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Added alias %q to note %q\n", args[1], note.Title)
+		return nil
+	},
+}
+
+var noteAliasRemoveCmd = &cobra.Command{
+	Use:   "remove <slug-or-id> <alias>",
+	Short: "Remove an alias from a note",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := db.RemoveNoteAlias(note.ID, args[1]); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printNoteAliases(cmd, note)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed alias %q from note %q\n", args[1], note.Title)
+		return nil
+	},
+}
+
+var noteAliasListCmd = &cobra.Command{
+	Use:   "list <slug-or-id>",
+	Short: "List a note's aliases",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+		return printNoteAliases(cmd, note)
+	},
+}
+
+func printNoteAliases(cmd *cobra.Command, note *model.Note) error {
+	aliases, err := db.ListNoteAliases(note.ID)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if aliases == nil {
+			aliases = []string{}
+		}
+		return printJSON(noteAliasesJSON{NoteID: note.ID, Slug: note.Slug, Aliases: aliases})
+	}
+
+	if len(aliases) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Note %q has no aliases.\n", note.Title)
+		return nil
+	}
+	for _, alias := range aliases {
+		fmt.Fprintln(cmd.OutOrStdout(), alias)
+	}
+	return nil
+}
+
+func init() {
+	noteAliasCmd.AddCommand(noteAliasAddCmd)
+	noteAliasCmd.AddCommand(noteAliasRemoveCmd)
+	noteAliasCmd.AddCommand(noteAliasListCmd)
+	noteCmd.AddCommand(noteAliasCmd)
+}
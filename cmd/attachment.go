@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"text/tabwriter"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var noteAttachCmd = &cobra.Command{
+	Use:   "attach <slug-or-id> <file>",
+	Short: "Attach a file to a note",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+
+		a, err := db.AttachFile("note", note.ID, args[1])
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(toAttachmentJSON(a, db.AttachmentPath(a)))
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Attached %q to note %q (embed with: ![[%s]])\n", a.Name, note.Title, a.Name)
+		return nil
+	},
+}
+
+var cardAttachCmd = &cobra.Command{
+	Use:   "attach <id> <file>",
+	Short: "Attach a file to a card",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		board, err := resolveBoard()
+		if err != nil {
+			return err
+		}
+
+		cardID, err := resolveCardID(board.ID, args[0])
+		if err != nil {
+			return err
+		}
+
+		card, err := db.GetCard(cardID)
+		if err != nil {
+			return err
+		}
+
+		a, err := db.AttachFile("card", card.ID, args[1])
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(toAttachmentJSON(a, db.AttachmentPath(a)))
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Attached %q to card %q\n", a.Name, card.Title)
+		return nil
+	},
+}
+
+var attachmentCmd = &cobra.Command{
+	Use:     "attachments",
+	Aliases: []string{"attachment"},
+	Short:   "Manage files attached to notes and cards",
+}
+
+var attachmentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List attachments",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noteRef, _ := cmd.Flags().GetString("note")
+		cardRef, _ := cmd.Flags().GetString("card")
+
+		var ownerType, ownerID string
+		switch {
+		case noteRef != "" && cardRef != "":
+			return fmt.Errorf("use either --note or --card, not both")
+		case noteRef != "":
+			note, err := resolveNote(noteRef)
+			if err != nil {
+				return err
+			}
+			ownerType, ownerID = "note", note.ID
+		case cardRef != "":
+			board, err := resolveBoard()
+			if err != nil {
+				return err
+			}
+			cardID, err := resolveCardID(board.ID, cardRef)
+			if err != nil {
+				return err
+			}
+			ownerType, ownerID = "card", cardID
+		}
+
+		attachments, err := db.ListAttachments(ownerType, ownerID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			out := make([]attachmentJSON, len(attachments))
+			for i, a := range attachments {
+				out[i] = toAttachmentJSON(a, db.AttachmentPath(a))
+			}
+			return printJSON(out)
+		}
+
+		if len(attachments) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No attachments. Add one with: kb note attach <slug> <file>")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSIZE\tATTACHED TO")
+		for _, a := range attachments {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", a.ID[:8], a.Name, model.FormatSize(a.Size), attachmentOwnerLabel(a))
+		}
+		return w.Flush()
+	},
+}
+
+var attachmentOpenCmd = &cobra.Command{
+	Use:   "open <id-or-name>",
+	Short: "Open an attachment with the system viewer",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := db.ResolveAttachment(args[0])
+		if err != nil {
+			return err
+		}
+
+		// Copy the file out under its original name so the viewer picks
+		// the right application from the extension.
+		path := filepath.Join(os.TempDir(), "kb-attachments", a.Hash[:8], a.Name)
+		if err := copyFile(db.AttachmentPath(a), path); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(toAttachmentJSON(a, path))
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Opening %s\n", path)
+		return openFile(path)
+	},
+}
+
+var attachmentRemoveCmd = &cobra.Command{
+	Use:     "rm <id-or-name>",
+	Aliases: []string{"remove"},
+	Short:   "Remove an attachment",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := db.ResolveAttachment(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := db.RemoveAttachment(a.ID); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(toAttachmentJSON(a, ""))
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed attachment %q\n", a.Name)
+		return nil
+	},
+}
+
+func attachmentOwnerLabel(a *model.Attachment) string {
+	kind, id := a.Owner()
+	if kind == "card" {
+		if card, err := db.GetCard(id); err == nil {
+			return "card: " + truncateStr(card.Title, 40)
+		}
+		return "card: " + id[:8]
+	}
+	if note, err := db.GetNote(id); err == nil {
+		return "note: " + note.Slug
+	}
+	return "note: " + id[:8]
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", src, err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("creating directory %s: %w", filepath.Dir(dest), err)
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", dest, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("copying to %s: %w", dest, err)
+	}
+	return out.Close()
+}
+
+func openFile(path string) error {
+	openCmd := "open"
+	if runtime.GOOS == "linux" {
+		openCmd = "xdg-open"
+	}
+	return exec.Command(openCmd, path).Start()
+}
+
+func init() {
+	attachmentListCmd.Flags().String("note", "", "Only attachments of this note")
+	attachmentListCmd.Flags().String("card", "", "Only attachments of this card (on the current board)")
+
+	attachmentCmd.AddCommand(attachmentListCmd)
+	attachmentCmd.AddCommand(attachmentOpenCmd)
+	attachmentCmd.AddCommand(attachmentRemoveCmd)
+	noteCmd.AddCommand(noteAttachCmd)
+	cardCmd.AddCommand(cardAttachCmd)
+	rootCmd.AddCommand(attachmentCmd)
+}
@@ -425,7 +425,7 @@ func resolveCardID(boardID, prefix string) (string, error) {
 
 	var matches []string
 	for _, c := range cards {
-		if c.ID == prefix || (len(prefix) >= 4 && strings.HasPrefix(c.ID, prefix)) {
+		if c.ID == prefix || (len(prefix) >= model.MinCardIDPrefix && strings.HasPrefix(c.ID, prefix)) {
 			matches = append(matches, c.ID)
 		}
 	}
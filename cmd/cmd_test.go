@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/jeryldev/kb/internal/graph"
 	"github.com/jeryldev/kb/internal/model"
 	"github.com/jeryldev/kb/internal/store"
 	"github.com/spf13/cobra"
@@ -22,6 +24,7 @@ func setupTestDB(t *testing.T) {
 		t.Fatalf("opening test db: %v", err)
 	}
 	db = testDB
+	db.SetAttachmentDir(t.TempDir())
 	origPostRun := rootCmd.PersistentPostRunE
 	rootCmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error { return nil }
 	t.Cleanup(func() {
@@ -34,6 +37,10 @@ func setupTestDB(t *testing.T) {
 func resetFlags(cmd *cobra.Command) {
 	cmd.Flags().VisitAll(func(f *pflag.Flag) {
 		f.Changed = false
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(nil)
+			return
+		}
 		f.Value.Set(f.DefValue)
 	})
 	for _, sub := range cmd.Commands() {
@@ -1523,6 +1530,103 @@ func TestNoteDeleteJSON(t *testing.T) {
 	}
 }
 
+func TestNoteDeleteMovesToTrash(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Trash Me", "--json")
+	out := executeCmd(t, "notes", "delete", "trash-me")
+	if !strings.Contains(out, "trash") {
+		t.Errorf("expected trash message, got: %s", out)
+	}
+
+	out = executeCmd(t, "notes", "--trash", "--json")
+	var notes []noteJSON
+	if err := json.Unmarshal([]byte(out), &notes); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(notes) != 1 || notes[0].DeletedAt == nil {
+		t.Fatalf("expected 1 trashed note with deleted_at, got %+v", notes)
+	}
+
+	executeCmd(t, "notes", "restore", "trash-me")
+	out = executeCmd(t, "notes", "show", "trash-me", "--json")
+	if !strings.Contains(out, "Trash Me") {
+		t.Errorf("expected restored note, got: %s", out)
+	}
+}
+
+func TestNoteDeletePurgePublished(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Published", "--body", "hello")
+	executeCmd(t, "publish", "setup", "site", "--engine", "jekyll", "--path", t.TempDir())
+	executeCmd(t, "publish", "published")
+
+	executeCmd(t, "notes", "delete", "published", "--purge", "--force")
+
+	out := executeCmd(t, "notes", "--trash")
+	if !strings.Contains(out, "Trash is empty") {
+		t.Errorf("expected empty trash after purge, got: %s", out)
+	}
+	executeCmd(t, "notes", "create", "Published")
+}
+
+func TestNoteDeletePurgeConfirm(t *testing.T) {
+	setupTestDB(t)
+	executeCmd(t, "notes", "create", "Keep Me")
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+
+	rootCmd.SetIn(strings.NewReader("n\n"))
+	out := executeCmd(t, "notes", "delete", "keep-me", "--purge")
+	if !strings.Contains(out, "Cancelled.") {
+		t.Errorf("expected purge to be cancelled, got: %s", out)
+	}
+	executeCmd(t, "notes", "show", "keep-me")
+
+	rootCmd.SetIn(strings.NewReader("y\n"))
+	out = executeCmd(t, "notes", "delete", "keep-me", "--purge")
+	if !strings.Contains(out, "Permanently deleted") {
+		t.Errorf("expected purge to go ahead, got: %s", out)
+	}
+	if _, err := executeCmdErr(t, "notes", "show", "keep-me"); err == nil {
+		t.Error("expected purged note to be gone")
+	}
+}
+
+func TestNoteArchiveUnarchive(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Old Idea")
+
+	out := executeCmd(t, "notes", "archive", "old-idea", "--json")
+	var note noteJSON
+	if err := json.Unmarshal([]byte(out), &note); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if note.ArchivedAt == nil {
+		t.Error("expected archived_at to be set")
+	}
+
+	out = executeCmd(t, "notes")
+	if strings.Contains(out, "old-idea") {
+		t.Errorf("expected archived note to be hidden, got: %s", out)
+	}
+	out = executeCmd(t, "notes", "--archived")
+	if !strings.Contains(out, "old-idea") {
+		t.Errorf("expected archived note in --archived list, got: %s", out)
+	}
+
+	executeCmd(t, "notes", "unarchive", "old-idea")
+	out = executeCmd(t, "notes")
+	if !strings.Contains(out, "old-idea") {
+		t.Errorf("expected unarchived note in list, got: %s", out)
+	}
+
+	if _, err := executeCmdErr(t, "notes", "unarchive", "old-idea"); err == nil {
+		t.Error("expected error unarchiving an active note")
+	}
+}
+
 func TestNoteBacklinksJSON(t *testing.T) {
 	setupTestDB(t)
 
@@ -1541,6 +1645,98 @@ func TestNoteBacklinksJSON(t *testing.T) {
 	}
 }
 
+func TestNoteBacklinksFragment(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Design", "--body", "## Storage\n\nSQLite", "--json")
+	executeCmd(t, "notes", "create", "Source",
+		"--body", "See [[design#Storage]]", "--json")
+
+	out := executeCmd(t, "notes", "backlinks", "design", "--json")
+
+	var links []backlinkJSON
+	if err := json.Unmarshal([]byte(out), &links); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(links) != 1 || links[0].Fragment != "#Storage" {
+		t.Fatalf("expected one backlink to #Storage, got %+v", links)
+	}
+
+	human := executeCmd(t, "notes", "backlinks", "design")
+	if !strings.Contains(human, "[[source]] → #Storage") {
+		t.Errorf("expected section in human output, got: %s", human)
+	}
+}
+
+func TestNoteRelated(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Postgres indexes", "--body", "B-tree indexes speed up postgres queries.", "--json")
+	executeCmd(t, "notes", "create", "Query planning", "--body", "The planner picks indexes for each query.", "--json")
+	executeCmd(t, "notes", "create", "Gardening", "--body", "Tomatoes need sun and water.", "--json")
+
+	out := executeCmd(t, "notes", "related", "postgres-indexes", "--json")
+	var related []relatedNoteJSON
+	if err := json.Unmarshal([]byte(out), &related); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(related) != 1 || related[0].Slug != "query-planning" || related[0].Score <= 0 {
+		t.Fatalf("expected query-planning to be related, got %+v", related)
+	}
+
+	human := executeCmd(t, "notes", "related", "gardening")
+	if !strings.Contains(human, "No related notes") {
+		t.Errorf("expected empty message, got: %s", human)
+	}
+
+	executeCmd(t, "notes", "edit", "query-planning", "--body", "See [[postgres-indexes]] for each query.")
+	human = executeCmd(t, "notes", "related", "postgres-indexes")
+	if strings.Contains(human, "query-planning") {
+		t.Errorf("linked notes should not be suggested, got: %s", human)
+	}
+}
+
+func TestNoteMentions(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Query Planning", "--json")
+	executeCmd(t, "notes", "create", "Standup", "--body", "Discussed query planning.\nMore Query Planning later.", "--json")
+
+	out := executeCmd(t, "notes", "mentions", "query-planning", "--json")
+	var mentions []mentionJSON
+	if err := json.Unmarshal([]byte(out), &mentions); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(mentions) != 2 || mentions[0].Source != "standup" || mentions[1].Line != 2 {
+		t.Fatalf("expected two mentions in standup, got %+v", mentions)
+	}
+
+	if _, err := executeCmdErr(t, "notes", "mentions", "query-planning", "2"); err == nil {
+		t.Error("expected error for mention numbers without --link")
+	}
+	if _, err := executeCmdErr(t, "notes", "mentions", "query-planning", "--link", "3"); err == nil {
+		t.Error("expected error for an out of range mention number")
+	}
+
+	out = executeCmd(t, "notes", "mentions", "query-planning", "--link", "2")
+	if !strings.Contains(out, "Linked 1 mention(s)") {
+		t.Errorf("expected link confirmation, got: %s", out)
+	}
+	show := executeCmd(t, "notes", "show", "standup", "--json")
+	if !strings.Contains(show, "More [[query-planning|Query Planning]] later.") {
+		t.Errorf("expected the second mention to be linked, got: %s", show)
+	}
+	backlinks := executeCmd(t, "notes", "backlinks", "query-planning")
+	if !strings.Contains(backlinks, "standup") {
+		t.Errorf("expected a backlink after linking, got: %s", backlinks)
+	}
+
+	out = executeCmd(t, "notes", "mentions", "query-planning")
+	if !strings.Contains(out, "1. [[standup]]:1") || !strings.Contains(out, "Discussed query planning.") {
+		t.Errorf("expected the remaining mention, got: %s", out)
+	}
+}
+
 func TestNoteCreateHuman(t *testing.T) {
 	setupTestDB(t)
 
@@ -1588,6 +1784,88 @@ func TestNoteShowNotFound(t *testing.T) {
 	}
 }
 
+func TestNoteListWhereSort(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Early", "--body", "---\nstatus: draft\ndue: 2026-11-01\n---\n")
+	executeCmd(t, "notes", "create", "Late", "--body", "---\nstatus: draft\ndue: 2026-12-15\n---\n")
+	executeCmd(t, "notes", "create", "Done", "--body", "---\nstatus: done\ndue: 2026-10-01\n---\n", "--tags", "x")
+
+	out := executeCmd(t, "notes", "--where", "status=draft", "--sort", "-due", "--json")
+	var notes []noteJSON
+	if err := json.Unmarshal([]byte(out), &notes); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(notes) != 2 || notes[0].Slug != "late" || notes[1].Slug != "early" {
+		t.Errorf("unexpected notes: %+v", notes)
+	}
+
+	out = executeCmd(t, "notes", "--where", "status=draft", "--where", "due<2026-12-01", "--json")
+	notes = nil
+	json.Unmarshal([]byte(out), &notes)
+	if len(notes) != 1 || notes[0].Slug != "early" {
+		t.Errorf("expected only early note, got %+v", notes)
+	}
+
+	out = executeCmd(t, "notes", "--tag", "x", "--sort", "due", "--json")
+	notes = nil
+	json.Unmarshal([]byte(out), &notes)
+	if len(notes) != 1 || notes[0].Slug != "done" {
+		t.Errorf("expected --sort to keep the --tag filter, got %+v", notes)
+	}
+
+	if _, err := executeCmdErr(t, "notes", "--where", "status"); err == nil {
+		t.Error("expected error for invalid filter")
+	}
+
+	out = executeCmd(t, "notes", "show", "early", "--json")
+	var note noteJSON
+	json.Unmarshal([]byte(out), &note)
+	if note.Properties["status"] != "draft" || note.Properties["due"] != "2026-11-01" {
+		t.Errorf("unexpected properties: %v", note.Properties)
+	}
+}
+
+func TestNoteAliasCommands(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Use SQLite for storage")
+	executeCmd(t, "notes", "create", "Other")
+
+	out := executeCmd(t, "notes", "alias", "add", "use-sqlite-for-storage", "ADR-7")
+	if !strings.Contains(out, `Added alias "ADR-7"`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	out = executeCmd(t, "notes", "show", "adr-7", "--json")
+	var note noteJSON
+	if err := json.Unmarshal([]byte(out), &note); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if note.Slug != "use-sqlite-for-storage" || len(note.Aliases) != 1 || note.Aliases[0] != "ADR-7" {
+		t.Errorf("expected note resolved by alias, got %+v", note)
+	}
+
+	if _, err := executeCmdErr(t, "notes", "alias", "add", "other", "ADR-7"); err == nil {
+		t.Error("expected conflict error")
+	}
+
+	out = executeCmd(t, "notes", "alias", "list", "ADR-7", "--json")
+	var list noteAliasesJSON
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(list.Aliases) != 1 {
+		t.Errorf("aliases = %v", list.Aliases)
+	}
+
+	executeCmd(t, "notes", "alias", "remove", "use-sqlite-for-storage", "ADR-7")
+	out = executeCmd(t, "notes", "alias", "list", "use-sqlite-for-storage")
+	if !strings.Contains(out, "has no aliases") {
+		t.Errorf("expected empty alias list, got: %s", out)
+	}
+}
+
 // --- Workspace tests ---
 
 func TestWorkspaceCreateJSON(t *testing.T) {
@@ -1900,6 +2178,178 @@ func TestWorkspaceShowWithContents(t *testing.T) {
 	}
 }
 
+func TestTagCommands(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Indexes", "--body", "Tuning #area/backend/db")
+	executeCmd(t, "notes", "create", "Styles", "--tags", "area/frontend")
+	executeCmd(t, "notes", "create", "Ideas", "--body", "Just an #idea")
+
+	out := executeCmd(t, "tags")
+	for _, want := range []string{"#area (2)", "  #backend (1)", "    #db (1)", "  #frontend (1)", "#idea (1)"} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("expected %q in tag tree, got:\n%s", want, out)
+		}
+	}
+
+	out = executeCmd(t, "notes", "--tag", "area", "--json")
+	var notes []noteJSON
+	if err := json.Unmarshal([]byte(out), &notes); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(notes) != 2 {
+		t.Errorf("expected 2 notes under #area, got %d", len(notes))
+	}
+
+	out = executeCmd(t, "tag", "rename", "area/backend", "server")
+	if !strings.Contains(out, "Renamed #area/backend to #server in 1 note(s)") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	out = executeCmd(t, "notes", "show", "indexes", "--json")
+	var note noteJSON
+	if err := json.Unmarshal([]byte(out), &note); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if note.Body != "Tuning #server/db" {
+		t.Errorf("body = %q", note.Body)
+	}
+
+	out = executeCmd(t, "tags", "--json")
+	var tree []tagJSON
+	if err := json.Unmarshal([]byte(out), &tree); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(tree) != 3 || tree[2].Path != "server" || len(tree[2].Children) != 1 {
+		t.Errorf("unexpected tag tree: %+v", tree)
+	}
+
+	if _, err := executeCmdErr(t, "tag", "rename", "idea", "not valid"); err == nil {
+		t.Error("expected error for invalid tag")
+	}
+}
+
+func TestAttachmentCommands(t *testing.T) {
+	setupTestDB(t)
+	os.Setenv("KB_BOARD", "test-board")
+	defer os.Unsetenv("KB_BOARD")
+	createTestBoard(t, "test-board")
+
+	dir := t.TempDir()
+	shot := filepath.Join(dir, "screenshot.png")
+	log := filepath.Join(dir, "crash.log")
+	os.WriteFile(shot, []byte("png-bytes"), 0o644)
+	os.WriteFile(log, []byte("panic: oops"), 0o644)
+
+	executeCmd(t, "notes", "create", "Bug report")
+	out := executeCmd(t, "note", "attach", "bug-report", shot)
+	if !strings.Contains(out, `Attached "screenshot.png" to note "Bug report"`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	out = executeCmd(t, "card", "add", "Fix crash", "--json")
+	var card cardJSON
+	if err := json.Unmarshal([]byte(out), &card); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	out = executeCmd(t, "card", "attach", card.ID[:8], log, "--json")
+	var attached attachmentJSON
+	if err := json.Unmarshal([]byte(out), &attached); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if attached.OwnerType != "card" || attached.OwnerID != card.ID || attached.Size != 11 {
+		t.Errorf("unexpected attachment: %+v", attached)
+	}
+	if data, err := os.ReadFile(attached.Path); err != nil || string(data) != "panic: oops" {
+		t.Errorf("stored file = %q, %v", data, err)
+	}
+
+	out = executeCmd(t, "attachments", "list")
+	for _, want := range []string{"screenshot.png", "note: bug-report", "crash.log", "card: Fix crash"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in list, got:\n%s", want, out)
+		}
+	}
+
+	out = executeCmd(t, "attachments", "list", "--note", "bug-report", "--json")
+	var list []attachmentJSON
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(list) != 1 || list[0].Name != "screenshot.png" || list[0].MimeType != "image/png" {
+		t.Errorf("unexpected note attachments: %+v", list)
+	}
+
+	out = executeCmd(t, "attachments", "rm", "screenshot.png")
+	if !strings.Contains(out, `Removed attachment "screenshot.png"`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if _, err := executeCmdErr(t, "attachments", "rm", "screenshot.png"); err == nil {
+		t.Error("expected error removing a missing attachment")
+	}
+	if _, err := executeCmdErr(t, "note", "attach", "bug-report", filepath.Join(dir, "missing.pdf")); err == nil {
+		t.Error("expected error attaching a missing file")
+	}
+}
+
+func TestNotePromote(t *testing.T) {
+	setupTestDB(t)
+	os.Setenv("KB_BOARD", "sprint-1")
+	defer os.Unsetenv("KB_BOARD")
+	createTestBoard(t, "sprint-1")
+
+	executeCmd(t, "notes", "create", "Retro", "--body", "- [ ] Fix flaky test\n- [x] Celebrate\n- [ ] Update docs")
+
+	out := executeCmd(t, "note", "promote", "retro", "--board", "sprint-1", "--column", "todo")
+	if !strings.Contains(out, "Promoted 2 checkbox(es) to sprint-1 / Todo") || !strings.Contains(out, "Fix flaky test") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	out = executeCmd(t, "note", "promote", "retro", "--json")
+	var cards []cardJSON
+	if err := json.Unmarshal([]byte(out), &cards); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(cards) != 0 {
+		t.Errorf("expected nothing left to promote, got %d", len(cards))
+	}
+
+	out = executeCmd(t, "cards", "--json")
+	if err := json.Unmarshal([]byte(out), &cards); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	var fixID string
+	for _, c := range cards {
+		if c.Title == "Fix flaky test" {
+			fixID = c.ID
+		}
+	}
+	if fixID == "" {
+		t.Fatalf("promoted card not found: %+v", cards)
+	}
+
+	executeCmd(t, "card", "move", fixID[:8], "Done")
+	out = executeCmd(t, "notes", "show", "retro", "--json")
+	var note noteJSON
+	if err := json.Unmarshal([]byte(out), &note); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if !strings.HasPrefix(note.Body, "- [x] Fix flaky test ^card-"+fixID[:8]) {
+		t.Errorf("expected checkbox ticked, got %q", note.Body)
+	}
+
+	out = executeCmd(t, "note", "backlinks", "retro")
+	if !strings.Contains(out, "[[card:"+fixID[:8]+"]]") {
+		t.Errorf("expected card backlink, got: %s", out)
+	}
+
+	if _, err := executeCmdErr(t, "note", "promote", "retro", "--board", "missing"); err == nil {
+		t.Error("expected error for missing board")
+	}
+	if _, err := executeCmdErr(t, "note", "promote", "retro", "--column", "Nope"); err == nil {
+		t.Error("expected error for missing column")
+	}
+}
+
 func TestWorkspaceAlias(t *testing.T) {
 	setupTestDB(t)
 
@@ -2134,6 +2584,61 @@ func TestPublishNoteDryRun(t *testing.T) {
 	}
 }
 
+func TestPublishNoteAttachments(t *testing.T) {
+	setupTestDB(t)
+
+	tmpDir := t.TempDir()
+	executeCmd(t, "publish", "setup", "site", "--engine", "jekyll", "--path", tmpDir, "--json")
+	executeCmd(t, "notes", "create", "Chart Post", "--body", "Results:\n\n![[chart.png]]")
+	src := filepath.Join(t.TempDir(), "chart.png")
+	os.WriteFile(src, []byte("png"), 0o644)
+	executeCmd(t, "note", "attach", "chart-post", src)
+
+	out := executeCmd(t, "publish", "chart-post")
+	if !strings.Contains(out, "Copied 1 attachment(s)") {
+		t.Errorf("expected copied attachment, got: %s", out)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "assets", "chart-post", "chart.png"))
+	if err != nil || string(data) != "png" {
+		t.Fatalf("expected asset copied, got %q, %v", data, err)
+	}
+	files, _ := filepath.Glob(filepath.Join(tmpDir, "_posts", "*.md"))
+	content, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(content), "![chart.png](/assets/chart-post/chart.png)") {
+		t.Errorf("expected image markdown in post: %s", content)
+	}
+
+	executeCmd(t, "notes", "create", "Other Post", "--body", "![[chart.png]]")
+	other := filepath.Join(t.TempDir(), "chart.png")
+	os.WriteFile(other, []byte("other png"), 0o644)
+	executeCmd(t, "note", "attach", "other-post", other)
+	executeCmd(t, "publish", "chart-post")
+	executeCmd(t, "publish", "other-post")
+	for slug, want := range map[string]string{"chart-post": "png", "other-post": "other png"} {
+		data, _ := os.ReadFile(filepath.Join(tmpDir, "assets", slug, "chart.png"))
+		if string(data) != want {
+			t.Errorf("expected %s's own chart.png, got %q", slug, data)
+		}
+	}
+}
+
+func TestPublishNoteProperties(t *testing.T) {
+	setupTestDB(t)
+
+	tmpDir := t.TempDir()
+	executeCmd(t, "publish", "setup", "site", "--engine", "jekyll", "--path", tmpDir, "--json")
+	executeCmd(t, "notes", "create", "Props Post", "--body", "---\nseries: [kb]\nstatus: draft\n---\nHello")
+
+	out := executeCmd(t, "publish", "props-post", "--dry-run", "--property", "series")
+
+	if !strings.Contains(out, "series: [kb]") {
+		t.Errorf("expected selected property in front matter, got: %s", out)
+	}
+	if strings.Contains(out, "status") {
+		t.Errorf("unselected property should not be published, got: %s", out)
+	}
+}
+
 func TestPublishNoteDryRunJSON(t *testing.T) {
 	setupTestDB(t)
 
@@ -2428,3 +2933,692 @@ func TestGraphWorkspaceNotFound(t *testing.T) {
 		t.Error("expected error for nonexistent workspace")
 	}
 }
+
+func TestGraphServeErrors(t *testing.T) {
+	setupTestDB(t)
+
+	if _, err := executeCmdErr(t, "graph", "serve", "--workspace", "nonexistent"); err == nil {
+		t.Error("expected error for nonexistent workspace")
+	}
+	if _, err := executeCmdErr(t, "graph", "serve", "--addr", "not-an-address"); err == nil {
+		t.Error("expected error for a bad address")
+	}
+}
+
+func TestTodayCreatesJournalNote(t *testing.T) {
+	setupTestDB(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KB_JOURNAL_WORKSPACE", "")
+
+	out := executeCmd(t, "today")
+	slug := time.Now().Format("2006-01-02")
+	if !strings.Contains(out, "Created journal note") || !strings.Contains(out, slug) {
+		t.Errorf("expected created message for %s, got: %s", slug, out)
+	}
+
+	out = executeCmd(t, "today")
+	if strings.Contains(out, "Created journal note") {
+		t.Errorf("expected existing note to be reused, got: %s", out)
+	}
+
+	out = executeCmd(t, "workspace", "list")
+	if !strings.Contains(out, "Journal") {
+		t.Errorf("expected Journal workspace, got: %s", out)
+	}
+}
+
+func TestJournalRelativeDayJSON(t *testing.T) {
+	setupTestDB(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	out := executeCmd(t, "journal", "yesterday", "--workspace", "Log", "--json")
+	var note noteJSON
+	if err := json.Unmarshal([]byte(out), &note); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	want := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	if note.Slug != want {
+		t.Errorf("slug = %q, want %q", note.Slug, want)
+	}
+
+	out = executeCmd(t, "journal", "2026-03-01", "--json")
+	if !strings.Contains(out, `"slug": "2026-03-01"`) {
+		t.Errorf("expected explicit date note, got: %s", out)
+	}
+
+	if _, err := executeCmdErr(t, "journal", "someday"); err == nil {
+		t.Error("expected error for invalid day")
+	}
+	if _, err := executeCmdErr(t, "journal", "yesterday", "tomorrow"); err == nil {
+		t.Error("expected error for two days")
+	}
+}
+
+func TestJournalNegativeOffset(t *testing.T) {
+	setupTestDB(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	for _, args := range [][]string{{"journal", "-1", "--json"}, {"journal", "--json", "-1"}, {"journal", "--json", "--", "-1"}} {
+		out := executeCmd(t, args...)
+		if !strings.Contains(out, `"slug": "`+yesterday+`"`) {
+			t.Errorf("%v: expected yesterday's note, got: %s", args, out)
+		}
+	}
+	if out := executeCmd(t, "journal", "--help"); !strings.Contains(out, "Open or create the journal note") {
+		t.Errorf("expected help, got: %s", out)
+	}
+}
+
+func TestNoteCreateFromTemplate(t *testing.T) {
+	setupTestDB(t)
+	t.Setenv("KB_TEMPLATE_DIR", t.TempDir())
+
+	out := executeCmd(t, "notes", "create", "Sprint 4 retro", "--template", "retro", "--json")
+	var note noteJSON
+	if err := json.Unmarshal([]byte(out), &note); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if !strings.Contains(note.Body, "Workspace: Default") || !strings.Contains(note.Body, "## What went well") {
+		t.Errorf("expected rendered retro template, got %q", note.Body)
+	}
+	if !strings.Contains(note.Body, "Date: "+time.Now().Format("2006-01-02")) {
+		t.Errorf("expected today's date, got %q", note.Body)
+	}
+}
+
+func TestNoteCreateTemplatePrompts(t *testing.T) {
+	setupTestDB(t)
+	t.Setenv("KB_TEMPLATE_DIR", t.TempDir())
+
+	rootCmd.SetIn(strings.NewReader("Ana, Bo\n"))
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+
+	out := executeCmd(t, "notes", "create", "Weekly sync", "--template", "meeting")
+	if !strings.Contains(out, "Attendees: ") {
+		t.Errorf("expected prompt, got: %s", out)
+	}
+	out = executeCmd(t, "notes", "show", "weekly-sync")
+	if !strings.Contains(out, "Attendees: Ana, Bo") {
+		t.Errorf("expected prompt answer in body, got: %s", out)
+	}
+
+	executeCmd(t, "notes", "create", "ADR 7", "--template", "adr", "--var", "Status=Accepted")
+	out = executeCmd(t, "notes", "show", "adr-7")
+	if !strings.Contains(out, "Status: Accepted") {
+		t.Errorf("expected --var answer in body, got: %s", out)
+	}
+}
+
+func TestNoteCreateTemplateErrors(t *testing.T) {
+	setupTestDB(t)
+	t.Setenv("KB_TEMPLATE_DIR", t.TempDir())
+
+	if _, err := executeCmdErr(t, "notes", "create", "X", "--template", "nope"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+	if _, err := executeCmdErr(t, "notes", "create", "Y", "--template", "retro", "--body", "hi"); err == nil {
+		t.Error("expected error combining --body and --template")
+	}
+}
+
+func TestNoteTemplatesList(t *testing.T) {
+	setupTestDB(t)
+	dir := t.TempDir()
+	t.Setenv("KB_TEMPLATE_DIR", dir)
+	os.WriteFile(filepath.Join(dir, "standup.md"), []byte("Blockers: {{prompt:Blockers}}"), 0o644)
+
+	out := executeCmd(t, "notes", "templates")
+	for _, name := range []string{"meeting", "adr", "retro", "1on1", "standup", "Blockers"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("expected %q in template list, got: %s", name, out)
+		}
+	}
+}
+
+func TestTaskCommands(t *testing.T) {
+	setupTestDB(t)
+
+	executeCmd(t, "notes", "create", "Launch", "--tags", "work/launch", "--body",
+		"# Prep\n- [ ] Write post @due(2020-01-01) !high\n- [x] Book room")
+	executeCmd(t, "notes", "create", "Errands", "--body", "- [ ] Buy milk")
+
+	out := executeCmd(t, "tasks")
+	for _, want := range []string{
+		"Errands (errands)\n  [ ] ",
+		"Launch (launch)\n",
+		"Write post  !high  due 2020-01-01 (overdue)  — Prep\n",
+		"[x] ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+
+	out = executeCmd(t, "tasks", "--open", "--tag", "work", "--json")
+	var tasks []taskJSON
+	if err := json.Unmarshal([]byte(out), &tasks); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(tasks) != 1 || tasks[0].Text != "Write post" || tasks[0].Note != "launch" || tasks[0].Line != 2 {
+		t.Fatalf("unexpected tasks: %+v", tasks)
+	}
+
+	out = executeCmd(t, "tasks", "toggle", tasks[0].ID[:8])
+	if !strings.Contains(out, "Completed: Write post (launch)") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	out = executeCmd(t, "notes", "show", "launch", "--json")
+	var note noteJSON
+	if err := json.Unmarshal([]byte(out), &note); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if !strings.Contains(note.Body, "- [x] Write post @due(2020-01-01) !high") {
+		t.Errorf("checkbox not toggled: %q", note.Body)
+	}
+
+	out = executeCmd(t, "tasks", "--open")
+	if strings.Contains(out, "Write post") || !strings.Contains(out, "Buy milk") {
+		t.Errorf("unexpected open tasks:\n%s", out)
+	}
+
+	if _, err := executeCmdErr(t, "tasks", "--workspace", "nope"); err == nil {
+		t.Error("expected error for unknown workspace")
+	}
+	if _, err := executeCmdErr(t, "tasks", "toggle", "zzzzzz"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+func TestBoardExportImport(t *testing.T) {
+	setupTestDB(t)
+	os.Setenv("KB_BOARD", "sprint-1")
+	defer os.Unsetenv("KB_BOARD")
+	createTestBoard(t, "sprint-1")
+
+	out := executeCmd(t, "cards", "add", "Fix login", "-p", "urgent", "-l", "bug,auth", "-e", "JIRA-7",
+		"-c", "Todo", "-d", "Steps\n\n1. Log in", "--json")
+	var card cardJSON
+	if err := json.Unmarshal([]byte(out), &card); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	executeCmd(t, "cards", "add", "Shipped", "-c", "Done")
+	out = executeCmd(t, "cards", "add", "Dropped", "--json")
+	var dropped cardJSON
+	json.Unmarshal([]byte(out), &dropped)
+	executeCmd(t, "cards", "archive", dropped.ID[:8])
+
+	dir := t.TempDir()
+	shot := filepath.Join(dir, "src", "trace.png")
+	os.MkdirAll(filepath.Dir(shot), 0o755)
+	os.WriteFile(shot, []byte("png"), 0o644)
+	executeCmd(t, "card", "attach", card.ID[:8], shot)
+
+	out = executeCmd(t, "board", "export", "sprint-1", "--format", "md-kanban")
+	for _, want := range []string{
+		"kanban-plugin: basic",
+		"## Todo\n\n- [ ] Fix login #bug #auth !urgent [external-id:: JIRA-7]\n\tSteps\n\n\t1. Log in\n\t![[trace.png]]\n",
+		"## Done\n\n**Complete**\n- [x] Shipped\n",
+		"## Archive\n\n- [ ] Dropped\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in export:\n%s", want, out)
+		}
+	}
+
+	if _, err := executeCmdErr(t, "board", "export", "sprint-1", "--format", "csv"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+
+	file := filepath.Join(dir, "out", "sprint.md")
+	os.MkdirAll(filepath.Dir(file), 0o755)
+	out = executeCmd(t, "board", "export", "sprint-1", "-o", file)
+	if !strings.Contains(out, "Copied 1 attachment(s)") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out", "trace.png")); err != nil {
+		t.Errorf("expected attachment next to export: %v", err)
+	}
+
+	out = executeCmd(t, "board", "import", file)
+	if !strings.Contains(out, `Imported board "sprint" with 5 column(s), 2 card(s) and 1 archived card(s)`) ||
+		!strings.Contains(out, "Attached 1 file(s)") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	exported := executeCmd(t, "board", "export", "sprint")
+	original := executeCmd(t, "board", "export", "sprint-1")
+	if exported != original {
+		t.Errorf("round trip differs:\n%s\n---\n%s", exported, original)
+	}
+
+	if _, err := executeCmdErr(t, "board", "import", file); err == nil {
+		t.Error("expected error importing a board name twice")
+	}
+}
+
+func TestBoardSnapshot(t *testing.T) {
+	setupTestDB(t)
+	os.Setenv("KB_BOARD", "sprint-1")
+	defer os.Unsetenv("KB_BOARD")
+	createTestBoard(t, "sprint-1")
+
+	executeCmd(t, "cards", "add", "Fix login", "-p", "urgent", "-l", "bug", "-e", "JIRA-7", "-c", "Todo")
+
+	out := executeCmd(t, "board", "snapshot", "sprint-1", "--json")
+	var note noteJSON
+	if err := json.Unmarshal([]byte(out), &note); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if !strings.HasPrefix(note.Slug, "sprint-1-snapshot-") || !strings.Contains(note.Body, "Fix login]] · urgent · `bug` · JIRA-7") {
+		t.Errorf("unexpected snapshot note: %+v", note)
+	}
+
+	out = executeCmd(t, "notes", "--tag", "sprint-1")
+	if !strings.Contains(out, note.Slug) {
+		t.Errorf("expected snapshot tagged with the board name:\n%s", out)
+	}
+
+	executeCmd(t, "notes", "create", "Retro")
+	out = executeCmd(t, "board", "snapshot", "sprint-1", "--note", "retro")
+	if !strings.Contains(out, `Appended snapshot of "sprint-1" to note "Retro"`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	if _, err := executeCmdErr(t, "board", "snapshot", "missing"); err == nil {
+		t.Error("expected error for unknown board")
+	}
+}
+
+func TestNoteRenderQueries(t *testing.T) {
+	setupTestDB(t)
+	os.Setenv("KB_BOARD", "sprint-1")
+	defer os.Unsetenv("KB_BOARD")
+	createTestBoard(t, "sprint-1")
+
+	executeCmd(t, "cards", "add", "Fix login", "-p", "urgent", "-c", "Todo")
+	executeCmd(t, "cards", "add", "Polish", "-p", "low", "-c", "Todo")
+	body := "Urgent work:\n\n```kb-query\ncards board:sprint-1 priority:urgent\n```\n"
+	executeCmd(t, "notes", "create", "Dashboard", "--body", body)
+
+	out := executeCmd(t, "notes", "render", "dashboard")
+	if !strings.Contains(out, "Fix login]] | sprint-1 | Todo | urgent |") || strings.Contains(out, "Polish") {
+		t.Errorf("unexpected rendered note:\n%s", out)
+	}
+
+	tmpDir := t.TempDir()
+	executeCmd(t, "publish", "setup", "site", "--engine", "jekyll", "--path", tmpDir)
+	out = executeCmd(t, "publish", "dashboard", "--dry-run")
+	if !strings.Contains(out, "```kb-query") {
+		t.Errorf("queries should be published as written by default:\n%s", out)
+	}
+	out = executeCmd(t, "publish", "dashboard", "--dry-run", "--freeze-queries")
+	if !strings.Contains(out, "| Fix login | sprint-1 | Todo | urgent |") || strings.Contains(out, "kb-query") {
+		t.Errorf("expected frozen query results:\n%s", out)
+	}
+}
+
+func TestReviewCommands(t *testing.T) {
+	setupTestDB(t)
+
+	out := executeCmd(t, "review")
+	if !strings.Contains(out, "No flashcards due.") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	executeCmd(t, "notes", "create", "Go", "--body", "Goroutine? :: Lightweight thread\n\nChannel?\n?\nTyped pipe")
+	out = executeCmd(t, "review", "--json")
+	var cards []flashcardJSON
+	if err := json.Unmarshal([]byte(out), &cards); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(cards) != 2 || cards[0].Note != "go" || cards[1].Front != "Channel?" || cards[1].Line != 3 {
+		t.Errorf("unexpected due cards: %+v", cards)
+	}
+
+	db.ReviewFlashcard(cards[0].ID, 5, time.Now())
+	out = executeCmd(t, "review", "stats")
+	for _, want := range []string{"Flashcards:     2 (1 new)", "Due today:      1", "Due tomorrow:   1", "Streak:         1 day(s)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestGraphCardsAndBoards(t *testing.T) {
+	setupTestDB(t)
+	os.Setenv("KB_BOARD", "sprint-1")
+	defer os.Unsetenv("KB_BOARD")
+	createTestBoard(t, "sprint-1")
+
+	executeCmd(t, "note", "create", "Design", "--body", "Tracked in [[card:Fix login]] on [[board:sprint-1]]")
+	out := executeCmd(t, "cards", "add", "Fix login", "-c", "Todo", "--json")
+	var card cardJSON
+	if err := json.Unmarshal([]byte(out), &card); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	executeCmd(t, "cards", "edit", card.ID[:8], "-d", "Spec in [[design]]")
+
+	out = executeCmd(t, "graph", "--json")
+	var data graph.GraphData
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	types := make(map[string]int)
+	for _, n := range data.Nodes {
+		types[n.Type]++
+	}
+	edgeTypes := make(map[string]int)
+	for _, e := range data.Edges {
+		edgeTypes[e.Type]++
+	}
+	if types["note"] != 1 || types["card"] != 1 || types["board"] != 1 {
+		t.Errorf("unexpected node types: %v", types)
+	}
+	if edgeTypes["member"] != 1 || edgeTypes["link"] != 3 {
+		t.Errorf("unexpected edge types: %v", edgeTypes)
+	}
+
+	out = executeCmd(t, "graph")
+	if !strings.Contains(out, "Nodes:   3 (1 notes, 1 cards, 1 boards)") {
+		t.Errorf("expected a per-type breakdown:\n%s", out)
+	}
+
+	out = executeCmd(t, "graph", "--types", "note", "--json")
+	data = graph.GraphData{}
+	json.Unmarshal([]byte(out), &data)
+	if len(data.Nodes) != 1 || len(data.Edges) != 0 {
+		t.Errorf("expected notes only, got %+v", data)
+	}
+
+	if _, err := executeCmdErr(t, "graph", "--types", "tag"); err == nil {
+		t.Error("expected error for unknown node type")
+	}
+
+	executeCmd(t, "note", "create", "Release", "--body", "[[implements>card:"+card.ID[:6]+"]]")
+	out = executeCmd(t, "graph", "--relation", "implements", "--json")
+	data = graph.GraphData{}
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(data.Edges) != 1 || data.Edges[0].Target != card.ID {
+		t.Errorf("expected a short card ID to resolve, got %+v", data.Edges)
+	}
+}
+
+func TestGraphAnalyzeAndPath(t *testing.T) {
+	setupTestDB(t)
+	executeCmd(t, "note", "create", "Gamma")
+	executeCmd(t, "note", "create", "Beta", "--body", "See [[gamma]]")
+	executeCmd(t, "note", "create", "Alpha", "--body", "See [[beta]]")
+	executeCmd(t, "note", "create", "Loner")
+
+	out := executeCmd(t, "graph", "analyze", "--json")
+	var analysis graph.Analysis
+	if err := json.Unmarshal([]byte(out), &analysis); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if analysis.Nodes != 4 || analysis.Edges != 2 {
+		t.Errorf("unexpected counts: %+v", analysis)
+	}
+	if len(analysis.Bridges) != 1 || analysis.Bridges[0].Label != "Beta" {
+		t.Errorf("expected Beta as the bridge, got %+v", analysis.Bridges)
+	}
+	if len(analysis.Components) != 2 || analysis.Components[0].Size != 3 {
+		t.Errorf("unexpected components: %+v", analysis.Components)
+	}
+
+	out = executeCmd(t, "graph", "analyze", "--limit", "1")
+	for _, want := range []string{"Hubs by degree", "1. Beta", "Bridges by betweenness", "Communities: 2", "Components: 2", "1 node(s): Loner"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	out = executeCmd(t, "graph", "path", "gamma", "alpha")
+	if !strings.Contains(out, "Gamma\n  ← Beta\n  ← Alpha\n") || !strings.Contains(out, "2 link(s)") {
+		t.Errorf("unexpected path:\n%s", out)
+	}
+
+	out = executeCmd(t, "graph", "path", "alpha", "beta", "--json")
+	var path []graph.PathStep
+	if err := json.Unmarshal([]byte(out), &path); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(path) != 2 || path[1].Label != "Beta" || !path[1].Forward {
+		t.Errorf("unexpected path: %+v", path)
+	}
+
+	if _, err := executeCmdErr(t, "graph", "path", "alpha", "loner"); err == nil {
+		t.Error("expected error when no path exists")
+	}
+}
+
+func TestGraphOfflineOutput(t *testing.T) {
+	setupTestDB(t)
+	executeCmd(t, "note", "create", "Target")
+	executeCmd(t, "note", "create", "Source", "--body", "See [[target]]")
+
+	dir := t.TempDir()
+	svgPath := filepath.Join(dir, "graph.svg")
+	htmlPath := filepath.Join(dir, "graph.html")
+	out := executeCmd(t, "graph", "--svg", svgPath, "--html", htmlPath, "--seed", "7")
+	if !strings.Contains(out, "Graph written to "+svgPath) || !strings.Contains(out, "Graph written to "+htmlPath) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	svg, err := os.ReadFile(svgPath)
+	if err != nil {
+		t.Fatalf("reading SVG: %v", err)
+	}
+	if !strings.Contains(string(svg), ">Source</text>") || strings.Count(string(svg), "<line ") != 1 {
+		t.Errorf("unexpected SVG:\n%s", svg)
+	}
+	page, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("reading HTML: %v", err)
+	}
+	if strings.Contains(string(page), "https://") {
+		t.Error("offline HTML should not load anything from the web")
+	}
+
+	executeCmd(t, "graph", "--svg", svgPath, "--seed", "7")
+	again, _ := os.ReadFile(svgPath)
+	if string(again) != string(svg) {
+		t.Error("expected the same SVG for the same seed")
+	}
+}
+
+func TestGraphPageOffline(t *testing.T) {
+	data := &graph.GraphData{Nodes: []graph.Node{{ID: "n1", Label: "Note", Type: "note"}}}
+
+	page, err := graphPage(data, "kb", false, 1)
+	if err != nil {
+		t.Fatalf("graphPage: %v", err)
+	}
+	if strings.Contains(page, "https://") {
+		t.Error("--open should not load anything from the web without --cdn")
+	}
+
+	page, err = graphPage(data, "kb", true, 1)
+	if err != nil {
+		t.Fatalf("graphPage: %v", err)
+	}
+	if !strings.Contains(page, "d3js.org") {
+		t.Error("--cdn should load D3 from its CDN")
+	}
+}
+
+func TestGraphAround(t *testing.T) {
+	setupTestDB(t)
+	executeCmd(t, "note", "create", "Gamma")
+	executeCmd(t, "note", "create", "Beta", "--body", "See [[gamma]]")
+	executeCmd(t, "note", "create", "Alpha", "--body", "See [[beta]]")
+
+	out := executeCmd(t, "graph", "--around", "alpha", "--depth", "2")
+	want := "╭───────╮\n│ Alpha │\n╰─┬─────╯\n  └─→ Beta\n    └─→ Gamma\n"
+	if out != want {
+		t.Errorf("unexpected diagram:\n%s\nwant:\n%s", out, want)
+	}
+
+	out = executeCmd(t, "graph", "--around", "alpha", "--depth", "1", "--json")
+	var data graph.GraphData
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(data.Nodes) != 2 || len(data.Edges) != 1 {
+		t.Errorf("expected Alpha and Beta only, got %+v", data)
+	}
+
+	if _, err := executeCmdErr(t, "graph", "--around", "alpha", "--depth", "0"); err == nil {
+		t.Error("expected error for depth 0")
+	}
+	if _, err := executeCmdErr(t, "graph", "--around", "alpha", "--from", "beta"); err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("expected error for --around with --from, got %v", err)
+	}
+}
+
+func TestGraphRelation(t *testing.T) {
+	setupTestDB(t)
+	executeCmd(t, "note", "create", "ADR 3")
+	executeCmd(t, "note", "create", "ADR 4", "--body", "supersedes:: [[adr-3]]\nBackground in [[adr-3]].")
+	executeCmd(t, "note", "create", "Roadmap", "--body", "See [[adr-4]]")
+
+	out := executeCmd(t, "note", "backlinks", "adr-3")
+	if !strings.Contains(out, "[[adr-4]] (supersedes) supersedes:: [[adr-3]]") {
+		t.Errorf("expected the relation in backlinks, got:\n%s", out)
+	}
+	out = executeCmd(t, "note", "backlinks", "adr-3", "--json")
+	var links []backlinkJSON
+	if err := json.Unmarshal([]byte(out), &links); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(links) != 2 {
+		t.Fatalf("expected a plain and a typed backlink, got %+v", links)
+	}
+
+	out = executeCmd(t, "graph", "--relation", "supersedes", "--json")
+	var data graph.GraphData
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(data.Nodes) != 2 || len(data.Edges) != 1 || data.Edges[0].Relation != "supersedes" {
+		t.Errorf("expected the two ADRs joined by supersedes, got %+v", data)
+	}
+
+	out = executeCmd(t, "graph")
+	if !strings.Contains(out, "Relations: supersedes") {
+		t.Errorf("expected relations in the summary, got:\n%s", out)
+	}
+
+	executeCmd(t, "note", "create", "ADR 5", "--body", "part of:: [[roadmap]]")
+	out = executeCmd(t, "graph", "--relation", "Part of", "--json")
+	data = graph.GraphData{}
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(data.Edges) != 1 || data.Edges[0].Relation != "part-of" {
+		t.Errorf("expected one part-of link, got %+v", data.Edges)
+	}
+
+	out = executeCmd(t, "graph", "--around", "roadmap", "--relation", "supersedes")
+	if !strings.Contains(out, "Roadmap") || strings.Contains(out, "ADR 4") {
+		t.Errorf("expected the note alone without supersedes links, got:\n%s", out)
+	}
+}
+
+func TestGraphFilters(t *testing.T) {
+	setupTestDB(t)
+	executeCmd(t, "note", "create", "Hub", "--tags", "area/backend")
+	executeCmd(t, "note", "create", "Spoke", "--tags", "area/frontend,draft", "--body", "See [[hub]]")
+	executeCmd(t, "note", "create", "Leaf", "--tags", "ops", "--body", "Part of [[spoke]]")
+	executeCmd(t, "note", "create", "Loner")
+
+	graphNodes := func(args ...string) []string {
+		t.Helper()
+		out := executeCmd(t, append([]string{"graph", "--json"}, args...)...)
+		var data graph.GraphData
+		if err := json.Unmarshal([]byte(out), &data); err != nil {
+			t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+		}
+		var labels []string
+		for _, n := range data.Nodes {
+			labels = append(labels, n.Label)
+		}
+		sort.Strings(labels)
+		return labels
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--tag", "area"}, "Hub,Spoke"},
+		{[]string{"--exclude-tag", "draft,ops"}, "Hub,Loner"},
+		{[]string{"--from", "hub", "--depth", "1"}, "Hub,Spoke"},
+		{[]string{"--from", "hub", "--depth", "4"}, "Hub,Leaf,Spoke"},
+		{[]string{"--from", "hub", "--tag", "ops"}, "Hub"},
+		{[]string{"--min-connections", "1"}, "Hub,Leaf,Spoke"},
+		{[]string{"--min-connections", "2"}, "Spoke"},
+		{[]string{"--since", time.Now().Format(time.DateOnly)}, "Hub,Leaf,Loner,Spoke"},
+		{[]string{"--until", time.Now().AddDate(0, 0, -1).Format(time.DateOnly), "--time", "created"}, ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(graphNodes(tt.args...), ","); got != tt.want {
+			t.Errorf("graph %v = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	out := executeCmd(t, "graph", "--tag", "area")
+	if !strings.Contains(out, "Nodes:   2") {
+		t.Errorf("expected the summary to respect filters, got:\n%s", out)
+	}
+	if _, err := executeCmdErr(t, "graph", "--since", "last week"); err == nil {
+		t.Error("expected error for a bad --since")
+	}
+	if _, err := executeCmdErr(t, "graph", "--since", "2026-01-01", "--time", "touched"); err == nil {
+		t.Error("expected error for a bad --time")
+	}
+}
+
+func TestGraphExportFormats(t *testing.T) {
+	setupTestDB(t)
+	executeCmd(t, "note", "create", "Target", "--tags", "go")
+	executeCmd(t, "note", "create", "Source", "--body", "See [[target]]")
+
+	out := executeCmd(t, "graph", "--format", "dot")
+	if !strings.Contains(out, "digraph \"kb Knowledge Graph\" {") || !strings.Contains(out, `tags="go"`) || !strings.Contains(out, `context="See [[target]]"`) {
+		t.Errorf("unexpected DOT output:\n%s", out)
+	}
+	if !strings.Contains(out, `workspace="`) {
+		t.Errorf("expected the workspace name in:\n%s", out)
+	}
+
+	out = executeCmd(t, "graph", "--format", "mermaid")
+	if !strings.Contains(out, "flowchart LR") || !strings.Contains(out, `-->|"See [[target]]"|`) {
+		t.Errorf("unexpected Mermaid output:\n%s", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "kb.gexf")
+	out = executeCmd(t, "graph", "--format", "gexf", "--output", path)
+	if !strings.Contains(out, "Graph written to "+path) {
+		t.Errorf("unexpected output: %s", out)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(written), `<gexf xmlns="http://gexf.net/1.3"`) {
+		t.Errorf("expected a GEXF file, got %v: %s", err, written)
+	}
+
+	path = filepath.Join(t.TempDir(), "kb.graphml")
+	executeCmd(t, "graph", "--format", "graphml", "-o", path)
+	if written, err := os.ReadFile(path); err != nil || !strings.Contains(string(written), "<graphml") {
+		t.Errorf("expected a GraphML file through -o, got %v: %s", err, written)
+	}
+
+	if _, err := executeCmdErr(t, "graph", "--format", "csv"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if _, err := executeCmdErr(t, "graph", "--format", "dot", "stray.dot"); err == nil {
+		t.Error("expected error for a positional argument")
+	}
+}
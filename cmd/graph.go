@@ -1,43 +1,115 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/jeryldev/kb/internal/graph"
+	"github.com/jeryldev/kb/internal/query"
 	"github.com/spf13/cobra"
 )
 
 var graphCmd = &cobra.Command{
 	Use:   "graph",
 	Short: "Visualize the knowledge graph",
+	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		workspace, _ := cmd.Flags().GetString("workspace")
 		open, _ := cmd.Flags().GetBool("open")
+		cdn, _ := cmd.Flags().GetBool("cdn")
+		svgPath, _ := cmd.Flags().GetString("svg")
+		htmlPath, _ := cmd.Flags().GetString("html")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		around, _ := cmd.Flags().GetString("around")
+		depth, _ := cmd.Flags().GetInt("depth")
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
 
-		wsID := ""
-		if workspace != "" {
-			ws, err := resolveWorkspace(workspace)
+		var center string
+		if around != "" {
+			// --from would cut the graph down to a neighborhood of its own
+			// before --around does, so the two are not combined.
+			if from, _ := cmd.Flags().GetString("from"); from != "" {
+				return fmt.Errorf("--around and --from cannot be used together")
+			}
+			note, err := resolveNote(around)
 			if err != nil {
 				return err
 			}
-			wsID = ws.ID
+			center = note.ID
 		}
 
-		data, err := graph.BuildGraph(db, wsID)
+		data, err := buildGraph(cmd, center)
 		if err != nil {
 			return err
 		}
+		if center != "" {
+			if data, err = data.Neighborhood(center, depth); err != nil {
+				return err
+			}
+		}
 
 		if jsonOutput {
 			return printJSON(data)
 		}
 
+		title := "kb Knowledge Graph"
+		if workspace != "" {
+			title = fmt.Sprintf("kb Graph — %s", workspace)
+		}
+		if format != "" {
+			text, err := graph.Export(data, format, title)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				fmt.Fprint(cmd.OutOrStdout(), text)
+				return nil
+			}
+			if err := os.WriteFile(output, []byte(text), 0644); err != nil {
+				return fmt.Errorf("writing graph file: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Graph written to %s\n", output)
+			return nil
+		}
+		if svgPath != "" || htmlPath != "" {
+			if svgPath != "" {
+				if err := os.WriteFile(svgPath, []byte(graph.GenerateSVG(data, title, seed)), 0644); err != nil {
+					return fmt.Errorf("writing SVG: %w", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Graph written to %s\n", svgPath)
+			}
+			if htmlPath != "" {
+				page, err := graph.GenerateOfflineHTML(data, title, seed)
+				if err != nil {
+					return fmt.Errorf("generating HTML: %w", err)
+				}
+				if err := os.WriteFile(htmlPath, []byte(page), 0644); err != nil {
+					return fmt.Errorf("writing HTML: %w", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Graph written to %s\n", htmlPath)
+			}
+			return nil
+		}
+
 		if open {
-			return openGraphHTML(cmd, data, workspace)
+			return openGraphHTML(cmd, data, title, cdn, seed)
+		}
+
+		if center != "" {
+			fmt.Fprint(cmd.OutOrStdout(), graph.RenderDiagram(data.Diagram(center)))
+			return nil
 		}
 
 		nodes, edges, orphans := data.Stats()
@@ -46,7 +118,10 @@ var graphCmd = &cobra.Command{
 		if workspace != "" {
 			fmt.Fprintf(out, "Workspace: %s\n", workspace)
 		}
-		fmt.Fprintf(out, "\n  Nodes:   %d\n  Edges:   %d\n  Orphans: %d\n", nodes, edges, orphans)
+		fmt.Fprintf(out, "\n  Nodes:   %d%s\n  Edges:   %d\n  Orphans: %d\n", nodes, typeBreakdown(data), edges, orphans)
+		if relations := data.Relations(); len(relations) > 0 {
+			fmt.Fprintf(out, "  Relations: %s\n", strings.Join(relations, ", "))
+		}
 		if nodes > 0 {
 			fmt.Fprintf(out, "\nOpen interactive visualization: kb graph --open\n")
 		}
@@ -54,13 +129,289 @@ var graphCmd = &cobra.Command{
 	},
 }
 
-func openGraphHTML(cmd *cobra.Command, data *graph.GraphData, workspace string) error {
-	title := "kb Knowledge Graph"
+var graphAnalyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Find hubs, bridges, communities and components",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		data, err := buildGraph(cmd)
+		if err != nil {
+			return err
+		}
+		analysis := data.Analyze(limit)
+
+		if jsonOutput {
+			return printJSON(analysis)
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Knowledge Graph Analysis\n")
+		fmt.Fprintf(out, "\n  Nodes: %d\n  Edges: %d\n", analysis.Nodes, analysis.Edges)
+		printRanking(out, "Hubs by degree", analysis.ByDegree, "%.0f")
+		printRanking(out, "Hubs by PageRank", analysis.ByPageRank, "%.3f")
+		printRanking(out, "Bridges by betweenness", analysis.Bridges, "%.3f")
+		printGroups(out, data, "Communities", analysis.Communities)
+		printGroups(out, data, "Components", analysis.Components)
+		return nil
+	},
+}
+
+var graphPathCmd = &cobra.Command{
+	Use:   "path <from> <to>",
+	Short: "Show the shortest chain of links between two notes",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+		to, err := resolveNote(args[1])
+		if err != nil {
+			return err
+		}
+
+		data, err := buildGraph(cmd)
+		if err != nil {
+			return err
+		}
+		path, err := data.ShortestPath(from.ID, to.ID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(path)
+		}
+
+		out := cmd.OutOrStdout()
+		for i, step := range path {
+			arrow := "→"
+			if !step.Forward {
+				arrow = "←"
+			}
+			if i == 0 {
+				fmt.Fprintf(out, "%s\n", step.Name())
+				continue
+			}
+			fmt.Fprintf(out, "  %s %s\n", arrow, step.Name())
+		}
+		fmt.Fprintf(out, "\n%d link(s)\n", len(path)-1)
+		return nil
+	},
+}
+
+var graphServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a live graph that updates as notes change",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		open, _ := cmd.Flags().GetBool("open")
+		workspace, _ := cmd.Flags().GetString("workspace")
+
+		// Check the flags before listening.
+		if _, err := buildGraph(cmd); err != nil {
+			return err
+		}
+
+		title := "kb Knowledge Graph"
+		if workspace != "" {
+			title = fmt.Sprintf("kb Graph — %s", workspace)
+		}
+		srv := graph.NewServer(db, title, func() (*graph.GraphData, error) { return buildGraph(cmd) })
+		srv.Expand = func(body string) string { return query.Expand(db, body) }
+		srv.AnyHost = !graph.LoopbackHost(addr)
+
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("listening on %s: %w", addr, err)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		httpSrv := &http.Server{
+			Handler:     srv.Handler(),
+			BaseContext: func(net.Listener) context.Context { return ctx },
+		}
+		go srv.Watch(ctx, time.Second)
+		go func() {
+			<-ctx.Done()
+			httpSrv.Close()
+		}()
+
+		url := "http://" + ln.Addr().String()
+		out := cmd.OutOrStdout()
+		if srv.AnyHost {
+			fmt.Fprintf(out, "Warning: %s is reachable from other machines\n", addr)
+		}
+		fmt.Fprintf(out, "Serving the graph at %s (Ctrl+C to stop)\n", url)
+		if open {
+			if err := openBrowser(url); err != nil {
+				fmt.Fprintf(out, "Could not open a browser: %v\n", err)
+			}
+		}
+
+		if err := httpSrv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+// buildGraph builds the graph selected by the workspace, types, relation,
+// tag, time, from and connection flags. The nodes in keep stay in the
+// graph whatever the filters say.
+func buildGraph(cmd *cobra.Command, keep ...string) (*graph.GraphData, error) {
+	workspace, _ := cmd.Flags().GetString("workspace")
+	typesFlag, _ := cmd.Flags().GetString("types")
+	relationFlag, _ := cmd.Flags().GetString("relation")
+	tagFlag, _ := cmd.Flags().GetString("tag")
+	excludeFlag, _ := cmd.Flags().GetString("exclude-tag")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	untilFlag, _ := cmd.Flags().GetString("until")
+	timeField, _ := cmd.Flags().GetString("time")
+	from, _ := cmd.Flags().GetString("from")
+	depth, _ := cmd.Flags().GetInt("depth")
+	minConnections, _ := cmd.Flags().GetInt("min-connections")
+
+	types, err := graph.ParseTypes(typesFlag)
+	if err != nil {
+		return nil, err
+	}
+	since, err := parseGraphTime("since", sinceFlag, false)
+	if err != nil {
+		return nil, err
+	}
+	until, err := parseGraphTime("until", untilFlag, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var root string
+	if from != "" {
+		note, err := resolveNote(from)
+		if err != nil {
+			return nil, err
+		}
+		root = note.ID
+		keep = append(keep, root)
+	}
+
+	wsID := ""
 	if workspace != "" {
-		title = fmt.Sprintf("kb Graph — %s", workspace)
+		ws, err := resolveWorkspace(workspace)
+		if err != nil {
+			return nil, err
+		}
+		wsID = ws.ID
+	}
+	data, err := graph.BuildGraph(db, wsID, types...)
+	if err != nil {
+		return nil, err
+	}
+	if relationFlag != "" {
+		data = data.FilterRelations(strings.Split(relationFlag, ","), keep...)
+	}
+	if tagFlag != "" || excludeFlag != "" {
+		data = data.FilterTags(splitList(tagFlag), splitList(excludeFlag), keep...)
+	}
+	if !since.IsZero() || !until.IsZero() {
+		if data, err = data.FilterTime(timeField, since, until, keep...); err != nil {
+			return nil, err
+		}
 	}
+	if root != "" {
+		if data, err = data.Neighborhood(root, depth); err != nil {
+			return nil, err
+		}
+	}
+	if minConnections > 0 {
+		data = data.MinConnections(minConnections, keep...)
+	}
+	return data, nil
+}
 
-	html, err := graph.GenerateHTML(data, title)
+// parseGraphTime reads a --since or --until value, a date or an RFC 3339
+// time. A date given to --until covers that whole day.
+func parseGraphTime(flag, value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q: use YYYY-MM-DD or an RFC 3339 time", flag, value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// splitList splits a comma-separated flag, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func printRanking(out io.Writer, title string, ranked []graph.Ranked, format string) {
+	fmt.Fprintf(out, "\n%s\n", title)
+	if len(ranked) == 0 {
+		fmt.Fprintf(out, "  (none)\n")
+		return
+	}
+	for i, r := range ranked {
+		fmt.Fprintf(out, "  %2d. %-40s %s\n", i+1, truncateStr(graph.Node{Label: r.Label, Type: r.Type}.Name(), 40), fmt.Sprintf(format, r.Score))
+	}
+}
+
+// printGroups lists each group's size and first few members.
+func printGroups(out io.Writer, data *graph.GraphData, title string, groups []graph.Group) {
+	const shown = 5
+	fmt.Fprintf(out, "\n%s: %d\n", title, len(groups))
+	for i, grp := range groups {
+		var names []string
+		for _, id := range grp.Nodes[:min(len(grp.Nodes), shown)] {
+			n, _ := data.Node(id)
+			names = append(names, n.Label)
+		}
+		if len(grp.Nodes) > shown {
+			names = append(names, fmt.Sprintf("+%d more", len(grp.Nodes)-shown))
+		}
+		fmt.Fprintf(out, "  %2d. %d node(s): %s\n", i+1, grp.Size, strings.Join(names, ", "))
+	}
+}
+
+// typeBreakdown counts the nodes of each type, as " (3 notes, 2 cards)",
+// when the graph holds more than one type.
+func typeBreakdown(data *graph.GraphData) string {
+	counts := make(map[string]int)
+	for _, n := range data.Nodes {
+		counts[n.Type]++
+	}
+	if len(counts) < 2 {
+		return ""
+	}
+	var parts []string
+	for _, t := range graph.Types {
+		if counts[t] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %ss", counts[t], t))
+		}
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func openGraphHTML(cmd *cobra.Command, data *graph.GraphData, title string, cdn bool, seed int64) error {
+	html, err := graphPage(data, title, cdn, seed)
 	if err != nil {
 		return fmt.Errorf("generating HTML: %w", err)
 	}
@@ -72,7 +423,20 @@ func openGraphHTML(cmd *cobra.Command, data *graph.GraphData, workspace string)
 	}
 
 	fmt.Fprintf(cmd.OutOrStdout(), "Graph written to %s\n", outPath)
+	return openBrowser(outPath)
+}
 
+// graphPage renders the page --open shows. It is laid out in kb and makes
+// no network requests unless cdn asks for the D3 view instead.
+func graphPage(data *graph.GraphData, title string, cdn bool, seed int64) (string, error) {
+	if cdn {
+		return graph.GenerateHTML(data, title)
+	}
+	return graph.GenerateOfflineHTML(data, title, seed)
+}
+
+// openBrowser opens a file or URL with the system's default handler.
+func openBrowser(target string) error {
 	var openCmd string
 	switch runtime.GOOS {
 	case "darwin":
@@ -83,11 +447,35 @@ func openGraphHTML(cmd *cobra.Command, data *graph.GraphData, workspace string)
 		openCmd = "open"
 	}
 
-	return exec.Command(openCmd, outPath).Start()
+	return exec.Command(openCmd, target).Start()
 }
 
 func init() {
-	graphCmd.Flags().StringP("workspace", "w", "", "Scope graph to a workspace")
-	graphCmd.Flags().BoolP("open", "o", false, "Open interactive graph in browser")
+	graphCmd.PersistentFlags().StringP("workspace", "w", "", "Scope graph to a workspace")
+	graphCmd.PersistentFlags().String("types", "note,card,board", "Node types to include (comma-separated: note, card, board)")
+	graphCmd.PersistentFlags().String("relation", "", "Only include links with these relations (comma-separated, e.g. supersedes)")
+	graphCmd.PersistentFlags().String("tag", "", "Only include nodes with one of these tags or card labels (comma-separated)")
+	graphCmd.PersistentFlags().String("exclude-tag", "", "Leave out nodes with any of these tags or card labels (comma-separated)")
+	graphCmd.PersistentFlags().String("since", "", "Only include nodes created or updated on or after this date (YYYY-MM-DD)")
+	graphCmd.PersistentFlags().String("until", "", "Only include nodes created or updated on or before this date (YYYY-MM-DD)")
+	graphCmd.PersistentFlags().String("time", graph.TimeUpdated, "Time --since and --until compare: created or updated")
+	graphCmd.PersistentFlags().String("from", "", "Only include the neighborhood of a note (slug or ID), --depth links deep")
+	graphCmd.PersistentFlags().Int("depth", 2, "Links to follow from the --from or --around note")
+	graphCmd.PersistentFlags().Int("min-connections", 0, "Only include nodes with at least this many connections")
+	graphCmd.Flags().Bool("open", false, "Open interactive graph in browser")
+	graphCmd.Flags().Bool("cdn", false, "With --open, show the D3 view, which loads D3.js from the web")
+	graphCmd.Flags().String("svg", "", "Write the graph as a self-contained SVG file")
+	graphCmd.Flags().String("html", "", "Write the graph as a self-contained HTML page")
+	graphCmd.Flags().Int64("seed", 1, "Seed for the graph layout")
+	graphCmd.Flags().String("format", "", "Export format: graphml, gexf, dot or mermaid")
+	graphCmd.Flags().StringP("output", "o", "", "With --format, write to a file instead of stdout")
+	graphCmd.Flags().String("around", "", "Draw the neighborhood of a note (slug or ID)")
+	graphAnalyzeCmd.Flags().IntP("limit", "n", 10, "Number of nodes to list in each ranking")
+	graphServeCmd.Flags().String("addr", "127.0.0.1:7777", "Address to listen on")
+	graphServeCmd.Flags().Bool("open", false, "Open the graph in the browser")
+
+	graphCmd.AddCommand(graphAnalyzeCmd)
+	graphCmd.AddCommand(graphPathCmd)
+	graphCmd.AddCommand(graphServeCmd)
 	rootCmd.AddCommand(graphCmd)
 }
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/jeryldev/kb/internal/journal"
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var todayCmd = &cobra.Command{
+	Use:   "today",
+	Short: "Open or create today's journal note",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return openJournal(cmd, "today")
+	},
+}
+
+var journalCmd = &cobra.Command{
+	Use:   "journal [date|today|yesterday|tomorrow|+N|-N]",
+	Short: "Open or create the journal note for a day",
+	// Flags are parsed in RunE, so that an offset such as -1 is read as the
+	// day instead of as a shorthand flag.
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		offset, rest := splitDayOffset(args)
+		cmd.DisableFlagParsing = false
+		err := cmd.ParseFlags(rest)
+		cmd.DisableFlagParsing = true
+		if err != nil {
+			return err
+		}
+		if help, _ := cmd.Flags().GetBool("help"); help {
+			return cmd.Help()
+		}
+
+		args = cmd.Flags().Args()
+		if offset != "" {
+			args = append([]string{offset}, args...)
+		}
+		if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		day := "today"
+		if len(args) == 1 {
+			day = args[0]
+		}
+		return openJournal(cmd, day)
+	},
+}
+
+var dayOffsetRe = regexp.MustCompile(`^-\d+$`)
+
+// splitDayOffset takes the first negative day offset, such as -1, out of
+// args.
+func splitDayOffset(args []string) (string, []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if dayOffsetRe.MatchString(arg) {
+			return arg, append(args[:i:i], args[i+1:]...)
+		}
+	}
+	return "", args
+}
+
+func openJournal(cmd *cobra.Command, arg string) error {
+	day, err := journal.ParseDay(arg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	wsName, _ := cmd.Flags().GetString("workspace")
+	if wsName == "" {
+		wsName = journal.WorkspaceName()
+	}
+
+	note, created, err := journal.Open(db, day, wsName)
+	if err != nil {
+		return err
+	}
+
+	if edit, _ := cmd.Flags().GetBool("edit"); edit && !jsonOutput {
+		if err := editNoteBody(note); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput {
+		return printJSON(toNoteJSON(note))
+	}
+
+	out := cmd.OutOrStdout()
+	if created {
+		fmt.Fprintf(out, "Created journal note %q\n\n", note.Slug)
+	}
+	printNote(out, note)
+	return nil
+}
+
+func editNoteBody(note *model.Note) error {
+	c, tmpPath, err := tui.EditorCommand(note)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("running editor: %w", err)
+	}
+
+	data, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return fmt.Errorf("reading edited note: %w", err)
+	}
+	if string(data) == note.Body {
+		return nil
+	}
+	note.Body = string(data)
+	if err := db.UpdateNote(note); err != nil {
+		return err
+	}
+	return db.SyncNoteLinks(note)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{todayCmd, journalCmd} {
+		c.Flags().StringP("workspace", "w", "", "Journal workspace (default: $KB_JOURNAL_WORKSPACE or Journal)")
+		c.Flags().BoolP("edit", "e", false, "Open the note in $EDITOR")
+		rootCmd.AddCommand(c)
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeryldev/kb/internal/mdkanban"
+	"github.com/spf13/cobra"
+)
+
+var boardExportCmd = &cobra.Command{
+	Use:   "export <name>",
+	Short: "Export a board as Obsidian Kanban markdown",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format != mdkanban.Format {
+			return fmt.Errorf("unsupported format %q: use %s", format, mdkanban.Format)
+		}
+
+		board, err := db.GetBoardByName(args[0])
+		if err != nil {
+			return err
+		}
+		if board == nil {
+			return fmt.Errorf("board %q not found", args[0])
+		}
+
+		kanban, attachments, err := mdkanban.Load(db, board)
+		if err != nil {
+			return err
+		}
+		content := mdkanban.Render(kanban)
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			fmt.Fprint(cmd.OutOrStdout(), content)
+			return nil
+		}
+
+		if err := os.WriteFile(output, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", output, err)
+		}
+		dir := filepath.Dir(output)
+		for _, a := range attachments {
+			if err := copyFile(db.AttachmentPath(a), filepath.Join(dir, a.Name)); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Exported board %q to %s\n", board.Name, output)
+		if len(attachments) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Copied %d attachment(s) to %s\n", len(attachments), dir)
+		}
+		return nil
+	},
+}
+
+var boardImportCmd = &cobra.Command{
+	Use:   "import <file.md>",
+	Short: "Create a board from Obsidian Kanban markdown",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", args[0], err)
+		}
+		kanban, err := mdkanban.Parse(string(data))
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", args[0], err)
+		}
+
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+		}
+		wsName, _ := cmd.Flags().GetString("workspace")
+		workspaceID, err := resolveWorkspaceIDForCreate(wsName)
+		if err != nil {
+			return err
+		}
+
+		result, err := mdkanban.Import(db, kanban, name, workspaceID, filepath.Dir(args[0]))
+		if err != nil {
+			return err
+		}
+		for _, missing := range result.Missing {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: attachment %q not found next to %s\n", missing, args[0])
+		}
+
+		if jsonOutput {
+			return printJSON(toBoardJSON(result.Board))
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Imported board %q with %d column(s), %d card(s) and %d archived card(s) (id: %s)\n",
+			result.Board.Name, len(kanban.Lanes), result.Cards, result.Archived, result.Board.ID[:8])
+		if result.Attached > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Attached %d file(s)\n", result.Attached)
+		}
+		return nil
+	},
+}
+
+func init() {
+	boardExportCmd.Flags().String("format", mdkanban.Format, "Export format (md-kanban)")
+	boardExportCmd.Flags().StringP("output", "o", "", "Write to a file and copy card attachments next to it")
+
+	boardImportCmd.Flags().StringP("name", "n", "", "Board name (default: file name)")
+	boardImportCmd.Flags().StringP("workspace", "w", "", "Workspace to assign the board to (default: Default)")
+
+	boardCmd.AddCommand(boardExportCmd)
+	boardCmd.AddCommand(boardImportCmd)
+}
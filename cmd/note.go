@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/query"
+	"github.com/jeryldev/kb/internal/templates"
 	"github.com/spf13/cobra"
 )
 
@@ -15,11 +22,19 @@ var noteCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tag, _ := cmd.Flags().GetString("tag")
 		search, _ := cmd.Flags().GetString("search")
+		archived, _ := cmd.Flags().GetBool("archived")
+		trash, _ := cmd.Flags().GetBool("trash")
+		where, _ := cmd.Flags().GetStringArray("where")
+		sortKey, _ := cmd.Flags().GetString("sort")
 
 		var notes []*model.Note
 		var err error
 
 		switch {
+		case archived:
+			notes, err = db.ListArchivedNotes()
+		case trash:
+			notes, err = db.ListTrashedNotes()
 		case search != "":
 			notes, err = db.SearchNotes(search)
 		case tag != "":
@@ -31,11 +46,28 @@ var noteCmd = &cobra.Command{
 			return err
 		}
 
+		if len(where) > 0 || sortKey != "" {
+			if archived || trash {
+				return fmt.Errorf("--where and --sort cannot be combined with --archived or --trash")
+			}
+			notes, err = queryNoteProperties(notes, where, sortKey)
+			if err != nil {
+				return err
+			}
+		}
+
 		if len(notes) == 0 {
 			if jsonOutput {
 				return printJSON([]noteJSON{})
 			}
-			fmt.Fprintln(cmd.OutOrStdout(), "No notes found. Create one with: kb note create \"title\"")
+			switch {
+			case archived:
+				fmt.Fprintln(cmd.OutOrStdout(), "No archived notes.")
+			case trash:
+				fmt.Fprintln(cmd.OutOrStdout(), "Trash is empty.")
+			default:
+				fmt.Fprintln(cmd.OutOrStdout(), "No notes found. Create one with: kb note create \"title\"")
+			}
 			return nil
 		}
 
@@ -77,6 +109,16 @@ var noteCreateCmd = &cobra.Command{
 			return err
 		}
 
+		if tmplName, _ := cmd.Flags().GetString("template"); tmplName != "" {
+			if cmd.Flags().Changed("body") {
+				return fmt.Errorf("use either --body or --template, not both")
+			}
+			body, err = renderNoteTemplate(cmd, tmplName, title, workspaceID)
+			if err != nil {
+				return err
+			}
+		}
+
 		note, err := db.CreateNote(title, slug, body, workspaceID)
 		if err != nil {
 			return err
@@ -102,6 +144,71 @@ var noteCreateCmd = &cobra.Command{
 	},
 }
 
+var noteTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List note templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := templates.List()
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			out := make([]templateJSON, len(list))
+			for i, t := range list {
+				out[i] = templateJSON{
+					Name:    t.Name,
+					Source:  t.Source,
+					Prompts: templates.Prompts(t.Body),
+					Body:    t.Body,
+				}
+			}
+			return printJSON(out)
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tPROMPTS\tSOURCE")
+		for _, t := range list {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", t.Name, strings.Join(templates.Prompts(t.Body), ", "), t.Source)
+		}
+		return w.Flush()
+	},
+}
+
+func renderNoteTemplate(cmd *cobra.Command, name, title, workspaceID string) (string, error) {
+	tmpl, err := templates.Get(name)
+	if err != nil {
+		return "", err
+	}
+	ws, err := db.GetWorkspace(workspaceID)
+	if err != nil {
+		return "", err
+	}
+
+	answers := make(map[string]string)
+	vars, _ := cmd.Flags().GetStringArray("var")
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return "", fmt.Errorf("invalid --var %q: expected Label=value", v)
+		}
+		answers[strings.TrimSpace(key)] = value
+	}
+
+	reader := bufio.NewReader(cmd.InOrStdin())
+	for _, label := range templates.Prompts(tmpl.Body) {
+		if _, ok := answers[label]; ok || jsonOutput {
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: ", label)
+		line, _ := reader.ReadString('\n')
+		answers[label] = strings.TrimSpace(line)
+	}
+
+	return templates.Expand(tmpl.Body, templates.NoteVars(title, ws.Name, time.Now()), answers), nil
+}
+
 var noteShowCmd = &cobra.Command{
 	Use:   "show <slug-or-id>",
 	Short: "Show note details",
@@ -112,26 +219,67 @@ var noteShowCmd = &cobra.Command{
 			return err
 		}
 
+		aliases, err := db.ListNoteAliases(note.ID)
+		if err != nil {
+			return err
+		}
+
+		props, err := db.ListNoteProperties(note.ID)
+		if err != nil {
+			return err
+		}
+
 		if jsonOutput {
-			return printJSON(toNoteJSON(note))
+			out := toNoteJSON(note)
+			out.Aliases = aliases
+			out.Properties = toPropertiesJSON(props)
+			return printJSON(out)
 		}
 
-		out := cmd.OutOrStdout()
-		fmt.Fprintf(out, "Title: %s\n", note.Title)
-		fmt.Fprintf(out, "Slug:  %s\n", note.Slug)
-		if note.Tags != "" {
-			fmt.Fprintf(out, "Tags:  %s\n", note.Tags)
+		printNote(cmd.OutOrStdout(), note)
+		return nil
+	},
+}
+
+var noteRenderCmd = &cobra.Command{
+	Use:   "render <slug-or-id>",
+	Short: "Print a note body with its kb-query blocks evaluated",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, err := resolveNote(args[0])
+		if err != nil {
+			return err
 		}
-		if note.Body != "" {
-			fmt.Fprintf(out, "\n%s\n", note.Body)
+
+		rendered := query.Expand(db, note.Body)
+		if jsonOutput {
+			out := toNoteJSON(note)
+			out.Body = rendered
+			return printJSON(out)
 		}
-		fmt.Fprintf(out, "\nCreated: %s   Updated: %s\n",
-			note.CreatedAt.Format("02 Jan 2006"), note.UpdatedAt.Format("02 Jan 2006"))
-		fmt.Fprintf(out, "ID: %s\n", note.ID)
+
+		fmt.Fprintln(cmd.OutOrStdout(), strings.TrimRight(rendered, "\n"))
 		return nil
 	},
 }
 
+func printNote(out io.Writer, note *model.Note) {
+	fmt.Fprintf(out, "Title: %s\n", note.Title)
+	fmt.Fprintf(out, "Slug:  %s\n", note.Slug)
+	if note.Tags != "" {
+		fmt.Fprintf(out, "Tags:  %s\n", note.Tags)
+	}
+	if aliases, _ := db.ListNoteAliases(note.ID); len(aliases) > 0 {
+		fmt.Fprintf(out, "Aliases: %s\n", strings.Join(aliases, ", "))
+	}
+	if note.Body != "" {
+		fmt.Fprintf(out, "\n%s\n", note.Body)
+	}
+	fmt.Fprintf(out, "\nCreated: %s   Updated: %s\n",
+		note.CreatedAt.Format("02 Jan 2006"), note.UpdatedAt.Format("02 Jan 2006"))
+	fmt.Fprintf(out, "ID: %s\n", note.ID)
+}
+
 var noteEditCmd = &cobra.Command{
 	Use:   "edit <slug-or-id>",
 	Short: "Edit a note's fields",
@@ -169,25 +317,238 @@ var noteEditCmd = &cobra.Command{
 	},
 }
 
+var noteArchiveCmd = &cobra.Command{
+	Use:   "archive <slug-or-id>",
+	Short: "Archive a note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := db.ArchiveNote(note.ID); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			archived, err := resolveNoteIn(db.ListArchivedNotes, note.ID)
+			if err != nil {
+				return fmt.Errorf("fetching archived note: %w", err)
+			}
+			return printJSON(toNoteJSON(archived))
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Archived note %q\n", note.Title)
+		return nil
+	},
+}
+
+var noteUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive <slug-or-id>",
+	Short: "Restore an archived note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, err := resolveNoteIn(db.ListArchivedNotes, args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := db.UnarchiveNote(note.ID); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			restored, err := db.GetNote(note.ID)
+			if err != nil {
+				return err
+			}
+			return printJSON(toNoteJSON(restored))
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Unarchived note %q\n", note.Title)
+		return nil
+	},
+}
+
 var noteDeleteCmd = &cobra.Command{
 	Use:   "delete <slug-or-id>",
-	Short: "Delete a note",
+	Short: "Move a note to the trash (or --purge to delete permanently)",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		purge, _ := cmd.Flags().GetBool("purge")
+
 		note, err := resolveNote(args[0])
 		if err != nil {
+			note, err = resolveNoteIn(db.ListArchivedNotes, args[0])
+		}
+		if err != nil && purge {
+			note, err = resolveNoteIn(db.ListTrashedNotes, args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("note %q not found", args[0])
+		}
+
+		if purge {
+			force, _ := cmd.Flags().GetBool("force")
+			if !force && !jsonOutput {
+				fmt.Fprintf(cmd.OutOrStdout(), "Permanently delete note %q and its publish history? This cannot be undone. [y/N] ", note.Title)
+				var confirm string
+				fmt.Fscanln(cmd.InOrStdin(), &confirm)
+				if confirm != "y" && confirm != "Y" {
+					fmt.Fprintln(cmd.OutOrStdout(), "Cancelled.")
+					return nil
+				}
+			}
+			if err := db.PurgeNote(note.ID); err != nil {
+				return err
+			}
+		} else if err := db.DeleteNote(note.ID); err != nil {
 			return err
 		}
 
-		if err := db.DeleteNote(note.ID); err != nil {
+		if jsonOutput {
+			return printJSON(toNoteJSON(note))
+		}
+
+		if purge {
+			fmt.Fprintf(cmd.OutOrStdout(), "Permanently deleted note %q\n", note.Title)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Moved note %q to the trash (restore with: kb note restore %s)\n", note.Title, note.Slug)
+		}
+		return nil
+	},
+}
+
+var noteRestoreCmd = &cobra.Command{
+	Use:   "restore <slug-or-id>",
+	Short: "Restore a note from the trash",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, err := resolveNoteIn(db.ListTrashedNotes, args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := db.RestoreNote(note.ID); err != nil {
 			return err
 		}
 
 		if jsonOutput {
+			note.DeletedAt = nil
 			return printJSON(toNoteJSON(note))
 		}
 
-		fmt.Fprintf(cmd.OutOrStdout(), "Deleted note %q\n", note.Title)
+		fmt.Fprintf(cmd.OutOrStdout(), "Restored note %q\n", note.Title)
+		return nil
+	},
+}
+
+var noteRelatedCmd = &cobra.Command{
+	Use:   "related <slug-or-id>",
+	Short: "Suggest similar notes that are not linked yet",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		note, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+
+		related, err := db.RelatedNotes(note.ID, limit)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			out := make([]relatedNoteJSON, len(related))
+			for i, r := range related {
+				out[i] = relatedNoteJSON{ID: r.Note.ID, Title: r.Note.Title, Slug: r.Note.Slug, Score: r.Score}
+			}
+			return printJSON(out)
+		}
+
+		if len(related) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "No related notes for %q\n", note.Slug)
+			return nil
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Notes related to %q:\n\n", note.Slug)
+		for _, r := range related {
+			fmt.Fprintf(out, "  %.2f  [[%s]] %s\n", r.Score, r.Note.Slug, truncateStr(r.Note.Title, 60))
+		}
+		return nil
+	},
+}
+
+var noteMentionsCmd = &cobra.Command{
+	Use:   "mentions <slug-or-id> [number...]",
+	Short: "Find unlinked mentions of a note and optionally link them",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		link, _ := cmd.Flags().GetBool("link")
+		if len(args) > 1 && !link {
+			return fmt.Errorf("mention numbers select what to link: add --link")
+		}
+
+		note, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+
+		mentions, err := db.FindMentions(note)
+		if err != nil {
+			return err
+		}
+
+		if link {
+			selected := mentions
+			if len(args) > 1 {
+				selected = nil
+				for _, arg := range args[1:] {
+					n, err := strconv.Atoi(arg)
+					if err != nil || n < 1 || n > len(mentions) {
+						return fmt.Errorf("invalid mention number %q: use 1-%d", arg, len(mentions))
+					}
+					selected = append(selected, mentions[n-1])
+				}
+			}
+			linked, err := db.LinkMentions(note, selected)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(mentionLinkJSON{Slug: note.Slug, Linked: linked})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Linked %d mention(s) of %q\n", linked, note.Slug)
+			return nil
+		}
+
+		if jsonOutput {
+			out := make([]mentionJSON, len(mentions))
+			for i, m := range mentions {
+				out[i] = toMentionJSON(i+1, m)
+			}
+			return printJSON(out)
+		}
+
+		if len(mentions) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "No unlinked mentions of %q\n", note.Slug)
+			return nil
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Unlinked mentions of %q:\n\n", note.Slug)
+		for i, m := range mentions {
+			source := fmt.Sprintf("[[%s]]:%d", m.Source, m.Line)
+			if m.SourceType == "card" {
+				source = fmt.Sprintf("[[card:%s]] (%s)", m.Source, m.Board)
+			}
+			fmt.Fprintf(out, "  %d. %s\n     %s\n", i+1, source, truncateStr(strings.TrimSpace(m.Context), 70))
+		}
+		fmt.Fprintf(out, "\nLink them with: kb note mentions %s --link [number...]\n", note.Slug)
 		return nil
 	},
 }
@@ -213,6 +574,8 @@ var noteBacklinksCmd = &cobra.Command{
 				out[i] = backlinkJSON{
 					SourceType: l.SourceType,
 					SourceID:   l.SourceID,
+					Fragment:   l.Fragment,
+					Relation:   l.Relation,
 					Context:    l.Context,
 				}
 			}
@@ -227,10 +590,23 @@ var noteBacklinksCmd = &cobra.Command{
 		out := cmd.OutOrStdout()
 		fmt.Fprintf(out, "Backlinks to %q:\n\n", note.Slug)
 		for _, l := range links {
-			if l.SourceType == "note" {
+			section := ""
+			if l.Fragment != "" {
+				section = " → " + l.Fragment
+			}
+			if l.Relation != "" {
+				section += " (" + l.Relation + ")"
+			}
+			switch l.SourceType {
+			case "note":
 				source, err := db.GetNote(l.SourceID)
 				if err == nil {
-					fmt.Fprintf(out, "  [[%s]] %s\n", source.Slug, truncateStr(l.Context, 60))
+					fmt.Fprintf(out, "  [[%s]]%s %s\n", source.Slug, section, truncateStr(l.Context, 60))
+				}
+			case "card":
+				card, err := db.GetCard(l.SourceID)
+				if err == nil {
+					fmt.Fprintf(out, "  [[card:%s]]%s %s\n", card.ID[:8], section, truncateStr(card.Title, 60))
 				}
 			}
 		}
@@ -238,8 +614,37 @@ var noteBacklinksCmd = &cobra.Command{
 	},
 }
 
+// queryNoteProperties narrows notes to those matching the --where filters,
+// in --sort order.
+func queryNoteProperties(notes []*model.Note, where []string, sortKey string) ([]*model.Note, error) {
+	filters := make([]model.PropertyFilter, 0, len(where))
+	for _, expr := range where {
+		f, err := model.ParsePropertyFilter(expr)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+
+	matched, err := db.QueryNotes(filters, sortKey)
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[string]bool, len(notes))
+	for _, n := range notes {
+		keep[n.ID] = true
+	}
+	var result []*model.Note
+	for _, n := range matched {
+		if keep[n.ID] {
+			result = append(result, n)
+		}
+	}
+	return result, nil
+}
+
 func resolveNote(ref string) (*model.Note, error) {
-	note, err := db.GetNoteBySlug(ref)
+	note, err := db.GetNoteByRef(ref)
 	if err == nil {
 		return note, nil
 	}
@@ -252,23 +657,65 @@ func resolveNote(ref string) (*model.Note, error) {
 	return nil, fmt.Errorf("note %q not found", ref)
 }
 
+func resolveNoteIn(list func() ([]*model.Note, error), ref string) (*model.Note, error) {
+	notes, err := list()
+	if err != nil {
+		return nil, err
+	}
+	var matches []*model.Note
+	for _, n := range notes {
+		if n.Slug == ref || n.ID == ref {
+			return n, nil
+		}
+		if len(ref) >= 4 && strings.HasPrefix(n.ID, ref) {
+			matches = append(matches, n)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("note %q not found", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("ambiguous note ID %q matches %d notes; use more characters", ref, len(matches))
+	}
+}
+
 func init() {
-	noteCmd.Flags().StringP("tag", "t", "", "Filter by tag")
+	noteCmd.Flags().StringP("tag", "t", "", "Filter by tag, including nested tags")
 	noteCmd.Flags().StringP("search", "s", "", "Search in title, body, and tags")
+	noteCmd.Flags().Bool("archived", false, "List archived notes")
+	noteCmd.Flags().Bool("trash", false, "List notes in the trash")
+	noteCmd.Flags().StringArray("where", nil, "Filter by front matter property (key=value, key<value, ...)")
+	noteCmd.Flags().String("sort", "", "Sort by front matter property (prefix with - for descending)")
 
 	noteCreateCmd.Flags().StringP("body", "b", "", "Note body content")
 	noteCreateCmd.Flags().StringP("tags", "t", "", "Comma-separated tags")
 	noteCreateCmd.Flags().String("slug", "", "Custom slug (default: auto-generated from title)")
 	noteCreateCmd.Flags().StringP("workspace", "w", "", "Workspace to assign the note to (default: Default)")
+	noteCreateCmd.Flags().String("template", "", "Create the body from a template (see: kb note templates)")
+	noteCreateCmd.Flags().StringArray("var", nil, "Answer a template prompt, as Label=value (repeatable)")
 
 	noteEditCmd.Flags().StringP("title", "T", "", "New title")
 	noteEditCmd.Flags().StringP("body", "b", "", "New body content")
 	noteEditCmd.Flags().StringP("tags", "t", "", "New tags (comma-separated)")
 
+	noteDeleteCmd.Flags().Bool("purge", false, "Delete permanently instead of moving to the trash")
+	noteDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation when purging")
+
 	noteCmd.AddCommand(noteCreateCmd)
+	noteCmd.AddCommand(noteTemplatesCmd)
 	noteCmd.AddCommand(noteShowCmd)
+	noteCmd.AddCommand(noteRenderCmd)
 	noteCmd.AddCommand(noteEditCmd)
+	noteCmd.AddCommand(noteArchiveCmd)
+	noteCmd.AddCommand(noteUnarchiveCmd)
 	noteCmd.AddCommand(noteDeleteCmd)
+	noteCmd.AddCommand(noteRestoreCmd)
 	noteCmd.AddCommand(noteBacklinksCmd)
+	noteRelatedCmd.Flags().IntP("limit", "n", 10, "Maximum number of notes to suggest")
+	noteCmd.AddCommand(noteRelatedCmd)
+	noteMentionsCmd.Flags().Bool("link", false, "Turn the mentions, or only the numbered ones, into wikilinks")
+	noteCmd.AddCommand(noteMentionsCmd)
 	rootCmd.AddCommand(noteCmd)
 }
@@ -3,10 +3,12 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
 )
 
 var jsonOutput bool
@@ -44,6 +46,14 @@ func formatTime(t time.Time) string {
 	return t.Format(time.RFC3339)
 }
 
+func formatTimePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := formatTime(*t)
+	return &s
+}
+
 func toBoardJSON(b *model.Board) boardJSON {
 	return boardJSON{
 		ID:          b.ID,
@@ -104,23 +114,210 @@ func toWorkspaceJSON(ws *model.Workspace) workspaceJSON {
 }
 
 type noteJSON struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Slug        string `json:"slug"`
-	Body        string `json:"body"`
-	Tags        string `json:"tags"`
-	Pinned      bool   `json:"pinned"`
-	WorkspaceID string `json:"workspace_id"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	Slug        string         `json:"slug"`
+	Body        string         `json:"body"`
+	Tags        string         `json:"tags"`
+	Pinned      bool           `json:"pinned"`
+	WorkspaceID string         `json:"workspace_id"`
+	ArchivedAt  *string        `json:"archived_at,omitempty"`
+	DeletedAt   *string        `json:"deleted_at,omitempty"`
+	Aliases     []string       `json:"aliases,omitempty"`
+	Properties  map[string]any `json:"properties,omitempty"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+}
+
+func toPropertiesJSON(props []model.Property) map[string]any {
+	if len(props) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(props))
+	for _, p := range props {
+		switch p.Type {
+		case model.PropertyList:
+			out[p.Key] = p.Items
+		case model.PropertyNumber:
+			if n, err := strconv.ParseFloat(p.Value, 64); err == nil {
+				out[p.Key] = n
+				continue
+			}
+			out[p.Key] = p.Value
+		default:
+			out[p.Key] = p.Value
+		}
+	}
+	return out
+}
+
+type noteAliasesJSON struct {
+	NoteID  string   `json:"note_id"`
+	Slug    string   `json:"slug"`
+	Aliases []string `json:"aliases"`
+}
+
+type tagJSON struct {
+	Tag      string    `json:"tag"`
+	Path     string    `json:"path"`
+	Count    int       `json:"count"`
+	Children []tagJSON `json:"children"`
+}
+
+func toTagJSON(nodes []*model.TagNode) []tagJSON {
+	out := make([]tagJSON, len(nodes))
+	for i, n := range nodes {
+		out[i] = tagJSON{Tag: n.Name, Path: n.Path, Count: n.Count, Children: toTagJSON(n.Children)}
+	}
+	return out
+}
+
+type tagRenameJSON struct {
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	Notes int    `json:"notes"`
+}
+
+type taskJSON struct {
+	ID       string `json:"id"`
+	NoteID   string `json:"note_id"`
+	Note     string `json:"note"`
+	Line     int    `json:"line"`
+	Text     string `json:"text"`
+	Checked  bool   `json:"checked"`
+	Due      string `json:"due"`
+	Priority string `json:"priority"`
+	Context  string `json:"context"`
+}
+
+func toTaskJSON(t *model.Task) taskJSON {
+	return taskJSON{
+		ID:       t.ID,
+		NoteID:   t.NoteID,
+		Note:     t.NoteSlug,
+		Line:     t.Line + 1,
+		Text:     t.Text,
+		Checked:  t.Checked,
+		Due:      t.Due,
+		Priority: string(t.Priority),
+		Context:  t.Context,
+	}
+}
+
+type flashcardJSON struct {
+	ID       string  `json:"id"`
+	NoteID   string  `json:"note_id"`
+	Note     string  `json:"note"`
+	Line     int     `json:"line"`
+	Front    string  `json:"front"`
+	Back     string  `json:"back"`
+	Reps     int     `json:"reps"`
+	Interval int     `json:"interval"`
+	Ease     float64 `json:"ease"`
+	Due      string  `json:"due"`
+}
+
+func toFlashcardJSON(f *model.Flashcard) flashcardJSON {
+	return flashcardJSON{
+		ID:       f.ID,
+		NoteID:   f.NoteID,
+		Note:     f.NoteSlug,
+		Line:     f.Line + 1,
+		Front:    f.Front,
+		Back:     f.Back,
+		Reps:     f.Reps,
+		Interval: f.Interval,
+		Ease:     f.Ease,
+		Due:      f.Due,
+	}
+}
+
+type reviewStatsJSON struct {
+	Total         int `json:"total"`
+	New           int `json:"new"`
+	Due           int `json:"due"`
+	DueTomorrow   int `json:"due_tomorrow"`
+	ReviewedToday int `json:"reviewed_today"`
+	Streak        int `json:"streak"`
+	LongestStreak int `json:"longest_streak"`
+}
+
+type attachmentJSON struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MimeType  string `json:"mime_type"`
+	Size      int64  `json:"size"`
+	OwnerType string `json:"owner_type"`
+	OwnerID   string `json:"owner_id"`
+	Path      string `json:"path"`
+	CreatedAt string `json:"created_at"`
+}
+
+func toAttachmentJSON(a *model.Attachment, path string) attachmentJSON {
+	kind, id := a.Owner()
+	return attachmentJSON{
+		ID:        a.ID,
+		Name:      a.Name,
+		MimeType:  a.MimeType,
+		Size:      a.Size,
+		OwnerType: kind,
+		OwnerID:   id,
+		Path:      path,
+		CreatedAt: formatTime(a.CreatedAt),
+	}
+}
+
+type templateJSON struct {
+	Name    string   `json:"name"`
+	Source  string   `json:"source"`
+	Prompts []string `json:"prompts"`
+	Body    string   `json:"body"`
 }
 
 type backlinkJSON struct {
 	SourceType string `json:"source_type"`
 	SourceID   string `json:"source_id"`
+	Fragment   string `json:"fragment"`
+	Relation   string `json:"relation"`
 	Context    string `json:"context"`
 }
 
+type relatedNoteJSON struct {
+	ID    string  `json:"id"`
+	Title string  `json:"title"`
+	Slug  string  `json:"slug"`
+	Score float64 `json:"score"`
+}
+
+type mentionJSON struct {
+	Number     int    `json:"number"`
+	SourceType string `json:"source_type"`
+	SourceID   string `json:"source_id"`
+	Source     string `json:"source"`
+	Board      string `json:"board,omitempty"`
+	Line       int    `json:"line"`
+	Text       string `json:"text"`
+	Context    string `json:"context"`
+}
+
+func toMentionJSON(number int, m *store.Mention) mentionJSON {
+	return mentionJSON{
+		Number:     number,
+		SourceType: m.SourceType,
+		SourceID:   m.SourceID,
+		Source:     m.Source,
+		Board:      m.Board,
+		Line:       m.Line,
+		Text:       m.Text,
+		Context:    m.Context,
+	}
+}
+
+type mentionLinkJSON struct {
+	Slug   string `json:"slug"`
+	Linked int    `json:"linked"`
+}
+
 func toNoteJSON(n *model.Note) noteJSON {
 	return noteJSON{
 		ID:          n.ID,
@@ -130,6 +327,8 @@ func toNoteJSON(n *model.Note) noteJSON {
 		Tags:        n.Tags,
 		Pinned:      n.Pinned,
 		WorkspaceID: n.WorkspaceID,
+		ArchivedAt:  formatTimePtr(n.ArchivedAt),
+		DeletedAt:   formatTimePtr(n.DeletedAt),
 		CreatedAt:   formatTime(n.CreatedAt),
 		UpdatedAt:   formatTime(n.UpdatedAt),
 	}
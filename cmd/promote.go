@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var notePromoteCmd = &cobra.Command{
+	Use:   "promote <slug-or-id>",
+	Short: "Turn unchecked checkboxes into cards that stay in sync with the note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+
+		var board *model.Board
+		if boardName, _ := cmd.Flags().GetString("board"); boardName != "" {
+			board, err = db.GetBoardByName(boardName)
+			if err != nil {
+				return err
+			}
+			if board == nil {
+				return fmt.Errorf("board %q not found", boardName)
+			}
+		} else if board, err = resolveBoard(); err != nil {
+			return err
+		}
+
+		columns, err := db.ListColumns(board.ID)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			return fmt.Errorf("board %q has no columns", board.Name)
+		}
+		column := columns[0]
+		if colName, _ := cmd.Flags().GetString("column"); colName != "" {
+			if column, err = resolveColumnByName(board.ID, colName); err != nil {
+				return fmt.Errorf("column %q not found on board %q", colName, board.Name)
+			}
+		}
+
+		cards, err := db.PromoteCheckboxes(note.ID, column.ID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			out := make([]cardJSON, len(cards))
+			for i, c := range cards {
+				out[i] = toCardJSON(c, column.Name)
+			}
+			return printJSON(out)
+		}
+
+		if len(cards) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "No unchecked checkboxes to promote in %q\n", note.Title)
+			return nil
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Promoted %d checkbox(es) to %s / %s:\n", len(cards), board.Name, column.Name)
+		for _, c := range cards {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s\n", c.ID[:8], c.Title)
+		}
+		return nil
+	},
+}
+
+func init() {
+	notePromoteCmd.Flags().String("board", "", "Board to add cards to (default: current board)")
+	notePromoteCmd.Flags().StringP("column", "c", "", "Column for the new cards (default: first column)")
+	noteCmd.AddCommand(notePromoteCmd)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/jeryldev/kb/internal/model"
 	"github.com/jeryldev/kb/internal/publish"
+	"github.com/jeryldev/kb/internal/query"
 	"github.com/spf13/cobra"
 )
 
@@ -29,6 +30,11 @@ var publishCmd = &cobra.Command{
 
 		draft, _ := cmd.Flags().GetBool("draft")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if freeze, _ := cmd.Flags().GetBool("freeze-queries"); freeze {
+			frozen := *note
+			frozen.Body = query.Expand(db, note.Body)
+			note = &frozen
+		}
 
 		now := time.Now().UTC()
 
@@ -41,20 +47,47 @@ var publishCmd = &cobra.Command{
 			publishedSlugs[slug] = publish.JekyllPermalink(slug, now)
 		}
 
-		content := publish.GeneratePost(note, now, draft, publishedSlugs, db)
+		propKeys, _ := cmd.Flags().GetStringSlice("property")
+		var props []model.Property
+		if len(propKeys) > 0 {
+			noteProps, err := db.ListNoteProperties(note.ID)
+			if err != nil {
+				return err
+			}
+			props = publish.SelectProperties(noteProps, propKeys)
+		}
+
+		content := publish.GeneratePost(note, now, draft, props, publishedSlugs, db)
 		relPath := publish.PostFilePath(target.PostsDir, note.Slug, now)
 		fullPath := filepath.Join(target.BasePath, relPath)
 
+		var attachments []*model.Attachment
+		var assets []string
+		for _, ref := range publish.AttachmentRefs(note, db) {
+			a, err := db.GetAttachmentByName("note", ref.Note.ID, ref.Name)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+				continue
+			}
+			attachments = append(attachments, a)
+			assets = append(assets, filepath.Join(target.BasePath, publish.AssetPath(ref.Note.Slug, ref.Name)))
+		}
+
 		if dryRun {
 			if jsonOutput {
 				return printJSON(struct {
-					FilePath string `json:"file_path"`
-					Content  string `json:"content"`
-					Draft    bool   `json:"draft"`
-				}{fullPath, content, draft})
+					FilePath string   `json:"file_path"`
+					Content  string   `json:"content"`
+					Draft    bool     `json:"draft"`
+					Assets   []string `json:"assets,omitempty"`
+				}{fullPath, content, draft, assets})
 			}
 			out := cmd.OutOrStdout()
-			fmt.Fprintf(out, "Would write to: %s\n\n", fullPath)
+			fmt.Fprintf(out, "Would write to: %s\n", fullPath)
+			for _, asset := range assets {
+				fmt.Fprintf(out, "Would copy: %s\n", asset)
+			}
+			fmt.Fprintln(out)
 			fmt.Fprint(out, content)
 			return nil
 		}
@@ -68,7 +101,13 @@ var publishCmd = &cobra.Command{
 			return fmt.Errorf("writing file %s: %w", fullPath, err)
 		}
 
-		frontMatter := publish.GenerateFrontMatter(note, now, draft)
+		for i, a := range attachments {
+			if err := copyFile(db.AttachmentPath(a), assets[i]); err != nil {
+				return err
+			}
+		}
+
+		frontMatter := publish.GenerateFrontMatter(note, now, draft, props)
 		pl, err := db.CreatePublishLog(note.ID, target.ID, relPath, frontMatter)
 		if err != nil {
 			return fmt.Errorf("recording publish log: %w", err)
@@ -83,6 +122,9 @@ var publishCmd = &cobra.Command{
 			label = "Published (draft)"
 		}
 		fmt.Fprintf(cmd.OutOrStdout(), "%s %q to %s\n", label, note.Title, fullPath)
+		if len(attachments) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Copied %d attachment(s) to %s\n", len(attachments), filepath.Join(target.BasePath, publish.AssetsDir))
+		}
 		return nil
 	},
 }
@@ -258,6 +300,8 @@ func init() {
 	publishCmd.Flags().StringP("target", "t", "", "Publish target name (auto-selects if only one exists)")
 	publishCmd.Flags().Bool("draft", false, "Publish as draft (published: false)")
 	publishCmd.Flags().Bool("dry-run", false, "Preview output without writing file")
+	publishCmd.Flags().Bool("freeze-queries", false, "Replace kb-query blocks with their current results")
+	publishCmd.Flags().StringSliceP("property", "P", nil, "Front matter properties to pass through (comma-separated keys)")
 
 	publishSetupCmd.Flags().StringP("engine", "e", "jekyll", "Publishing engine")
 	publishSetupCmd.Flags().StringP("path", "p", "", "Base path to the site directory")
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jeryldev/kb/internal/store"
+	"github.com/jeryldev/kb/internal/tui"
+	"github.com/spf13/cobra"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review due flashcards in the TUI",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsRef, _ := cmd.Flags().GetString("workspace")
+		tag, _ := cmd.Flags().GetString("tag")
+
+		filter := store.FlashcardFilter{Tag: tag}
+		if wsRef != "" {
+			ws, err := resolveWorkspace(wsRef)
+			if err != nil {
+				return err
+			}
+			filter.WorkspaceID = ws.ID
+		}
+
+		cards, err := db.ListDueFlashcards(filter, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			out := make([]flashcardJSON, len(cards))
+			for i, c := range cards {
+				out[i] = toFlashcardJSON(c)
+			}
+			return printJSON(out)
+		}
+
+		if len(cards) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No flashcards due.")
+			return nil
+		}
+		p := tea.NewProgram(tui.NewReviewApp(db, filter), tea.WithAltScreen())
+		_, err = p.Run()
+		return err
+	},
+}
+
+var reviewStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show due flashcards and review streaks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stats, err := db.FlashcardStats(time.Now())
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(reviewStatsJSON{
+				Total:         stats.Total,
+				New:           stats.New,
+				Due:           stats.Due,
+				DueTomorrow:   stats.DueTomorrow,
+				ReviewedToday: stats.ReviewedToday,
+				Streak:        stats.Streak,
+				LongestStreak: stats.LongestStreak,
+			})
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Flashcards:     %d (%d new)\n", stats.Total, stats.New)
+		fmt.Fprintf(out, "Due today:      %d\n", stats.Due)
+		fmt.Fprintf(out, "Due tomorrow:   %d\n", stats.DueTomorrow)
+		fmt.Fprintf(out, "Reviewed today: %d\n", stats.ReviewedToday)
+		fmt.Fprintf(out, "Streak:         %d day(s), longest %d\n", stats.Streak, stats.LongestStreak)
+		return nil
+	},
+}
+
+func init() {
+	reviewCmd.Flags().StringP("workspace", "w", "", "Only review flashcards from notes in this workspace")
+	reviewCmd.Flags().StringP("tag", "t", "", "Only review flashcards from notes with this tag, including nested tags")
+
+	reviewCmd.AddCommand(reviewStatsCmd)
+	rootCmd.AddCommand(reviewCmd)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jeryldev/kb/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var boardSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <name>",
+	Short: "Save the board's columns and cards as a markdown note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		board, err := db.GetBoardByName(args[0])
+		if err != nil {
+			return err
+		}
+		if board == nil {
+			return fmt.Errorf("board %q not found", args[0])
+		}
+
+		slug, _ := cmd.Flags().GetString("note")
+		note, created, err := snapshot.Take(db, board, slug, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(toNoteJSON(note))
+		}
+
+		if created {
+			fmt.Fprintf(cmd.OutOrStdout(), "Saved snapshot of %q to note %q (slug: %s)\n", board.Name, note.Title, note.Slug)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Appended snapshot of %q to note %q\n", board.Name, note.Title)
+		}
+		return nil
+	},
+}
+
+func init() {
+	boardSnapshotCmd.Flags().String("note", "", "Slug of the note to create, or slug or alias of an existing note to append to")
+
+	boardCmd.AddCommand(boardSnapshotCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:     "tags",
+	Aliases: []string{"tag"},
+	Short:   "Show the tag tree with note counts",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noteTags, err := db.ListNoteTags()
+		if err != nil {
+			return err
+		}
+		tree := model.BuildTagTree(noteTags)
+
+		if jsonOutput {
+			return printJSON(toTagJSON(tree))
+		}
+
+		if len(tree) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No tags. Add #tags to a note body or use: kb note create \"title\" --tags go")
+			return nil
+		}
+		printTagTree(cmd.OutOrStdout(), tree, 0)
+		return nil
+	},
+}
+
+var tagRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag and the tags nested below it across all notes",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		old, new := model.NormalizeTag(args[0]), model.NormalizeTag(args[1])
+		count, err := db.RenameTag(old, new)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(tagRenameJSON{Old: old, New: new, Notes: count})
+		}
+
+		if count == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "No notes tagged #%s\n", old)
+			return nil
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Renamed #%s to #%s in %d note(s)\n", old, new, count)
+		return nil
+	},
+}
+
+func printTagTree(w io.Writer, nodes []*model.TagNode, depth int) {
+	for _, n := range nodes {
+		fmt.Fprintf(w, "%s#%s (%d)\n", strings.Repeat("  ", depth), n.Name, n.Count)
+		printTagTree(w, n.Children, depth+1)
+	}
+}
+
+func init() {
+	tagCmd.AddCommand(tagRenameCmd)
+	rootCmd.AddCommand(tagCmd)
+}
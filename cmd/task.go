@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var taskCmd = &cobra.Command{
+	Use:     "tasks",
+	Aliases: []string{"task"},
+	Short:   "List checkbox tasks from all notes",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		open, _ := cmd.Flags().GetBool("open")
+		wsRef, _ := cmd.Flags().GetString("workspace")
+		tag, _ := cmd.Flags().GetString("tag")
+
+		filter := store.TaskFilter{Open: open, Tag: tag}
+		if wsRef != "" {
+			ws, err := resolveWorkspace(wsRef)
+			if err != nil {
+				return err
+			}
+			filter.WorkspaceID = ws.ID
+		}
+
+		tasks, err := db.ListTasks(filter)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			out := make([]taskJSON, len(tasks))
+			for i, t := range tasks {
+				out[i] = toTaskJSON(t)
+			}
+			return printJSON(out)
+		}
+
+		if len(tasks) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No tasks. Add \"- [ ] something\" to a note body.")
+			return nil
+		}
+		now := time.Now()
+		noteID := ""
+		for _, t := range tasks {
+			if t.NoteID != noteID {
+				if noteID != "" {
+					fmt.Fprintln(cmd.OutOrStdout())
+				}
+				noteID = t.NoteID
+				fmt.Fprintf(cmd.OutOrStdout(), "%s (%s)\n", t.NoteTitle, t.NoteSlug)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s %s  %s\n", taskBox(t), t.ID[:8], formatTask(t, now))
+		}
+		return nil
+	},
+}
+
+var taskToggleCmd = &cobra.Command{
+	Use:   "toggle <id>",
+	Short: "Tick or clear a task's checkbox in its note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		task, err := db.ResolveTask(args[0])
+		if err != nil {
+			return err
+		}
+		task, err = db.ToggleTask(task.ID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(toTaskJSON(task))
+		}
+
+		state := "Reopened"
+		if task.Checked {
+			state = "Completed"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %s (%s)\n", state, task.Text, task.NoteSlug)
+		return nil
+	},
+}
+
+func taskBox(t *model.Task) string {
+	if t.Checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// formatTask renders a task's text followed by its priority, due date and
+// the heading it sits under.
+func formatTask(t *model.Task, now time.Time) string {
+	parts := []string{t.Text}
+	if t.Priority != "" {
+		parts = append(parts, "!"+string(t.Priority))
+	}
+	if t.Due != "" {
+		due := "due " + t.Due
+		if t.IsOverdue(now) {
+			due += " (overdue)"
+		}
+		parts = append(parts, due)
+	}
+	if t.Context != "" {
+		parts = append(parts, "— "+t.Context)
+	}
+	return strings.Join(parts, "  ")
+}
+
+func init() {
+	taskCmd.Flags().Bool("open", false, "Only show unchecked tasks")
+	taskCmd.Flags().StringP("workspace", "w", "", "Only show tasks from notes in this workspace")
+	taskCmd.Flags().StringP("tag", "t", "", "Only show tasks from notes with this tag, including nested tags")
+
+	taskCmd.AddCommand(taskToggleCmd)
+	rootCmd.AddCommand(taskCmd)
+}
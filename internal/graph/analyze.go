@@ -0,0 +1,497 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Ranked is a node with a score from one of the centrality measures.
+type Ranked struct {
+	ID    string  `json:"id"`
+	Label string  `json:"label"`
+	Type  string  `json:"type"`
+	Score float64 `json:"score"`
+}
+
+// Group is a set of nodes: a community or a connected component.
+type Group struct {
+	Size  int      `json:"size"`
+	Nodes []string `json:"nodes"`
+}
+
+// Analysis reports the structure of a graph: hubs by degree and PageRank,
+// bridges by betweenness, communities and connected components.
+type Analysis struct {
+	Nodes       int      `json:"nodes"`
+	Edges       int      `json:"edges"`
+	ByDegree    []Ranked `json:"by_degree"`
+	ByPageRank  []Ranked `json:"by_pagerank"`
+	Bridges     []Ranked `json:"bridges"`
+	Communities []Group  `json:"communities"`
+	Components  []Group  `json:"components"`
+}
+
+// PathStep is one node on a path; Forward tells whether the link into it
+// points along the path or back against it.
+type PathStep struct {
+	Node
+	Forward bool `json:"forward"`
+}
+
+// Analyze computes the analysis of g, keeping the top limit nodes of each
+// ranking. Communities and components list node IDs, largest first.
+func (g *GraphData) Analyze(limit int) *Analysis {
+	a := &Analysis{Nodes: len(g.Nodes), Edges: len(g.Edges)}
+
+	degree := make(map[string]float64)
+	for _, n := range g.Nodes {
+		degree[n.ID] = float64(n.Connections)
+	}
+	a.ByDegree = g.rank(degree, limit)
+	a.ByPageRank = g.rank(g.PageRank(), limit)
+	a.Bridges = g.rank(g.Betweenness(), limit)
+
+	for _, members := range groupBy(g.Communities()) {
+		a.Communities = append(a.Communities, Group{Size: len(members), Nodes: members})
+	}
+	for _, members := range groupBy(g.Components()) {
+		a.Components = append(a.Components, Group{Size: len(members), Nodes: members})
+	}
+	if a.Communities == nil {
+		a.Communities = []Group{}
+	}
+	if a.Components == nil {
+		a.Components = []Group{}
+	}
+	return a
+}
+
+// rank sorts the nodes with a positive score, highest first, and keeps
+// the top limit; a limit of zero keeps them all.
+func (g *GraphData) rank(scores map[string]float64, limit int) []Ranked {
+	ranked := []Ranked{}
+	for _, n := range g.Nodes {
+		if s := scores[n.ID]; s > 0 {
+			ranked = append(ranked, Ranked{ID: n.ID, Label: n.Label, Type: n.Type, Score: s})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Label < ranked[j].Label
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// neighbors lists the nodes joined to each node by an edge in either
+// direction, in the order the edges appear.
+func (g *GraphData) neighbors() map[string][]string {
+	adj := make(map[string][]string, len(g.Nodes))
+	for _, n := range g.Nodes {
+		adj[n.ID] = nil
+	}
+	for _, e := range g.Edges {
+		if _, ok := adj[e.Source]; !ok {
+			continue
+		}
+		if _, ok := adj[e.Target]; !ok || e.Source == e.Target {
+			continue
+		}
+		adj[e.Source] = append(adj[e.Source], e.Target)
+		adj[e.Target] = append(adj[e.Target], e.Source)
+	}
+	return adj
+}
+
+// PageRank scores nodes by the links pointing at them, following edges
+// in their direction with a damping factor of 0.85. Scores sum to 1.
+func (g *GraphData) PageRank() map[string]float64 {
+	const damping = 0.85
+	n := float64(len(g.Nodes))
+	rank := make(map[string]float64, len(g.Nodes))
+	if n == 0 {
+		return rank
+	}
+	out := make(map[string][]string)
+	for _, e := range g.Edges {
+		out[e.Source] = append(out[e.Source], e.Target)
+	}
+	for _, node := range g.Nodes {
+		rank[node.ID] = 1 / n
+	}
+
+	for range 100 {
+		// Nodes without outgoing links spread their rank over every node.
+		dangling := 0.0
+		for _, node := range g.Nodes {
+			if len(out[node.ID]) == 0 {
+				dangling += rank[node.ID]
+			}
+		}
+		next := make(map[string]float64, len(g.Nodes))
+		for _, node := range g.Nodes {
+			next[node.ID] = (1-damping)/n + damping*dangling/n
+		}
+		for _, node := range g.Nodes {
+			targets := out[node.ID]
+			for _, t := range targets {
+				if _, ok := next[t]; ok {
+					next[t] += damping * rank[node.ID] / float64(len(targets))
+				}
+			}
+		}
+		delta := 0.0
+		for id, r := range next {
+			delta += math.Abs(r - rank[id])
+		}
+		rank = next
+		if delta < 1e-9 {
+			break
+		}
+	}
+	return rank
+}
+
+// Betweenness scores nodes by the share of shortest paths between other
+// nodes that pass through them, treating links as undirected. Scores are
+// normalized to the range 0..1.
+func (g *GraphData) Betweenness() map[string]float64 {
+	adj := g.neighbors()
+	score := make(map[string]float64, len(g.Nodes))
+
+	// Brandes' algorithm: one breadth-first search per source.
+	for _, s := range g.Nodes {
+		var stack []string
+		preds := make(map[string][]string)
+		sigma := map[string]float64{s.ID: 1}
+		dist := map[string]int{s.ID: 0}
+		queue := []string{s.ID}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, w := range adj[v] {
+				if _, ok := dist[w]; !ok {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+		delta := make(map[string]float64)
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s.ID {
+				score[w] += delta[w]
+			}
+		}
+	}
+
+	// Each undirected path was counted from both ends.
+	n := float64(len(g.Nodes))
+	if n > 2 {
+		norm := (n - 1) * (n - 2)
+		for id := range score {
+			score[id] /= norm
+		}
+	}
+	return score
+}
+
+// Communities detects clusters of densely linked nodes with the Louvain
+// method, treating links as undirected: nodes move to the neighboring
+// community that most improves modularity, then communities merge into
+// single nodes and the process repeats. Nodes are visited in ID order so
+// results are stable. The map gives each node's community number,
+// starting at 1 for the largest.
+func (g *GraphData) Communities() map[string]int {
+	ids := make([]string, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	sort.Strings(ids)
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	// weights[i][j] is the weight of the links between i and j; a
+	// self-loop holds the links inside a merged community.
+	weights := make([]map[int]float64, len(ids))
+	for i := range weights {
+		weights[i] = make(map[int]float64)
+	}
+	for _, e := range g.Edges {
+		s, ok1 := index[e.Source]
+		t, ok2 := index[e.Target]
+		if !ok1 || !ok2 || s == t {
+			continue
+		}
+		weights[s][t]++
+		weights[t][s]++
+	}
+
+	member := make([]int, len(ids))
+	for i := range member {
+		member[i] = i
+	}
+	for {
+		comm, moved := louvainMove(weights)
+		if !moved || slices.Max(comm)+1 == len(weights) {
+			break
+		}
+		for i := range member {
+			member[i] = comm[member[i]]
+		}
+		weights = louvainMerge(weights, comm)
+	}
+
+	label := make(map[string]string, len(ids))
+	for i, id := range ids {
+		label[id] = ids[member[i]]
+	}
+	return number(ids, label)
+}
+
+// louvainMove runs the local moving phase over a weighted graph and
+// returns the community of each node, numbered from 0, and whether any
+// node changed community.
+func louvainMove(weights []map[int]float64) ([]int, bool) {
+	n := len(weights)
+	comm := make([]int, n)
+	degree := make([]float64, n)
+	total := make([]float64, n)
+	m2 := 0.0
+	for i, row := range weights {
+		comm[i] = i
+		for j, w := range row {
+			degree[i] += w
+			if i == j {
+				degree[i] += w
+			}
+		}
+		total[i] = degree[i]
+		m2 += degree[i]
+	}
+	if m2 == 0 {
+		return comm, false
+	}
+
+	moved := false
+	for changed := true; changed; {
+		changed = false
+		for i := range n {
+			neighbors := make([]int, 0, len(weights[i]))
+			for j := range weights[i] {
+				neighbors = append(neighbors, j)
+			}
+			sort.Ints(neighbors)
+
+			linksTo := make(map[int]float64)
+			for _, j := range neighbors {
+				if j != i {
+					linksTo[comm[j]] += weights[i][j]
+				}
+			}
+			own := comm[i]
+			total[own] -= degree[i]
+			best, bestGain := own, linksTo[own]-total[own]*degree[i]/m2
+			for _, j := range neighbors {
+				c := comm[j]
+				if gain := linksTo[c] - total[c]*degree[i]/m2; gain > bestGain+1e-12 {
+					best, bestGain = c, gain
+				}
+			}
+			total[best] += degree[i]
+			if best != own {
+				comm[i] = best
+				changed, moved = true, true
+			}
+		}
+	}
+
+	renumber := make(map[int]int)
+	for i, c := range comm {
+		if _, ok := renumber[c]; !ok {
+			renumber[c] = len(renumber)
+		}
+		comm[i] = renumber[c]
+	}
+	return comm, moved
+}
+
+// louvainMerge collapses each community into a single node.
+func louvainMerge(weights []map[int]float64, comm []int) []map[int]float64 {
+	size := 0
+	for _, c := range comm {
+		size = max(size, c+1)
+	}
+	merged := make([]map[int]float64, size)
+	for i := range merged {
+		merged[i] = make(map[int]float64)
+	}
+	for i, row := range weights {
+		for j, w := range row {
+			if i == j {
+				w *= 2
+			}
+			merged[comm[i]][comm[j]] += w
+		}
+	}
+	// Links inside a community were counted from both ends.
+	for i := range merged {
+		merged[i][i] /= 2
+		if merged[i][i] == 0 {
+			delete(merged[i], i)
+		}
+	}
+	return merged
+}
+
+// Components finds the connected components of the graph, treating links
+// as undirected. The map gives each node's component number, starting at
+// 1 for the largest.
+func (g *GraphData) Components() map[string]int {
+	adj := g.neighbors()
+	ids := make([]string, 0, len(g.Nodes))
+	label := make(map[string]string, len(g.Nodes))
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if _, ok := label[id]; ok {
+			continue
+		}
+		label[id] = id
+		queue := []string{id}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			for _, w := range adj[v] {
+				if _, ok := label[w]; !ok {
+					label[w] = id
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+	return number(ids, label)
+}
+
+// number turns group labels into numbers from 1, largest group first.
+func number(ids []string, label map[string]string) map[string]int {
+	size := make(map[string]int)
+	var labels []string
+	for _, id := range ids {
+		if size[label[id]] == 0 {
+			labels = append(labels, label[id])
+		}
+		size[label[id]]++
+	}
+	sort.SliceStable(labels, func(i, j int) bool { return size[labels[i]] > size[labels[j]] })
+	num := make(map[string]int, len(labels))
+	for i, l := range labels {
+		num[l] = i + 1
+	}
+	groups := make(map[string]int, len(ids))
+	for _, id := range ids {
+		groups[id] = num[label[id]]
+	}
+	return groups
+}
+
+// groupBy lists the members of each numbered group, in number order.
+func groupBy(groups map[string]int) [][]string {
+	count := 0
+	for _, n := range groups {
+		count = max(count, n)
+	}
+	lists := make([][]string, count)
+	for id, n := range groups {
+		lists[n-1] = append(lists[n-1], id)
+	}
+	for _, l := range lists {
+		sort.Strings(l)
+	}
+	return lists
+}
+
+// Node returns the node with the given ID.
+func (g *GraphData) Node(id string) (Node, bool) {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return Node{}, false
+}
+
+// ShortestPath finds the shortest chain of links from one node to
+// another, following links in either direction. The first step is the
+// starting node.
+func (g *GraphData) ShortestPath(from, to string) ([]PathStep, error) {
+	start, ok := g.Node(from)
+	if !ok {
+		return nil, fmt.Errorf("%q is not in the graph", from)
+	}
+	if _, ok := g.Node(to); !ok {
+		return nil, fmt.Errorf("%q is not in the graph", to)
+	}
+
+	forward := make(map[[2]string]bool)
+	for _, e := range g.Edges {
+		forward[[2]string{e.Source, e.Target}] = true
+	}
+	adj := g.neighbors()
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 && !hasKey(prev, to) {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range adj[v] {
+			if !hasKey(prev, w) {
+				prev[w] = v
+				queue = append(queue, w)
+			}
+		}
+	}
+	if !hasKey(prev, to) {
+		return nil, fmt.Errorf("no path between %s and %s", start.Label, g.label(to))
+	}
+
+	var ids []string
+	for id := to; id != from; id = prev[id] {
+		ids = append(ids, id)
+	}
+	path := []PathStep{{Node: start, Forward: true}}
+	for i := len(ids) - 1; i >= 0; i-- {
+		n, _ := g.Node(ids[i])
+		last := path[len(path)-1].ID
+		path = append(path, PathStep{Node: n, Forward: forward[[2]string{last, n.ID}]})
+	}
+	return path, nil
+}
+
+func (g *GraphData) label(id string) string {
+	n, _ := g.Node(id)
+	return n.Label
+}
+
+func hasKey(m map[string]string, k string) bool {
+	_, ok := m[k]
+	return ok
+}
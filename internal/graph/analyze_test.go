@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"math"
+	"testing"
+)
+
+// twoTriangles builds two triangles a-b-c and x-y-z joined by the edge
+// c -> x, plus an isolated node o.
+func twoTriangles() *GraphData {
+	g := &GraphData{}
+	for _, id := range []string{"a", "b", "c", "x", "y", "z", "o"} {
+		g.Nodes = append(g.Nodes, Node{ID: id, Label: id, Type: TypeNote})
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"x", "y"}, {"y", "z"}, {"z", "x"}, {"c", "x"}} {
+		g.Edges = append(g.Edges, Edge{Source: e[0], Target: e[1], Type: EdgeLink})
+	}
+	count := make(map[string]int)
+	for _, e := range g.Edges {
+		count[e.Source]++
+		count[e.Target]++
+	}
+	for i := range g.Nodes {
+		g.Nodes[i].Connections = count[g.Nodes[i].ID]
+	}
+	return g
+}
+
+func TestPageRank(t *testing.T) {
+	g := &GraphData{
+		Nodes: []Node{{ID: "hub"}, {ID: "a"}, {ID: "b"}, {ID: "c"}},
+		Edges: []Edge{{Source: "a", Target: "hub"}, {Source: "b", Target: "hub"}, {Source: "c", Target: "hub"}},
+	}
+	rank := g.PageRank()
+	sum := 0.0
+	for _, r := range rank {
+		sum += r
+	}
+	if math.Abs(sum-1) > 1e-6 {
+		t.Errorf("ranks should sum to 1, got %f", sum)
+	}
+	if rank["hub"] <= rank["a"] {
+		t.Errorf("the linked-to node should rank highest: %v", rank)
+	}
+}
+
+func TestBetweenness(t *testing.T) {
+	g := twoTriangles()
+	score := g.Betweenness()
+	if score["c"] <= score["a"] || score["x"] <= score["y"] {
+		t.Errorf("the nodes joining the triangles should be bridges: %v", score)
+	}
+	if score["a"] != 0 || score["o"] != 0 {
+		t.Errorf("expected no betweenness for a and o: %v", score)
+	}
+}
+
+func TestCommunitiesAndComponents(t *testing.T) {
+	g := twoTriangles()
+
+	communities := g.Communities()
+	if communities["a"] != communities["b"] || communities["a"] != communities["c"] {
+		t.Errorf("a, b and c should share a community: %v", communities)
+	}
+	if communities["x"] != communities["y"] || communities["x"] != communities["z"] {
+		t.Errorf("x, y and z should share a community: %v", communities)
+	}
+	if communities["a"] == communities["x"] || communities["o"] != 3 {
+		t.Errorf("expected three communities: %v", communities)
+	}
+
+	components := g.Components()
+	if components["a"] != 1 || components["z"] != 1 || components["o"] != 2 {
+		t.Errorf("unexpected components: %v", components)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	a := twoTriangles().Analyze(2)
+	if a.Nodes != 7 || a.Edges != 7 {
+		t.Errorf("unexpected counts: %+v", a)
+	}
+	if len(a.ByDegree) != 2 || a.ByDegree[0].Label != "c" || a.ByDegree[0].Score != 3 {
+		t.Errorf("unexpected hubs: %+v", a.ByDegree)
+	}
+	if len(a.Bridges) != 2 || a.Bridges[0].Label != "c" {
+		t.Errorf("unexpected bridges: %+v", a.Bridges)
+	}
+	if len(a.Communities) != 3 || a.Communities[0].Size != 3 {
+		t.Errorf("unexpected communities: %+v", a.Communities)
+	}
+	if len(a.Components) != 2 || a.Components[0].Size != 6 || a.Components[1].Nodes[0] != "o" {
+		t.Errorf("unexpected components: %+v", a.Components)
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	g := twoTriangles()
+
+	path, err := g.ShortestPath("a", "y")
+	if err != nil {
+		t.Fatalf("ShortestPath: %v", err)
+	}
+	var ids []string
+	for _, s := range path {
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 4 || ids[0] != "a" || ids[1] != "c" || ids[2] != "x" || ids[3] != "y" {
+		t.Fatalf("unexpected path: %v", ids)
+	}
+	if path[1].Forward || !path[2].Forward {
+		t.Errorf("c links to a, and c links to x: %+v", path)
+	}
+
+	if _, err := g.ShortestPath("a", "o"); err == nil {
+		t.Error("expected no path to an isolated node")
+	}
+	if _, err := g.ShortestPath("a", "missing"); err == nil {
+		t.Error("expected error for a node outside the graph")
+	}
+}
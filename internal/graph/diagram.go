@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxDepth is the deepest neighborhood the TUI graph view will draw.
+const MaxDepth = 3
+
+// Neighborhood returns the nodes within depth links of the node id, in
+// either direction, and the edges between them. Connections count only
+// edges inside the neighborhood.
+func (g *GraphData) Neighborhood(id string, depth int) (*GraphData, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("depth must be at least 1")
+	}
+	if _, ok := g.Node(id); !ok {
+		return nil, fmt.Errorf("%q is not in the graph", id)
+	}
+
+	adj := g.neighbors()
+	dist := map[string]int{id: 0}
+	queue := []string{id}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if dist[v] == depth {
+			continue
+		}
+		for _, w := range adj[v] {
+			if _, ok := dist[w]; !ok {
+				dist[w] = dist[v] + 1
+				queue = append(queue, w)
+			}
+		}
+	}
+
+	return g.Subgraph(func(n Node) bool {
+		_, ok := dist[n.ID]
+		return ok
+	}), nil
+}
+
+// DiagramLine is one line of a neighborhood diagram. Prefix holds the
+// box-drawing connectors and the link arrow; NodeID is empty on lines
+// that only draw the frame around the center.
+type DiagramLine struct {
+	Prefix string
+	NodeID string
+	Label  string
+	Suffix string
+	Parent string
+}
+
+func (l DiagramLine) String() string {
+	return l.Prefix + l.Label + l.Suffix
+}
+
+// Diagram draws g as a tree rooted at center: each node sits under the
+// neighbor that first reached it, with → for a link from the parent, ←
+// for a link back to it and ↔ for both. Links between nodes already in
+// the tree are repeated under the earlier node, marked with ↺.
+func (g *GraphData) Diagram(center string) []DiagramLine {
+	root, ok := g.Node(center)
+	if !ok {
+		return nil
+	}
+	linked := make(map[[2]string]bool)
+	for _, e := range g.Edges {
+		linked[[2]string{e.Source, e.Target}] = true
+	}
+	adj := g.neighbors()
+	label := func(id string) string {
+		n, _ := g.Node(id)
+		return n.Name()
+	}
+
+	// Breadth-first, so every node hangs from its closest neighbor.
+	order := map[string]int{center: 0}
+	children := make(map[string][]string)
+	cross := make(map[string][]string)
+	queue := []string{center}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		next := uniqueSorted(adj[v], label)
+		for _, w := range next {
+			if _, seen := order[w]; !seen {
+				order[w] = len(order)
+				children[v] = append(children[v], w)
+				queue = append(queue, w)
+			}
+		}
+		for _, w := range next {
+			if order[w] > order[v] && !slices.Contains(children[v], w) {
+				cross[v] = append(cross[v], w)
+			}
+		}
+	}
+
+	name := root.Name()
+	width := utf8.RuneCountInString(name) + 2
+	bottom := "╰" + strings.Repeat("─", width) + "╯"
+	if len(children[center])+len(cross[center]) > 0 {
+		bottom = "╰─┬" + strings.Repeat("─", width-2) + "╯"
+	}
+	lines := []DiagramLine{
+		{Prefix: "╭" + strings.Repeat("─", width) + "╮"},
+		{Prefix: "│ ", NodeID: center, Label: name, Suffix: " │"},
+		{Prefix: bottom},
+	}
+
+	var walk func(id, indent string)
+	walk = func(id, indent string) {
+		type entry struct {
+			id    string
+			cross bool
+		}
+		var entries []entry
+		for _, c := range children[id] {
+			entries = append(entries, entry{c, false})
+		}
+		for _, c := range cross[id] {
+			entries = append(entries, entry{c, true})
+		}
+		for i, e := range entries {
+			branch, more := "├─", "│ "
+			if i == len(entries)-1 {
+				branch, more = "└─", "  "
+			}
+			arrow := "→"
+			switch out, in := linked[[2]string{id, e.id}], linked[[2]string{e.id, id}]; {
+			case out && in:
+				arrow = "↔"
+			case in:
+				arrow = "←"
+			}
+			line := DiagramLine{Prefix: indent + branch + arrow + " ", NodeID: e.id, Label: label(e.id), Parent: id}
+			if e.cross {
+				line.Suffix = " ↺"
+				lines = append(lines, line)
+				continue
+			}
+			lines = append(lines, line)
+			walk(e.id, indent+more)
+		}
+	}
+	walk(center, "  ")
+	return lines
+}
+
+// RenderDiagram joins the lines of a diagram into text.
+func RenderDiagram(lines []DiagramLine) string {
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Name labels a node for text output, naming the type of anything but a
+// note.
+func (n Node) Name() string {
+	if n.Type == TypeNote || n.Type == "" {
+		return n.Label
+	}
+	return fmt.Sprintf("%s [%s]", n.Label, n.Type)
+}
+
+func uniqueSorted(ids []string, label func(string) string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		li, lj := strings.ToLower(label(out[i])), strings.ToLower(label(out[j]))
+		if li != lj {
+			return li < lj
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestNeighborhood(t *testing.T) {
+	g := twoTriangles()
+
+	sub, err := g.Neighborhood("a", 1)
+	if err != nil {
+		t.Fatalf("Neighborhood: %v", err)
+	}
+	if len(sub.Nodes) != 3 || len(sub.Edges) != 3 {
+		t.Errorf("expected the a-b-c triangle, got %+v", sub)
+	}
+	sub, _ = g.Neighborhood("a", 2)
+	if len(sub.Nodes) != 4 {
+		t.Errorf("expected x at depth 2, got %+v", sub.Nodes)
+	}
+	for _, n := range sub.Nodes {
+		if n.ID == "x" && n.Connections != 1 {
+			t.Errorf("x should count only edges inside the neighborhood, got %d", n.Connections)
+		}
+	}
+
+	if sub, _ := g.Neighborhood("a", 4); len(sub.Nodes) != 6 {
+		t.Errorf("expected depth 4 to reach both triangles, got %+v", sub.Nodes)
+	}
+	if _, err := g.Neighborhood("a", 0); err == nil {
+		t.Error("expected error for depth 0")
+	}
+	if _, err := g.Neighborhood("missing", 1); err == nil {
+		t.Error("expected error for a node outside the graph")
+	}
+}
+
+func TestDiagram(t *testing.T) {
+	g := twoTriangles()
+	g.Nodes[5].Type = TypeCard
+	sub, _ := g.Neighborhood("c", 2)
+
+	lines := sub.Diagram("c")
+	want := `╭───╮
+│ c │
+╰─┬─╯
+  ├─→ a
+  │ └─→ b ↺
+  ├─← b
+  └─→ x
+    ├─→ y
+    │ └─→ z [card] ↺
+    └─← z [card]
+`
+	if got := RenderDiagram(lines); got != want {
+		t.Errorf("diagram:\n%s\nwant:\n%s", got, want)
+	}
+	if lines[1].NodeID != "c" || lines[0].NodeID != "" {
+		t.Errorf("expected the center on the second line, got %+v", lines[:2])
+	}
+	if lines[7].NodeID != "y" || lines[7].Parent != "x" {
+		t.Errorf("unexpected line for y: %+v", lines[7])
+	}
+
+	if got := sub.Diagram("o"); got != nil {
+		t.Errorf("expected no diagram for a node outside the graph, got %v", got)
+	}
+}
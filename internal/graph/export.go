@@ -0,0 +1,249 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Export formats.
+const (
+	FormatGraphML = "graphml"
+	FormatGEXF    = "gexf"
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// Formats lists every export format.
+var Formats = []string{FormatGraphML, FormatGEXF, FormatDOT, FormatMermaid}
+
+// Export serializes the graph in one of the Formats, for tools such as
+// Gephi and Graphviz or for embedding in Markdown.
+func Export(data *GraphData, format, title string) (string, error) {
+	switch strings.ToLower(format) {
+	case FormatGraphML:
+		return exportGraphML(data, title), nil
+	case FormatGEXF:
+		return exportGEXF(data, title), nil
+	case FormatDOT:
+		return exportDOT(data, title), nil
+	case FormatMermaid:
+		return exportMermaid(data, title), nil
+	}
+	return "", fmt.Errorf("unknown format %q: use %s", format, strings.Join(Formats, ", "))
+}
+
+// xmlEscape escapes text for XML content and attribute values.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// nodeAttrs are the attributes exported for every node, in order.
+var nodeAttrs = []struct {
+	name, kind string
+	value      func(Node) string
+}{
+	{"type", "string", func(n Node) string { return n.Type }},
+	{"slug", "string", func(n Node) string { return n.Slug }},
+	{"board", "string", func(n Node) string { return n.Board }},
+	{"workspace", "string", func(n Node) string { return n.Workspace }},
+	{"tags", "string", func(n Node) string { return strings.Join(n.Tags, ",") }},
+	{"connections", "int", func(n Node) string { return fmt.Sprint(n.Connections) }},
+}
+
+var edgeAttrs = []struct {
+	name  string
+	value func(Edge) string
+}{
+	{"type", func(e Edge) string { return e.Type }},
+	{"relation", func(e Edge) string { return e.Relation }},
+	{"context", func(e Edge) string { return e.Context }},
+}
+
+func exportGraphML(data *GraphData, title string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">
+  <key id="label" for="node" attr.name="label" attr.type="string"/>
+`)
+	for _, a := range nodeAttrs {
+		fmt.Fprintf(&b, "  <key id=\"%s\" for=\"node\" attr.name=\"%s\" attr.type=\"%s\"/>\n", a.name, a.name, a.kind)
+	}
+	for _, a := range edgeAttrs {
+		fmt.Fprintf(&b, "  <key id=\"edge_%s\" for=\"edge\" attr.name=\"%s\" attr.type=\"string\"/>\n", a.name, a.name)
+	}
+	fmt.Fprintf(&b, "  <graph id=\"%s\" edgedefault=\"directed\">\n", xmlEscape(title))
+	for _, n := range data.Nodes {
+		fmt.Fprintf(&b, "    <node id=\"%s\">\n      <data key=\"label\">%s</data>\n", xmlEscape(n.ID), xmlEscape(n.Label))
+		for _, a := range nodeAttrs {
+			if v := a.value(n); v != "" {
+				fmt.Fprintf(&b, "      <data key=\"%s\">%s</data>\n", a.name, xmlEscape(v))
+			}
+		}
+		b.WriteString("    </node>\n")
+	}
+	for i, e := range data.Edges {
+		fmt.Fprintf(&b, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, xmlEscape(e.Source), xmlEscape(e.Target))
+		for _, a := range edgeAttrs {
+			if v := a.value(e); v != "" {
+				fmt.Fprintf(&b, "      <data key=\"edge_%s\">%s</data>\n", a.name, xmlEscape(v))
+			}
+		}
+		b.WriteString("    </edge>\n")
+	}
+	b.WriteString("  </graph>\n</graphml>\n")
+	return b.String()
+}
+
+func exportGEXF(data *GraphData, title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <meta>
+    <creator>kb</creator>
+    <description>%s</description>
+  </meta>
+  <graph defaultedgetype="directed" mode="static">
+    <attributes class="node">
+`, xmlEscape(title))
+	for i, a := range nodeAttrs {
+		kind := a.kind
+		if kind == "int" {
+			kind = "integer"
+		}
+		fmt.Fprintf(&b, "      <attribute id=\"%d\" title=\"%s\" type=\"%s\"/>\n", i, a.name, kind)
+	}
+	b.WriteString("    </attributes>\n    <attributes class=\"edge\">\n")
+	for i, a := range edgeAttrs {
+		fmt.Fprintf(&b, "      <attribute id=\"%d\" title=\"%s\" type=\"string\"/>\n", i, a.name)
+	}
+	b.WriteString("    </attributes>\n    <nodes>\n")
+	for _, n := range data.Nodes {
+		fmt.Fprintf(&b, "      <node id=\"%s\" label=\"%s\">\n        <attvalues>\n", xmlEscape(n.ID), xmlEscape(n.Label))
+		for i, a := range nodeAttrs {
+			if v := a.value(n); v != "" {
+				fmt.Fprintf(&b, "          <attvalue for=\"%d\" value=\"%s\"/>\n", i, xmlEscape(v))
+			}
+		}
+		b.WriteString("        </attvalues>\n      </node>\n")
+	}
+	b.WriteString("    </nodes>\n    <edges>\n")
+	for i, e := range data.Edges {
+		fmt.Fprintf(&b, "      <edge id=\"%d\" source=\"%s\" target=\"%s\">\n        <attvalues>\n", i, xmlEscape(e.Source), xmlEscape(e.Target))
+		for j, a := range edgeAttrs {
+			if v := a.value(e); v != "" {
+				fmt.Fprintf(&b, "          <attvalue for=\"%d\" value=\"%s\"/>\n", j, xmlEscape(v))
+			}
+		}
+		b.WriteString("        </attvalues>\n      </edge>\n")
+	}
+	b.WriteString("    </edges>\n  </graph>\n</gexf>\n")
+	return b.String()
+}
+
+// dotQuote quotes s as a DOT string.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+var dotShapes = map[string]string{TypeNote: "ellipse", TypeCard: "box", TypeBoard: "diamond"}
+
+func exportDOT(data *GraphData, title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(title))
+	for _, n := range data.Nodes {
+		attrs := []string{"label=" + dotQuote(n.Label)}
+		if shape, ok := dotShapes[n.Type]; ok {
+			attrs = append(attrs, "shape="+shape)
+		}
+		for _, a := range nodeAttrs {
+			if v := a.value(n); v != "" {
+				attrs = append(attrs, a.name+"="+dotQuote(v))
+			}
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range data.Edges {
+		var attrs []string
+		switch {
+		case e.Type == EdgeMember:
+			attrs = append(attrs, "style=dashed")
+		case e.Relation != "":
+			attrs = append(attrs, "label="+dotQuote(e.Relation), "color="+dotQuote(relationColor(e.Relation)))
+		}
+		for _, a := range edgeAttrs {
+			if v := a.value(e); v != "" {
+				attrs = append(attrs, a.name+"="+dotQuote(v))
+			}
+		}
+		fmt.Fprintf(&b, "  %s -> %s", dotQuote(e.Source), dotQuote(e.Target))
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidText escapes s for a quoted Mermaid label using entity codes.
+func mermaidText(s string) string {
+	r := strings.NewReplacer("#", "#35;", `"`, "#quot;", "<", "#lt;", ">", "#gt;", "\r", "", "\n", " ")
+	return r.Replace(s)
+}
+
+func exportMermaid(data *GraphData, title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntitle: \"%s\"\n---\nflowchart LR\n", mermaidText(title))
+
+	// Mermaid IDs are restricted, so nodes are numbered.
+	ids := make(map[string]string, len(data.Nodes))
+	classes := make(map[string][]string)
+	for i, n := range data.Nodes {
+		id := fmt.Sprintf("n%d", i+1)
+		ids[n.ID] = id
+		label := mermaidText(n.Label)
+		switch n.Type {
+		case TypeCard:
+			fmt.Fprintf(&b, "  %s(\"%s\")\n", id, label)
+		case TypeBoard:
+			fmt.Fprintf(&b, "  %s{\"%s\"}\n", id, label)
+		default:
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, label)
+		}
+		if n.Type != "" {
+			classes[n.Type] = append(classes[n.Type], id)
+		}
+	}
+	for _, e := range data.Edges {
+		s, ok1 := ids[e.Source]
+		t, ok2 := ids[e.Target]
+		if !ok1 || !ok2 {
+			continue
+		}
+		arrow := "-->"
+		if e.Type == EdgeMember {
+			arrow = "-.->"
+		}
+		label := e.Context
+		if e.Relation != "" {
+			label = e.Relation
+		}
+		if label != "" {
+			fmt.Fprintf(&b, "  %s %s|\"%s\"| %s\n", s, arrow, mermaidText(label), t)
+		} else {
+			fmt.Fprintf(&b, "  %s %s %s\n", s, arrow, t)
+		}
+	}
+	for _, t := range Types {
+		if len(classes[t]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "  classDef %sNode fill:%s,color:#1a1b26\n", t, nodeColor(Node{Type: t}))
+		fmt.Fprintf(&b, "  class %s %sNode\n", strings.Join(classes[t], ","), t)
+	}
+	return b.String()
+}
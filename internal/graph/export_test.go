@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func exportGraph() *GraphData {
+	return &GraphData{
+		Nodes: []Node{
+			{ID: "n1", Label: `Say "hi" <now> & #1`, Type: TypeNote, Slug: "say-hi", Workspace: "R&D", Tags: []string{"go", "db"}, Connections: 1},
+			{ID: "c1", Label: "Fix\\login", Type: TypeCard, Board: "Sprint", Connections: 2},
+			{ID: "b1", Label: "Sprint", Type: TypeBoard, Connections: 1},
+		},
+		Edges: []Edge{
+			{Source: "c1", Target: "n1", Type: EdgeLink, Context: `see "design" <here>`},
+			{Source: "c1", Target: "b1", Type: EdgeMember},
+		},
+	}
+}
+
+func TestExportXMLFormats(t *testing.T) {
+	for _, format := range []string{FormatGraphML, FormatGEXF} {
+		out, err := Export(exportGraph(), format, "kb & co")
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		dec := xml.NewDecoder(strings.NewReader(out))
+		for {
+			_, err := dec.Token()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					t.Errorf("%s is not well-formed XML: %v\n%s", format, err, out)
+				}
+				break
+			}
+		}
+		for _, want := range []string{"Say &#34;hi&#34; &lt;now&gt; &amp; #1", "R&amp;D", "go,db", "see &#34;design&#34; &lt;here&gt;", "member"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: missing %q in\n%s", format, want, out)
+			}
+		}
+	}
+
+	out, _ := Export(exportGraph(), FormatGraphML, "kb")
+	for _, want := range []string{`<key id="connections" for="node" attr.name="connections" attr.type="int"/>`, `<edge id="e0" source="c1" target="n1">`, `<data key="type">card</data>`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("graphml: missing %q", want)
+		}
+	}
+	out, _ = Export(exportGraph(), FormatGEXF, "kb")
+	for _, want := range []string{`<attribute id="5" title="connections" type="integer"/>`, `<node id="b1" label="Sprint">`, `<attvalue for="0" value="board"/>`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("gexf: missing %q", want)
+		}
+	}
+}
+
+func TestExportDOT(t *testing.T) {
+	out, err := Export(exportGraph(), "DOT", `kb "graph"`)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	for _, want := range []string{
+		`digraph "kb \"graph\"" {`,
+		`"n1" [label="Say \"hi\" <now> & #1", shape=ellipse, type="note", slug="say-hi", workspace="R&D", tags="go,db", connections="1"];`,
+		`"c1" [label="Fix\\login", shape=box`,
+		`"c1" -> "n1" [type="link", context="see \"design\" <here>"];`,
+		`"c1" -> "b1" [style=dashed, type="member"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestExportMermaid(t *testing.T) {
+	out, err := Export(exportGraph(), FormatMermaid, "kb")
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	for _, want := range []string{
+		"flowchart LR",
+		`n1["Say #quot;hi#quot; #lt;now#gt; & #35;1"]`,
+		`n2("Fix\login")`,
+		`n3{"Sprint"}`,
+		`n2 -->|"see #quot;design#quot; #lt;here#gt;"| n1`,
+		"n2 -.-> n3",
+		"class n2 cardNode",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if _, err := Export(exportGraph(), "csv", "kb"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
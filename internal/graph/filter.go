@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// Times a node can be filtered on.
+const (
+	TimeCreated = "created"
+	TimeUpdated = "updated"
+)
+
+// Subgraph keeps the nodes for which keep returns true, plus the nodes
+// listed in ids, and the edges between them. Connections count only the
+// edges that remain.
+func (g *GraphData) Subgraph(keep func(Node) bool, ids ...string) *GraphData {
+	in := make(map[string]bool, len(g.Nodes))
+	for _, id := range ids {
+		in[id] = true
+	}
+	for _, n := range g.Nodes {
+		if keep(n) {
+			in[n.ID] = true
+		}
+	}
+
+	sub := &GraphData{Nodes: []Node{}, Edges: []Edge{}}
+	count := make(map[string]int)
+	for _, e := range g.Edges {
+		if in[e.Source] && in[e.Target] {
+			sub.Edges = append(sub.Edges, e)
+			count[e.Source]++
+			count[e.Target]++
+		}
+	}
+	for _, n := range g.Nodes {
+		if in[n.ID] {
+			n.Connections = count[n.ID]
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	return sub
+}
+
+// FilterTags keeps the nodes with at least one of tags, or every node when
+// tags is empty, and drops those with any of exclude. Tags match nested
+// tags too, so "area" matches "area/backend". Card labels count as tags.
+func (g *GraphData) FilterTags(tags, exclude []string, keep ...string) *GraphData {
+	return g.Subgraph(func(n Node) bool {
+		return (len(tags) == 0 || hasTag(n, tags)) && !hasTag(n, exclude)
+	}, keep...)
+}
+
+func hasTag(n Node, filters []string) bool {
+	for _, t := range n.Tags {
+		for _, f := range filters {
+			if model.TagMatches(t, f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilterTime keeps the nodes created or updated, as field says, between
+// since and until. A zero since or until leaves that end open.
+func (g *GraphData) FilterTime(field string, since, until time.Time, keep ...string) (*GraphData, error) {
+	if field != TimeCreated && field != TimeUpdated {
+		return nil, fmt.Errorf("unknown time %q: use %s or %s", field, TimeCreated, TimeUpdated)
+	}
+	return g.Subgraph(func(n Node) bool {
+		t := n.UpdatedAt
+		if field == TimeCreated {
+			t = n.CreatedAt
+		}
+		return !t.IsZero() && (since.IsZero() || !t.Before(since)) && (until.IsZero() || t.Before(until))
+	}, keep...), nil
+}
+
+// MinConnections keeps the nodes with at least min connections in g.
+func (g *GraphData) MinConnections(min int, keep ...string) *GraphData {
+	return g.Subgraph(func(n Node) bool { return n.Connections >= min }, keep...)
+}
@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"testing"
+	"time"
+)
+
+func day(d int) time.Time {
+	return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC)
+}
+
+func filterGraph() *GraphData {
+	return &GraphData{
+		Nodes: []Node{
+			{ID: "a", Label: "A", Type: TypeNote, Tags: []string{"area/backend"}, CreatedAt: day(1), UpdatedAt: day(10), Connections: 2},
+			{ID: "b", Label: "B", Type: TypeNote, Tags: []string{"area/frontend", "draft"}, CreatedAt: day(2), UpdatedAt: day(3), Connections: 2},
+			{ID: "c", Label: "C", Type: TypeNote, Tags: []string{"ops"}, CreatedAt: day(5), UpdatedAt: day(6), Connections: 1},
+			{ID: "d", Label: "D", Type: TypeCard, Tags: []string{"Area"}, CreatedAt: day(7), UpdatedAt: day(8), Connections: 1},
+			{ID: "e", Label: "E", Type: TypeNote},
+		},
+		Edges: []Edge{
+			{Source: "a", Target: "b", Type: EdgeLink},
+			{Source: "b", Target: "c", Type: EdgeLink},
+			{Source: "d", Target: "a", Type: EdgeLink},
+		},
+	}
+}
+
+func nodeIDs(g *GraphData) []string {
+	var ids []string
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func TestSubgraphRecountsConnections(t *testing.T) {
+	sub := filterGraph().Subgraph(func(n Node) bool { return n.ID != "b" }, "e")
+	if got := nodeIDs(sub); len(got) != 4 {
+		t.Fatalf("nodes = %v", got)
+	}
+	if len(sub.Edges) != 1 || sub.Nodes[0].Connections != 1 || sub.Nodes[1].Connections != 0 {
+		t.Errorf("expected only d→a to remain, got %+v", sub)
+	}
+}
+
+func TestFilterTags(t *testing.T) {
+	tests := []struct {
+		tags, exclude []string
+		want          string
+	}{
+		{[]string{"area"}, nil, "abd"},
+		{[]string{"#Area/Backend"}, nil, "a"},
+		{[]string{"ops", "area/frontend"}, nil, "bc"},
+		{nil, []string{"draft"}, "acde"},
+		{[]string{"area"}, []string{"draft"}, "ad"},
+	}
+	for _, tt := range tests {
+		got := ""
+		for _, id := range nodeIDs(filterGraph().FilterTags(tt.tags, tt.exclude)) {
+			got += id
+		}
+		if got != tt.want {
+			t.Errorf("FilterTags(%v, %v) = %s, want %s", tt.tags, tt.exclude, got, tt.want)
+		}
+	}
+
+	if got := nodeIDs(filterGraph().FilterTags([]string{"ops"}, nil, "a")); len(got) != 2 {
+		t.Errorf("expected the kept node too, got %v", got)
+	}
+}
+
+func TestFilterTime(t *testing.T) {
+	sub, err := filterGraph().FilterTime(TimeUpdated, day(6), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := nodeIDs(sub); len(got) != 3 || got[0] != "a" || got[1] != "c" || got[2] != "d" {
+		t.Errorf("updated since day 6 = %v", got)
+	}
+
+	sub, _ = filterGraph().FilterTime(TimeCreated, time.Time{}, day(5))
+	if got := nodeIDs(sub); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("created before day 5 = %v", got)
+	}
+
+	if _, err := filterGraph().FilterTime("touched", day(1), time.Time{}); err == nil {
+		t.Error("expected an error for an unknown time")
+	}
+}
+
+func TestMinConnections(t *testing.T) {
+	sub := filterGraph().MinConnections(2)
+	if got := nodeIDs(sub); len(got) != 2 || len(sub.Edges) != 1 {
+		t.Errorf("nodes = %v, edges = %v", got, sub.Edges)
+	}
+	if got := nodeIDs(filterGraph().MinConnections(2, "e")); len(got) != 3 {
+		t.Errorf("expected the kept orphan too, got %v", got)
+	}
+}
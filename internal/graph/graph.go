@@ -1,22 +1,50 @@
 package graph
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/jeryldev/kb/internal/model"
 )
 
+// Node types.
+const (
+	TypeNote  = "note"
+	TypeCard  = "card"
+	TypeBoard = "board"
+)
+
+// Types lists every node type.
+var Types = []string{TypeNote, TypeCard, TypeBoard}
+
+// Edge types: wikilinks, and a card's membership of its board.
+const (
+	EdgeLink   = "link"
+	EdgeMember = "member"
+)
+
 type Node struct {
-	ID          string `json:"id"`
-	Label       string `json:"label"`
-	Type        string `json:"type"`
-	Slug        string `json:"slug,omitempty"`
-	WorkspaceID string `json:"workspace_id,omitempty"`
-	Connections int    `json:"connections"`
+	ID          string    `json:"id"`
+	Label       string    `json:"label"`
+	Type        string    `json:"type"`
+	Slug        string    `json:"slug,omitempty"`
+	Board       string    `json:"board,omitempty"`
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	Workspace   string    `json:"workspace,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	Connections int       `json:"connections"`
+	Community   int       `json:"community,omitempty"`
 }
 
 type Edge struct {
-	Source  string `json:"source"`
-	Target string `json:"target"`
-	Context string `json:"context,omitempty"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Context  string `json:"context,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Relation string `json:"relation,omitempty"`
 }
 
 type GraphData struct {
@@ -28,24 +56,168 @@ type DataSource interface {
 	ListNotes() ([]*model.Note, error)
 	ListNotesByWorkspace(workspaceID string) ([]*model.Note, error)
 	ListAllLinks() ([]*model.Link, error)
+	ListBoards() ([]*model.Board, error)
+	ListBoardsByWorkspace(workspaceID string) ([]*model.Board, error)
+	ListBoardCards(boardID string) ([]*model.Card, error)
+	ListWorkspaces() ([]*model.Workspace, error)
 }
 
-func BuildGraph(ds DataSource, workspaceID string) (*GraphData, error) {
-	var notes []*model.Note
-	var err error
+// ParseTypes reads a comma-separated list of node types such as
+// "note,card".
+func ParseTypes(s string) ([]string, error) {
+	var types []string
+	for _, t := range strings.Split(s, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		t = strings.TrimSuffix(t, "s")
+		valid := false
+		for _, known := range Types {
+			valid = valid || t == known
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown node type %q: use %s", t, strings.Join(Types, ", "))
+		}
+		types = append(types, t)
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no node types given: use %s", strings.Join(Types, ", "))
+	}
+	return types, nil
+}
 
-	if workspaceID != "" {
-		notes, err = ds.ListNotesByWorkspace(workspaceID)
-	} else {
-		notes, err = ds.ListNotes()
+// BuildGraph collects the nodes of the given types, all types by default,
+// and the links between them. Cards are joined to their board by
+// membership edges; links to cards and boards are resolved by ID, card
+// title or board name.
+func BuildGraph(ds DataSource, workspaceID string, types ...string) (*GraphData, error) {
+	if len(types) == 0 {
+		types = Types
+	}
+	include := make(map[string]bool, len(types))
+	for _, t := range types {
+		include[t] = true
 	}
+
+	workspaces, err := ds.ListWorkspaces()
 	if err != nil {
 		return nil, err
 	}
+	wsName := make(map[string]string, len(workspaces))
+	for _, ws := range workspaces {
+		wsName[ws.ID] = ws.Name
+	}
+
+	var nodes []Node
+	var edges []Edge
+	inGraph := make(map[string]bool)
+
+	if include[TypeNote] {
+		var notes []*model.Note
+		var err error
+		if workspaceID != "" {
+			notes, err = ds.ListNotesByWorkspace(workspaceID)
+		} else {
+			notes, err = ds.ListNotes()
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range notes {
+			inGraph[n.ID] = true
+			nodes = append(nodes, Node{
+				ID:          n.ID,
+				Label:       n.Title,
+				Type:        TypeNote,
+				Slug:        n.Slug,
+				WorkspaceID: n.WorkspaceID,
+				Workspace:   wsName[n.WorkspaceID],
+				Tags:        model.NoteTags(n),
+				CreatedAt:   n.CreatedAt,
+				UpdatedAt:   n.UpdatedAt,
+			})
+		}
+	}
 
-	noteSet := make(map[string]*model.Note, len(notes))
-	for _, n := range notes {
-		noteSet[n.ID] = n
+	boardByName := make(map[string]string)
+	cardByTitle := make(map[string]*model.Card)
+	var cardIDs []string
+	if include[TypeCard] || include[TypeBoard] {
+		var boards []*model.Board
+		var err error
+		if workspaceID != "" {
+			boards, err = ds.ListBoardsByWorkspace(workspaceID)
+		} else {
+			boards, err = ds.ListBoards()
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range boards {
+			boardByName[strings.ToLower(b.Name)] = b.ID
+			if include[TypeBoard] {
+				inGraph[b.ID] = true
+				nodes = append(nodes, Node{
+					ID:          b.ID,
+					Label:       b.Name,
+					Type:        TypeBoard,
+					WorkspaceID: b.WorkspaceID,
+					Workspace:   wsName[b.WorkspaceID],
+					CreatedAt:   b.CreatedAt,
+					UpdatedAt:   b.UpdatedAt,
+				})
+			}
+			if !include[TypeCard] {
+				continue
+			}
+			cards, err := ds.ListBoardCards(b.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range cards {
+				inGraph[c.ID] = true
+				cardIDs = append(cardIDs, c.ID)
+				nodes = append(nodes, Node{
+					ID:          c.ID,
+					Label:       c.Title,
+					Type:        TypeCard,
+					Board:       b.Name,
+					WorkspaceID: b.WorkspaceID,
+					Workspace:   wsName[b.WorkspaceID],
+					Tags:        c.LabelList(),
+					CreatedAt:   c.CreatedAt,
+					UpdatedAt:   c.UpdatedAt,
+				})
+				if include[TypeBoard] {
+					edges = append(edges, Edge{Source: c.ID, Target: b.ID, Type: EdgeMember})
+				}
+				// Like ResolveCardRef, a title resolves to the most recently
+				// updated card.
+				key := strings.ToLower(c.Title)
+				if prev, ok := cardByTitle[key]; !ok || c.UpdatedAt.After(prev.UpdatedAt) {
+					cardByTitle[key] = c
+				}
+			}
+		}
+	}
+
+	resolve := func(targetType, ref string) string {
+		switch targetType {
+		case TypeCard:
+			if inGraph[ref] {
+				return ref
+			}
+			if c, ok := cardByTitle[strings.ToLower(ref)]; ok {
+				return c.ID
+			}
+			return uniquePrefix(cardIDs, ref)
+		case TypeBoard:
+			return boardByName[strings.ToLower(ref)]
+		case TypeNote:
+			return ref
+		}
+		return ""
 	}
 
 	allLinks, err := ds.ListAllLinks()
@@ -53,41 +225,46 @@ func BuildGraph(ds DataSource, workspaceID string) (*GraphData, error) {
 		return nil, err
 	}
 
-	connectionCount := make(map[string]int)
-	var edges []Edge
-
+	seen := make(map[[3]string]bool)
+	for _, e := range edges {
+		seen[[3]string{e.Source, e.Target, e.Relation}] = true
+	}
 	for _, link := range allLinks {
-		if link.SourceType != "note" || link.TargetType != "note" {
+		source := link.SourceID
+		target := resolve(link.TargetType, link.TargetID)
+		if !inGraph[source] || !inGraph[target] {
 			continue
 		}
-		_, sourceInSet := noteSet[link.SourceID]
-		_, targetInSet := noteSet[link.TargetID]
 
-		if !sourceInSet || !targetInSet {
+		// Links to different sections of the same note collapse into one
+		// edge per relation.
+		key := [3]string{source, target, link.Relation}
+		if seen[key] {
 			continue
 		}
+		seen[key] = true
 
 		edges = append(edges, Edge{
-			Source:  link.SourceID,
-			Target:  link.TargetID,
-			Context: link.Context,
-		})
-		connectionCount[link.SourceID]++
-		connectionCount[link.TargetID]++
-	}
-
-	nodes := make([]Node, 0, len(notes))
-	for _, n := range notes {
-		nodes = append(nodes, Node{
-			ID:          n.ID,
-			Label:       n.Title,
-			Type:        "note",
-			Slug:        n.Slug,
-			WorkspaceID: n.WorkspaceID,
-			Connections: connectionCount[n.ID],
+			Source:   source,
+			Target:   target,
+			Context:  link.Context,
+			Type:     EdgeLink,
+			Relation: link.Relation,
 		})
 	}
 
+	connectionCount := make(map[string]int)
+	for _, e := range edges {
+		connectionCount[e.Source]++
+		connectionCount[e.Target]++
+	}
+	for i := range nodes {
+		nodes[i].Connections = connectionCount[nodes[i].ID]
+	}
+
+	if nodes == nil {
+		nodes = []Node{}
+	}
 	if edges == nil {
 		edges = []Edge{}
 	}
@@ -95,6 +272,24 @@ func BuildGraph(ds DataSource, workspaceID string) (*GraphData, error) {
 	return &GraphData{Nodes: nodes, Edges: edges}, nil
 }
 
+// uniquePrefix returns the one ID starting with prefix, like the short
+// card IDs the CLI prints, or "" when none or several do.
+func uniquePrefix(ids []string, prefix string) string {
+	if len(prefix) < model.MinCardIDPrefix {
+		return ""
+	}
+	match := ""
+	for _, id := range ids {
+		if strings.HasPrefix(id, prefix) {
+			if match != "" {
+				return ""
+			}
+			match = id
+		}
+	}
+	return match
+}
+
 func (g *GraphData) Stats() (nodes, edges, orphans int) {
 	nodes = len(g.Nodes)
 	edges = len(g.Edges)
@@ -8,8 +8,11 @@ import (
 )
 
 type mockDataSource struct {
-	notes []*model.Note
-	links []*model.Link
+	notes      []*model.Note
+	links      []*model.Link
+	boards     []*model.Board
+	cards      map[string][]*model.Card
+	workspaces []*model.Workspace
 }
 
 func (m *mockDataSource) ListNotes() ([]*model.Note, error) {
@@ -30,6 +33,28 @@ func (m *mockDataSource) ListAllLinks() ([]*model.Link, error) {
 	return m.links, nil
 }
 
+func (m *mockDataSource) ListBoards() ([]*model.Board, error) {
+	return m.boards, nil
+}
+
+func (m *mockDataSource) ListBoardsByWorkspace(workspaceID string) ([]*model.Board, error) {
+	var filtered []*model.Board
+	for _, b := range m.boards {
+		if b.WorkspaceID == workspaceID {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered, nil
+}
+
+func (m *mockDataSource) ListBoardCards(boardID string) ([]*model.Card, error) {
+	return m.cards[boardID], nil
+}
+
+func (m *mockDataSource) ListWorkspaces() ([]*model.Workspace, error) {
+	return m.workspaces, nil
+}
+
 func TestBuildGraphBasic(t *testing.T) {
 	ds := &mockDataSource{
 		notes: []*model.Note{
@@ -125,6 +150,93 @@ func TestBuildGraphIgnoresNonNoteLinks(t *testing.T) {
 	}
 }
 
+func TestBuildGraphCardsAndBoards(t *testing.T) {
+	ds := &mockDataSource{
+		notes: []*model.Note{
+			{ID: "n1", Title: "Plan", Slug: "plan"},
+		},
+		boards: []*model.Board{{ID: "b1", Name: "Sprint"}},
+		cards: map[string][]*model.Card{
+			"b1": {{ID: "c1", Title: "Fix login"}, {ID: "c2", Title: "Ship"}},
+		},
+		links: []*model.Link{
+			{SourceType: "note", SourceID: "n1", TargetType: "card", TargetID: "fix login"},
+			{SourceType: "note", SourceID: "n1", TargetType: "board", TargetID: "sprint"},
+			{SourceType: "card", SourceID: "c2", TargetType: "note", TargetID: "n1"},
+			{SourceType: "card", SourceID: "c2", TargetType: "card", TargetID: "c1"},
+			{SourceType: "note", SourceID: "n1", TargetType: "card", TargetID: "missing"},
+		},
+	}
+
+	g, err := BuildGraph(ds, "")
+	if err != nil {
+		t.Fatalf("BuildGraph: %v", err)
+	}
+	if len(g.Nodes) != 4 {
+		t.Fatalf("nodes = %d, want 4", len(g.Nodes))
+	}
+	members, links := 0, 0
+	for _, e := range g.Edges {
+		switch e.Type {
+		case EdgeMember:
+			members++
+			if e.Target != "b1" {
+				t.Errorf("membership edge should point at the board: %+v", e)
+			}
+		case EdgeLink:
+			links++
+		}
+	}
+	if members != 2 || links != 4 {
+		t.Errorf("members = %d, links = %d, want 2 and 4", members, links)
+	}
+	for _, n := range g.Nodes {
+		if n.ID == "c1" && (n.Type != TypeCard || n.Board != "Sprint" || n.Connections != 3) {
+			t.Errorf("unexpected card node: %+v", n)
+		}
+	}
+
+	g, _ = BuildGraph(ds, "", TypeNote, TypeCard)
+	if len(g.Nodes) != 3 || len(g.Edges) != 3 {
+		t.Errorf("without boards: nodes = %d, edges = %d, want 3 and 3", len(g.Nodes), len(g.Edges))
+	}
+}
+
+func TestBuildGraphCardIDPrefix(t *testing.T) {
+	ds := &mockDataSource{
+		notes:  []*model.Note{{ID: "n1", Title: "Plan", Slug: "plan"}},
+		boards: []*model.Board{{ID: "b1", Name: "Sprint"}},
+		cards: map[string][]*model.Card{
+			"b1": {{ID: "abcd1234", Title: "Fix login"}, {ID: "abce5678", Title: "Ship"}},
+		},
+		links: []*model.Link{
+			{SourceType: "note", SourceID: "n1", TargetType: "card", TargetID: "abcd", Relation: "implements"},
+			{SourceType: "note", SourceID: "n1", TargetType: "card", TargetID: "abc"},
+			{SourceType: "note", SourceID: "n1", TargetType: "card", TargetID: "ab"},
+		},
+	}
+
+	g, err := BuildGraph(ds, "", TypeNote, TypeCard)
+	if err != nil {
+		t.Fatalf("BuildGraph: %v", err)
+	}
+	if len(g.Edges) != 1 || g.Edges[0].Target != "abcd1234" || g.Edges[0].Relation != "implements" {
+		t.Errorf("expected only the unique 4-character prefix to resolve, got %+v", g.Edges)
+	}
+}
+
+func TestParseTypes(t *testing.T) {
+	types, err := ParseTypes("notes, Card")
+	if err != nil || len(types) != 2 || types[0] != TypeNote || types[1] != TypeCard {
+		t.Errorf("ParseTypes = %v, %v", types, err)
+	}
+	for _, bad := range []string{"", "note,tag"} {
+		if _, err := ParseTypes(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
 func TestBuildGraphWorkspaceScoped(t *testing.T) {
 	ds := &mockDataSource{
 		notes: []*model.Note{
@@ -6,8 +6,16 @@ import (
 	"strings"
 )
 
+// GenerateHTML renders the graph as a D3 force simulation. The page loads
+// D3.js from its CDN, so it only works online; kb graph uses it for --cdn.
 func GenerateHTML(data *GraphData, title string) (string, error) {
-	nodesJSON, err := json.Marshal(data.Nodes)
+	nodes := make([]Node, len(data.Nodes))
+	communities := data.Communities()
+	for i, n := range data.Nodes {
+		n.Community = communities[n.ID]
+		nodes[i] = n
+	}
+	nodesJSON, err := json.Marshal(nodes)
 	if err != nil {
 		return "", fmt.Errorf("marshaling nodes: %w", err)
 	}
@@ -29,19 +37,31 @@ func GenerateHTML(data *GraphData, title string) (string, error) {
   * { margin: 0; padding: 0; box-sizing: border-box; }
   body { background: #1a1b26; color: #c0caf5; font-family: -apple-system, system-ui, sans-serif; overflow: hidden; }
   svg { width: 100vw; height: 100vh; display: block; }
-  .node circle { stroke: #414868; stroke-width: 1.5px; cursor: pointer; }
+  .node circle, .node rect { stroke: #414868; stroke-width: 1.5px; cursor: pointer; }
   .node text { font-size: 11px; fill: #c0caf5; pointer-events: none; }
   .link { stroke: #414868; stroke-opacity: 0.6; }
-  .node circle:hover { stroke: #7aa2f7; stroke-width: 2px; }
+  .link.member { stroke-dasharray: 4 3; stroke-opacity: 0.4; }
+  .link.relation { stroke-opacity: 0.9; stroke-width: 1.5px; }
+  .link-label { font-size: 9px; pointer-events: none; }
+  .node circle:hover, .node rect:hover { stroke: #7aa2f7; stroke-width: 2px; }
   #info { position: fixed; top: 16px; right: 16px; background: #24283b; padding: 12px 16px; border-radius: 8px; font-size: 13px; line-height: 1.6; border: 1px solid #414868; }
   #info span { color: #7aa2f7; font-weight: 600; }
+  #info .legend { color: #565f89; }
+  #info .card { color: #e0af68; }
+  #info .board { color: #bb9af7; }
+  #info select { background: #1a1b26; color: #c0caf5; border: 1px solid #414868; border-radius: 4px; font-size: 12px; }
+` + filterStyle + `
 </style>
 </head>
 <body>
 <div id="info">
-  <span id="stat-nodes">0</span> notes &middot;
+  <span id="stat-nodes">0</span> nodes &middot;
   <span id="stat-edges">0</span> links &middot;
   <span id="stat-orphans">0</span> orphans
+  <div class="legend">&#9679; note &nbsp; <b class="card">&#9632;</b> card &nbsp; <b class="board">&#9670;</b> board</div>
+  <div class="legend" id="relations"></div>
+  <div class="legend">color by <select id="color-by"><option value="type">type</option><option value="community">community</option></select></div>
+` + filterControls + `
 </div>
 <svg id="graph"></svg>
 <script src="https://d3js.org/d3.v7.min.js"></script>
@@ -71,8 +91,23 @@ const g = svg.append("g");
 
 svg.call(d3.zoom().scaleExtent([0.1, 4]).on("zoom", e => g.attr("transform", e.transform)));
 
+function relationColor(r) { return hue(Array.from(r).reduce((h, c) => (h * 31 + c.codePointAt(0)) % 360, 0)); }
+
 const link = g.append("g").selectAll("line")
-  .data(links).enter().append("line").attr("class", "link");
+  .data(links).enter().append("line")
+  .attr("class", d => "link " + (d.type || "") + (d.relation ? " relation" : ""))
+  .style("stroke", d => d.relation ? relationColor(d.relation) : null);
+link.filter(d => d.relation).append("title").text(d => d.relation);
+
+const linkLabel = g.append("g").selectAll("text")
+  .data(links.filter(d => d.relation)).enter().append("text")
+  .attr("class", "link-label").attr("text-anchor", "middle")
+  .attr("fill", d => relationColor(d.relation)).text(d => d.relation);
+
+const relationNames = [...new Set(links.filter(d => d.relation).map(d => d.relation))].sort();
+document.getElementById("relations").innerHTML = relationNames
+  .map(r => '<b style="color:' + relationColor(r) + '">&#8212;</b> ' + r)
+  .join(" &nbsp; ");
 
 const node = g.append("g").selectAll("g")
   .data(nodes).enter().append("g").attr("class", "node")
@@ -82,20 +117,51 @@ const node = g.append("g").selectAll("g")
     .on("end", (e, d) => { if (!e.active) simulation.alphaTarget(0); d.fx = null; d.fy = null; }));
 
 function nodeRadius(d) { return Math.max(6, Math.min(20, 4 + d.connections * 3)); }
+function hue(h) { return "hsl(" + h + ", 60%, 65%)"; }
 function nodeColor(d) {
+  if (colorBy === "community") return hue((d.community * 137) % 360);
+  if (d.type === "card") return "#e0af68";
+  if (d.type === "board") return "#bb9af7";
   if (!d.workspace_id) return "#7aa2f7";
   const h = Array.from(d.workspace_id).reduce((a, c) => a + c.charCodeAt(0), 0) % 360;
-  return "hsl(" + h + ", 60%, 65%)";
+  return hue(h);
 }
+let colorBy = "type";
+document.getElementById("color-by").addEventListener("change", e => {
+  colorBy = e.target.value;
+  node.selectAll("circle, rect").attr("fill", nodeColor);
+});
 
-node.append("circle").attr("r", nodeRadius).attr("fill", nodeColor);
+node.filter(d => d.type !== "card" && d.type !== "board")
+  .append("circle").attr("r", nodeRadius).attr("fill", nodeColor);
+node.filter(d => d.type === "card").append("rect")
+  .attr("x", d => -nodeRadius(d)).attr("y", d => -nodeRadius(d))
+  .attr("width", d => 2 * nodeRadius(d)).attr("height", d => 2 * nodeRadius(d))
+  .attr("rx", 3).attr("fill", nodeColor);
+node.filter(d => d.type === "board").append("rect")
+  .attr("x", d => -nodeRadius(d)).attr("y", d => -nodeRadius(d))
+  .attr("width", d => 2 * nodeRadius(d)).attr("height", d => 2 * nodeRadius(d))
+  .attr("transform", "rotate(45)").attr("fill", nodeColor);
 node.append("text").text(d => d.label).attr("dx", d => nodeRadius(d) + 4).attr("dy", 4);
 
-node.append("title").text(d => d.label + " (" + d.connections + " connections)");
+node.append("title").text(d => d.label + (d.board ? " — " + d.board : "") + " (" + d.type + ", " + d.connections + " connections, community " + d.community + ")");
+
+` + filterScript + `
+function applyFilters() {
+  const shown = shownNodes(nodes);
+  const visible = d => shown.has(d.source.id) && shown.has(d.target.id);
+  node.style("display", d => shown.has(d.id) ? null : "none");
+  link.style("display", d => visible(d) ? null : "none");
+  linkLabel.style("display", d => visible(d) ? null : "none");
+  document.getElementById("stat-nodes").textContent = shown.size;
+  document.getElementById("stat-edges").textContent = links.filter(visible).length;
+}
+onFilterChange(applyFilters);
 
 simulation.on("tick", () => {
   link.attr("x1", d => d.source.x).attr("y1", d => d.source.y)
       .attr("x2", d => d.target.x).attr("y2", d => d.target.y);
+  linkLabel.attr("x", d => (d.source.x + d.target.x) / 2).attr("y", d => (d.source.y + d.target.y) / 2 - 3);
   node.attr("transform", d => "translate(" + d.x + "," + d.y + ")");
 });
 </script>
@@ -104,3 +170,32 @@ simulation.on("tick", () => {
 
 	return b.String(), nil
 }
+
+// The filter controls shared by the D3 pages. They hide nodes by tag,
+// connection count and last update without rebuilding the graph.
+const filterStyle = `  #filters input { background: #1a1b26; color: #c0caf5; border: 1px solid #414868; border-radius: 4px; font-size: 12px; padding: 1px 4px; width: 96px; }
+  #filters input[type=number] { width: 48px; }
+  #filters { display: grid; grid-template-columns: auto auto; gap: 2px 6px; margin-top: 6px; color: #565f89; }`
+
+const filterControls = `  <div id="filters">
+    <label for="f-tag">tag</label><input id="f-tag" placeholder="any">
+    <label for="f-exclude">exclude tag</label><input id="f-exclude" placeholder="none">
+    <label for="f-min">min links</label><input id="f-min" type="number" min="0" value="0">
+    <label for="f-since">updated since</label><input id="f-since" type="date">
+  </div>`
+
+const filterScript = `function tagMatches(tags, filter) { return (tags || []).some(t => t === filter || t.startsWith(filter + "/")); }
+function filterTag(id) { return document.getElementById(id).value.trim().toLowerCase().replace(/^#/, "").replace(/^\/+|\/+$/g, ""); }
+function shownNodes(nodes) {
+  const tag = filterTag("f-tag"), exclude = filterTag("f-exclude");
+  const min = Number(document.getElementById("f-min").value) || 0;
+  const sinceValue = document.getElementById("f-since").value;
+  const since = sinceValue ? new Date(sinceValue + "T00:00:00") : null;
+  return new Set(nodes.filter(d =>
+    (!tag || tagMatches(d.tags, tag)) && !(exclude && tagMatches(d.tags, exclude)) &&
+    d.connections >= min && (!since || (d.updated_at && new Date(d.updated_at) >= since))
+  ).map(d => d.id));
+}
+function onFilterChange(apply) {
+  document.querySelectorAll("#filters input").forEach(input => input.addEventListener("input", apply));
+}`
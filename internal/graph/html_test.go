@@ -3,6 +3,7 @@ package graph
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGenerateHTMLBasic(t *testing.T) {
@@ -27,7 +28,7 @@ func TestGenerateHTMLBasic(t *testing.T) {
 	}{
 		{"doctype", "<!DOCTYPE html>"},
 		{"title", "<title>Test Graph</title>"},
-		{"d3 script", "d3js.org/d3.v7.min.js"},
+		{"d3 cdn script", `<script src="https://d3js.org/d3.v7.min.js">`},
 		{"svg element", `<svg id="graph">`},
 		{"node id n1", `"id":"n1"`},
 		{"node id n2", `"id":"n2"`},
@@ -150,3 +151,57 @@ func TestGenerateHTMLForceSimulation(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateHTMLNodeTypes(t *testing.T) {
+	data := &GraphData{
+		Nodes: []Node{
+			{ID: "c1", Label: "Fix login", Type: TypeCard, Board: "Sprint"},
+			{ID: "b1", Label: "Sprint", Type: TypeBoard},
+		},
+		Edges: []Edge{{Source: "c1", Target: "b1", Type: EdgeMember}},
+	}
+
+	html, err := GenerateHTML(data, "Graph")
+	if err != nil {
+		t.Fatalf("GenerateHTML: %v", err)
+	}
+	for _, s := range []string{`"type":"card"`, `"board":"Sprint"`, `"type":"member"`, `d.type === "board"`, ".link.member"} {
+		if !strings.Contains(html, s) {
+			t.Errorf("missing %q", s)
+		}
+	}
+}
+
+func TestGenerateHTMLCommunities(t *testing.T) {
+	data := &GraphData{
+		Nodes: []Node{{ID: "n1", Label: "A", Type: TypeNote}, {ID: "n2", Label: "B", Type: TypeNote}},
+		Edges: []Edge{{Source: "n1", Target: "n2"}},
+	}
+
+	html, err := GenerateHTML(data, "Graph")
+	if err != nil {
+		t.Fatalf("GenerateHTML: %v", err)
+	}
+	for _, s := range []string{`"community":1`, `id="color-by"`, `colorBy === "community"`} {
+		if !strings.Contains(html, s) {
+			t.Errorf("missing %q", s)
+		}
+	}
+	if data.Nodes[0].Community != 0 {
+		t.Error("GenerateHTML should not modify the graph")
+	}
+}
+
+func TestGenerateHTMLFilterControls(t *testing.T) {
+	data := &GraphData{Nodes: []Node{{ID: "n1", Label: "A", Type: TypeNote, Tags: []string{"go"}, UpdatedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}}}
+
+	html, err := GenerateHTML(data, "Graph")
+	if err != nil {
+		t.Fatalf("GenerateHTML: %v", err)
+	}
+	for _, s := range []string{`id="f-tag"`, `id="f-exclude"`, `id="f-min"`, `id="f-since"`, `"updated_at":"2026-03-01T00:00:00Z"`, "onFilterChange(applyFilters)"} {
+		if !strings.Contains(html, s) {
+			t.Errorf("missing %q", s)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Point is a node position in a layout.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+const layoutIterations = 300
+
+// Layout places the nodes with the Fruchterman-Reingold force-directed
+// algorithm: linked nodes attract, every pair of nodes repels, and a weak
+// pull toward the center keeps separate components in view. Starting
+// positions come from seed, so the same graph and seed always give the
+// same layout. Positions fall within a square of the returned size.
+func Layout(data *GraphData, seed int64) (map[string]Point, float64) {
+	n := len(data.Nodes)
+	size := 200 + 100*math.Sqrt(float64(n))
+	pos := make([]Point, n)
+	index := make(map[string]int, n)
+	rng := rand.New(rand.NewSource(seed))
+	for i, node := range data.Nodes {
+		index[node.ID] = i
+		pos[i] = Point{X: rng.Float64() * size, Y: rng.Float64() * size}
+	}
+
+	var edges [][2]int
+	for _, e := range data.Edges {
+		s, ok1 := index[e.Source]
+		t, ok2 := index[e.Target]
+		if ok1 && ok2 && s != t {
+			edges = append(edges, [2]int{s, t})
+		}
+	}
+
+	k := size / math.Sqrt(float64(max(n, 1)))
+	center := size / 2
+	temp := size / 10
+	gravity := 0.5 * k / size
+	disp := make([]Point, n)
+	for iter := range layoutIterations {
+		for i := range disp {
+			disp[i] = Point{}
+		}
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				dx, dy, d := delta(pos[i], pos[j])
+				f := k * k / d
+				disp[i].X += dx / d * f
+				disp[i].Y += dy / d * f
+				disp[j].X -= dx / d * f
+				disp[j].Y -= dy / d * f
+			}
+		}
+		for _, e := range edges {
+			dx, dy, d := delta(pos[e[0]], pos[e[1]])
+			f := d * d / k
+			disp[e[0]].X -= dx / d * f
+			disp[e[0]].Y -= dy / d * f
+			disp[e[1]].X += dx / d * f
+			disp[e[1]].Y += dy / d * f
+		}
+		for i := range n {
+			disp[i].X -= (pos[i].X - center) * gravity
+			disp[i].Y -= (pos[i].Y - center) * gravity
+			d := math.Hypot(disp[i].X, disp[i].Y)
+			if d > 0 {
+				step := math.Min(d, temp)
+				pos[i].X += disp[i].X / d * step
+				pos[i].Y += disp[i].Y / d * step
+			}
+		}
+		temp = size / 10 * (1 - float64(iter+1)/layoutIterations)
+	}
+
+	return fitLayout(data, pos, size), size
+}
+
+// delta returns the offset from b to a and its length, nudging nodes that
+// sit on top of each other apart.
+func delta(a, b Point) (dx, dy, d float64) {
+	dx, dy = a.X-b.X, a.Y-b.Y
+	d = math.Hypot(dx, dy)
+	if d < 0.01 {
+		dx, dy, d = 0.01, 0, 0.01
+	}
+	return dx, dy, d
+}
+
+// fitLayout scales the positions to fill the square with a margin and
+// rounds them, so output does not depend on floating-point noise.
+func fitLayout(data *GraphData, pos []Point, size float64) map[string]Point {
+	const margin = 40
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pos {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	scale := 1.0
+	if span := math.Max(maxX-minX, maxY-minY); span > 0 {
+		scale = (size - 2*margin) / span
+	}
+
+	layout := make(map[string]Point, len(pos))
+	for i, node := range data.Nodes {
+		p := Point{X: size / 2, Y: size / 2}
+		if len(pos) > 1 {
+			p = Point{X: margin + (pos[i].X-minX)*scale, Y: margin + (pos[i].Y-minY)*scale}
+		}
+		layout[node.ID] = Point{X: math.Round(p.X*10) / 10, Y: math.Round(p.Y*10) / 10}
+	}
+	return layout
+}
@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+var (
+	orderedItemRe = regexp.MustCompile(`^\s*\d+[.)]\s+`)
+	bulletItemRe  = regexp.MustCompile(`^\s*[-*+]\s+`)
+	taskRe        = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	ruleRe        = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	tableSepRe    = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+
+	// inlineRe finds the spans rendered as a whole: code, wikilinks,
+	// Markdown links and bare URLs.
+	inlineRe = regexp.MustCompile("`[^`]+`|!?\\[\\[[^\\]]+\\]\\]|\\[[^\\]]+\\]\\([^)\\s]+\\)|https?://[^\\s<>()]+")
+	strongRe = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	emRe     = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+)
+
+// RenderPreview renders a note body as HTML for the graph server's side
+// panel. It covers the Markdown notes commonly use: headings, paragraphs,
+// lists and task lists, quotes, code blocks, tables, emphasis and links.
+// Wikilinks become links with data-type and data-ref attributes so the page
+// can select the node they point to. All text is escaped.
+func RenderPreview(body string) string {
+	lines := strings.Split(strings.ReplaceAll(model.StripBlockIDs(model.StripFrontMatter(body)), "\r\n", "\n"), "\n")
+
+	var b strings.Builder
+	var para []string
+	list := ""
+	flushPara := func() {
+		if len(para) > 0 {
+			fmt.Fprintf(&b, "<p>%s</p>\n", renderInline(strings.Join(para, " ")))
+			para = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			fmt.Fprintf(&b, "</%s>\n", list)
+			list = ""
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushPara()
+			closeList()
+
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flushPara()
+			closeList()
+			fence := trimmed[:3]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			fmt.Fprintf(&b, "<pre><code>%s</code></pre>\n", html.EscapeString(strings.Join(code, "\n")))
+
+		case ruleRe.MatchString(line):
+			flushPara()
+			closeList()
+			b.WriteString("<hr>\n")
+
+		case strings.HasPrefix(trimmed, "#"):
+			level, text, ok := model.ParseHeading(trimmed)
+			if !ok {
+				para = append(para, trimmed)
+				continue
+			}
+			flushPara()
+			closeList()
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, renderInline(text), level)
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushPara()
+			closeList()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")))
+			}
+			i--
+			fmt.Fprintf(&b, "<blockquote>%s</blockquote>\n", renderInline(strings.Join(quote, " ")))
+
+		case strings.HasPrefix(trimmed, "|"):
+			flushPara()
+			closeList()
+			var rows [][]string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				if !tableSepRe.MatchString(lines[i]) {
+					rows = append(rows, tableCells(lines[i]))
+				}
+			}
+			i--
+			writeTable(&b, rows)
+
+		case bulletItemRe.MatchString(line) || orderedItemRe.MatchString(line):
+			flushPara()
+			kind, text := "ul", bulletItemRe.ReplaceAllString(line, "")
+			if orderedItemRe.MatchString(line) {
+				kind, text = "ol", orderedItemRe.ReplaceAllString(line, "")
+			}
+			if list != kind {
+				closeList()
+				fmt.Fprintf(&b, "<%s>\n", kind)
+				list = kind
+			}
+			if m := taskRe.FindStringSubmatch(text); m != nil {
+				checked := ""
+				if m[1] != " " {
+					checked = " checked"
+				}
+				fmt.Fprintf(&b, "<li class=\"task\"><input type=\"checkbox\" disabled%s> %s</li>\n", checked, renderInline(text[len(m[0]):]))
+			} else {
+				fmt.Fprintf(&b, "<li>%s</li>\n", renderInline(text))
+			}
+
+		default:
+			closeList()
+			para = append(para, trimmed)
+		}
+	}
+	flushPara()
+	closeList()
+	return b.String()
+}
+
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	// Wikilinks in tables escape their separator as "\|".
+	line = strings.ReplaceAll(line, `\|`, "\x00")
+	cells := strings.Split(line, "|")
+	for i, c := range cells {
+		cells[i] = strings.TrimSpace(strings.ReplaceAll(c, "\x00", `\|`))
+	}
+	return cells
+}
+
+func writeTable(b *strings.Builder, rows [][]string) {
+	b.WriteString("<table>\n")
+	for i, row := range rows {
+		tag := "td"
+		if i == 0 {
+			tag = "th"
+		}
+		b.WriteString("<tr>")
+		for _, c := range row {
+			fmt.Fprintf(b, "<%s>%s</%s>", tag, renderInline(c), tag)
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n")
+}
+
+// renderInline escapes text and renders its inline Markdown.
+func renderInline(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range inlineRe.FindAllStringIndex(text, -1) {
+		b.WriteString(emphasis(html.EscapeString(text[last:m[0]])))
+		b.WriteString(renderSpan(text[m[0]:m[1]]))
+		last = m[1]
+	}
+	b.WriteString(emphasis(html.EscapeString(text[last:])))
+	return b.String()
+}
+
+func emphasis(escaped string) string {
+	escaped = strongRe.ReplaceAllString(escaped, "<strong>$1$2</strong>")
+	return emRe.ReplaceAllString(escaped, "<em>$1$2</em>")
+}
+
+func renderSpan(span string) string {
+	switch {
+	case strings.HasPrefix(span, "`"):
+		return "<code>" + html.EscapeString(strings.Trim(span, "`")) + "</code>"
+
+	case strings.HasPrefix(span, "[[") || strings.HasPrefix(span, "![["):
+		links := model.ParseWikilinks(span)
+		if len(links) == 0 {
+			return html.EscapeString(span)
+		}
+		link := links[0]
+		return fmt.Sprintf(`<a href="#" class="wikilink" data-type="%s" data-ref="%s">%s</a>`,
+			html.EscapeString(link.TargetType), html.EscapeString(link.TargetRef), html.EscapeString(link.Display))
+
+	case strings.HasPrefix(span, "["):
+		sep := strings.Index(span, "](")
+		label, url := span[1:sep], span[sep+2:len(span)-1]
+		if !safeURL(url) {
+			return html.EscapeString(label)
+		}
+		return fmt.Sprintf(`<a href="%s" target="_blank" rel="noopener">%s</a>`, html.EscapeString(url), emphasis(html.EscapeString(label)))
+
+	default:
+		return fmt.Sprintf(`<a href="%s" target="_blank" rel="noopener">%s</a>`, html.EscapeString(span), html.EscapeString(span))
+	}
+}
+
+// safeURL allows only links that cannot run script in the page.
+func safeURL(url string) bool {
+	lower := strings.ToLower(url)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")
+}
@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderPreview(t *testing.T) {
+	body := "---\ntags: [x]\n---\n# Title\n\nSome **bold** and *soft* text with `code` and [[Other Note|other]].\n\n" +
+		"- [x] done\n- [ ] todo\n\n1. first\n\n> quoted\n\n```\n<b>raw</b>\n```\n\n" +
+		"| A | B |\n|---|---|\n| [[card:Fix\\|fix]] | 2 |\n\n[site](https://example.com) [bad](javascript:alert(1)) <script>"
+	got := RenderPreview(body)
+
+	for _, want := range []string{
+		"<h1>Title</h1>",
+		"<strong>bold</strong>",
+		"<em>soft</em>",
+		"<code>code</code>",
+		`<a href="#" class="wikilink" data-type="note" data-ref="Other Note">other</a>`,
+		`<li class="task"><input type="checkbox" disabled checked> done</li>`,
+		`<li class="task"><input type="checkbox" disabled> todo</li>`,
+		"<ol>\n<li>first</li>\n</ol>",
+		"<blockquote>quoted</blockquote>",
+		"<pre><code>&lt;b&gt;raw&lt;/b&gt;</code></pre>",
+		"<th>A</th><th>B</th>",
+		`data-type="card" data-ref="Fix">fix</a></td><td>2</td>`,
+		`<a href="https://example.com" target="_blank" rel="noopener">site</a>`,
+		"&lt;script&gt;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview missing %q:\n%s", want, got)
+		}
+	}
+	for _, bad := range []string{"tags:", "javascript:", "<script>"} {
+		if strings.Contains(got, bad) {
+			t.Errorf("preview should not contain %q:\n%s", bad, got)
+		}
+	}
+}
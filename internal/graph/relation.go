@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// Relations lists the relation names used by the graph's links, sorted.
+func (g *GraphData) Relations() []string {
+	seen := make(map[string]bool)
+	var relations []string
+	for _, e := range g.Edges {
+		if e.Relation != "" && !seen[e.Relation] {
+			seen[e.Relation] = true
+			relations = append(relations, e.Relation)
+		}
+	}
+	sort.Strings(relations)
+	return relations
+}
+
+// FilterRelations keeps the links with one of the given relations and the
+// nodes they join, plus the nodes listed in keep.
+func (g *GraphData) FilterRelations(relations []string, keep ...string) *GraphData {
+	want := make(map[string]bool, len(relations))
+	for _, r := range relations {
+		want[model.NormalizeRelation(r)] = true
+	}
+	inGraph := make(map[string]bool)
+	for _, id := range keep {
+		inGraph[id] = true
+	}
+
+	sub := &GraphData{Nodes: []Node{}, Edges: []Edge{}}
+	count := make(map[string]int)
+	for _, e := range g.Edges {
+		if e.Type != EdgeLink || !want[e.Relation] {
+			continue
+		}
+		sub.Edges = append(sub.Edges, e)
+		inGraph[e.Source] = true
+		inGraph[e.Target] = true
+		count[e.Source]++
+		count[e.Target]++
+	}
+	for _, n := range g.Nodes {
+		if inGraph[n.ID] {
+			n.Connections = count[n.ID]
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	return sub
+}
+
+// relationColor gives each relation a stable color; it matches the D3
+// view in GenerateHTML.
+func relationColor(relation string) string {
+	h := 0
+	for _, c := range relation {
+		h = (h*31 + int(c)) % 360
+	}
+	return hue(h)
+}
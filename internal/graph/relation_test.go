@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func relationGraph() *GraphData {
+	return &GraphData{
+		Nodes: []Node{
+			{ID: "a4", Label: "ADR 4", Type: TypeNote, Connections: 3},
+			{ID: "a3", Label: "ADR 3", Type: TypeNote, Connections: 2},
+			{ID: "c1", Label: "Ship it", Type: TypeCard, Connections: 2},
+			{ID: "b1", Label: "Sprint", Type: TypeBoard, Connections: 1},
+		},
+		Edges: []Edge{
+			{Source: "a4", Target: "a3", Type: EdgeLink, Relation: "supersedes"},
+			{Source: "a4", Target: "a3", Type: EdgeLink},
+			{Source: "c1", Target: "a4", Type: EdgeLink, Relation: "implements"},
+			{Source: "c1", Target: "b1", Type: EdgeMember},
+		},
+	}
+}
+
+func TestRelations(t *testing.T) {
+	if got := relationGraph().Relations(); !reflect.DeepEqual(got, []string{"implements", "supersedes"}) {
+		t.Errorf("Relations = %v", got)
+	}
+}
+
+func TestFilterRelations(t *testing.T) {
+	sub := relationGraph().FilterRelations([]string{" Supersedes "})
+	if len(sub.Edges) != 1 || sub.Edges[0].Relation != "supersedes" {
+		t.Fatalf("expected only the supersedes link, got %+v", sub.Edges)
+	}
+	if len(sub.Nodes) != 2 || sub.Nodes[0].Connections != 1 {
+		t.Errorf("expected the two ADRs with one connection each, got %+v", sub.Nodes)
+	}
+
+	sub = relationGraph().FilterRelations([]string{"contradicts"}, "c1")
+	if len(sub.Edges) != 0 || len(sub.Nodes) != 1 || sub.Nodes[0].ID != "c1" {
+		t.Errorf("expected only the kept node, got %+v", sub)
+	}
+}
+
+func TestRelationsRendered(t *testing.T) {
+	data := relationGraph()
+	color := relationColor("supersedes")
+	if color != relationColor("supersedes") || color == relationColor("implements") {
+		t.Errorf("relation colors should be stable and distinct, got %s and %s", color, relationColor("implements"))
+	}
+
+	svg := GenerateSVG(data, "kb", 1)
+	if !strings.Contains(svg, `stroke="`+color+`"`) || !strings.Contains(svg, ">supersedes</text>") {
+		t.Errorf("expected a colored, labeled supersedes edge in:\n%s", svg)
+	}
+	page, _ := GenerateOfflineHTML(data, "kb", 1)
+	if !strings.Contains(page, `<b style="color:`+color+`">&#8212;</b> supersedes`) {
+		t.Error("expected the relation in the offline legend")
+	}
+	html, _ := GenerateHTML(data, "kb")
+	if !strings.Contains(html, `"relation":"supersedes"`) || !strings.Contains(html, "function relationColor") {
+		t.Error("expected relations in the D3 page")
+	}
+
+	dot, _ := Export(data, FormatDOT, "kb")
+	if !strings.Contains(dot, `"a4" -> "a3" [label="supersedes", color="`+color+`"`) {
+		t.Errorf("expected a labeled DOT edge in:\n%s", dot)
+	}
+	mermaid, _ := Export(data, FormatMermaid, "kb")
+	if !strings.Contains(mermaid, `n3 -->|"implements"| n1`) {
+		t.Errorf("expected a labeled Mermaid edge in:\n%s", mermaid)
+	}
+}
@@ -0,0 +1,458 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// LiveSource is what the graph server reads: the graph itself, note and
+// card details for the side panel, and a stamp that changes whenever the
+// data does.
+type LiveSource interface {
+	DataSource
+	GetNote(id string) (*model.Note, error)
+	GetCard(id string) (*model.Card, error)
+	GetBacklinks(targetType, targetID string) ([]*model.Link, error)
+	ChangeStamp() (string, error)
+}
+
+// Server serves a live graph page, the graph as JSON, note previews and a
+// stream of server-sent events announcing changes.
+type Server struct {
+	src   LiveSource
+	title string
+	build func() (*GraphData, error)
+
+	// Expand, when set, rewrites a note body before it is rendered, for
+	// example to evaluate kb-query blocks.
+	Expand func(body string) string
+
+	// AnyHost turns off the loopback Host check, for servers deliberately
+	// bound to other interfaces.
+	AnyHost bool
+
+	mu      sync.Mutex
+	clients map[chan string]bool
+}
+
+// NewServer returns a server for the graph that build returns, reading
+// previews from src.
+func NewServer(src LiveSource, title string, build func() (*GraphData, error)) *Server {
+	return &Server{src: src, title: title, build: build, clients: make(map[chan string]bool)}
+}
+
+type previewBacklink struct {
+	SourceID   string `json:"source_id"`
+	SourceType string `json:"source_type"`
+	Label      string `json:"label"`
+	Relation   string `json:"relation,omitempty"`
+	Fragment   string `json:"fragment,omitempty"`
+	Context    string `json:"context"`
+}
+
+// liveGraph is the graph with the positions Layout gave its nodes.
+type liveGraph struct {
+	*GraphData
+	Positions map[string]Point `json:"positions"`
+	Size      float64          `json:"size"`
+}
+
+type preview struct {
+	ID        string            `json:"id"`
+	Title     string            `json:"title"`
+	Slug      string            `json:"slug"`
+	Tags      []string          `json:"tags"`
+	HTML      string            `json:"html"`
+	Backlinks []previewBacklink `json:"backlinks"`
+}
+
+// Handler routes the server's endpoints. Unless AnyHost is set, requests
+// must name a loopback host, so other sites cannot reach the server
+// through DNS rebinding.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.servePage)
+	mux.HandleFunc("GET /api/graph", s.serveGraph)
+	mux.HandleFunc("GET /api/notes/{id}", s.serveNote)
+	mux.HandleFunc("GET /events", s.serveEvents)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.AnyHost && !LoopbackHost(r.Host) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// LoopbackHost reports whether a host or host:port names this machine.
+func LoopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Watch polls the source every interval and tells the connected pages
+// when the data changes, until ctx is done.
+func (s *Server) Watch(ctx context.Context, interval time.Duration) {
+	last, _ := s.src.ChangeStamp()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stamp, err := s.src.ChangeStamp()
+			if err != nil || stamp == last {
+				continue
+			}
+			last = stamp
+			s.broadcast(stamp)
+		}
+	}
+}
+
+func (s *Server) broadcast(stamp string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.clients {
+		// A page that has not read the last change yet will reload anyway.
+		select {
+		case ch <- stamp:
+		default:
+		}
+	}
+}
+
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan string, 1)
+	s.mu.Lock()
+	s.clients[ch] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, "retry: 2000\n\n")
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case stamp := <-ch:
+			fmt.Fprintf(w, "event: change\ndata: %s\n\n", stamp)
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) serveGraph(w http.ResponseWriter, r *http.Request) {
+	data, err := s.build()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	communities := data.Communities()
+	nodes := make([]Node, len(data.Nodes))
+	for i, n := range data.Nodes {
+		n.Community = communities[n.ID]
+		nodes[i] = n
+	}
+	data = &GraphData{Nodes: nodes, Edges: data.Edges}
+	positions, size := Layout(data, 1)
+	writeJSON(w, liveGraph{GraphData: data, Positions: positions, Size: size})
+}
+
+func (s *Server) serveNote(w http.ResponseWriter, r *http.Request) {
+	note, err := s.src.GetNote(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "note not found", http.StatusNotFound)
+		return
+	}
+	body := note.Body
+	if s.Expand != nil {
+		body = s.Expand(body)
+	}
+
+	links, err := s.src.GetBacklinks("note", note.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	backlinks := []previewBacklink{}
+	for _, l := range links {
+		label := l.SourceID
+		switch l.SourceType {
+		case "note":
+			if n, err := s.src.GetNote(l.SourceID); err == nil {
+				label = n.Title
+			}
+		case "card":
+			if c, err := s.src.GetCard(l.SourceID); err == nil {
+				label = c.Title
+			}
+		}
+		backlinks = append(backlinks, previewBacklink{
+			SourceID:   l.SourceID,
+			SourceType: l.SourceType,
+			Label:      label,
+			Relation:   l.Relation,
+			Fragment:   l.Fragment,
+			Context:    l.Context,
+		})
+	}
+
+	tags := note.TagList()
+	if tags == nil {
+		tags = []string{}
+	}
+	writeJSON(w, preview{
+		ID:        note.ID,
+		Title:     note.Title,
+		Slug:      note.Slug,
+		Tags:      tags,
+		HTML:      RenderPreview(body),
+		Backlinks: backlinks,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) servePage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, strings.Replace(livePage, "{{title}}", html.EscapeString(s.title), 1))
+}
+
+// livePage draws the graph from the server with the layout computed in
+// Go, like GenerateOfflineHTML, so it needs no network access. It redraws
+// on change events and previews clicked notes in a side panel.
+const livePage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{title}}</title>
+<style>
+  * { margin: 0; padding: 0; box-sizing: border-box; }
+  body { background: #1a1b26; color: #c0caf5; font-family: -apple-system, system-ui, sans-serif; overflow: hidden; }
+  #graph { width: 100vw; height: 100vh; display: block; cursor: grab; }
+  #graph.panning { cursor: grabbing; }
+  .node circle, .node rect { stroke: #414868; stroke-width: 1.5px; cursor: pointer; }
+  .node.selected circle, .node.selected rect { stroke: #c0caf5; stroke-width: 3px; }
+  .node text { font-size: 11px; fill: #c0caf5; pointer-events: none; }
+  .link { stroke: #414868; stroke-opacity: 0.6; stroke-width: 1.5px; }
+  .link.member { stroke-dasharray: 4 3; stroke-opacity: 0.4; }
+  .link.relation { stroke-opacity: 0.9; }
+  .link-label { font-size: 9px; pointer-events: none; }
+  #info { position: fixed; top: 16px; left: 16px; background: #24283b; padding: 12px 16px; border-radius: 8px; font-size: 13px; line-height: 1.6; border: 1px solid #414868; }
+  #info span { color: #7aa2f7; font-weight: 600; }
+  #info .legend, #info .live { color: #565f89; }
+  #info .card { color: #e0af68; }
+  #info .board { color: #bb9af7; }
+  #panel { position: fixed; top: 0; right: 0; width: min(440px, 90vw); height: 100vh; overflow-y: auto; background: #24283b; border-left: 1px solid #414868; padding: 20px 24px; font-size: 14px; line-height: 1.6; display: none; }
+  #panel.open { display: block; }
+  #panel .close { float: right; cursor: pointer; color: #565f89; background: none; border: none; font-size: 18px; }
+  #panel h1 { font-size: 20px; margin-bottom: 4px; }
+  #panel h2, #panel h3, #panel h4 { margin: 14px 0 6px; }
+  #panel p, #panel ul, #panel ol, #panel pre, #panel blockquote, #panel table { margin: 8px 0; }
+  #panel ul, #panel ol { padding-left: 20px; }
+  #panel li.task { list-style: none; margin-left: -20px; }
+  #panel pre, #panel code { background: #1a1b26; border-radius: 4px; font-size: 12px; }
+  #panel pre { padding: 8px; overflow-x: auto; }
+  #panel code { padding: 1px 4px; }
+  #panel pre code { padding: 0; }
+  #panel blockquote { border-left: 3px solid #414868; padding-left: 10px; color: #a9b1d6; }
+  #panel table { border-collapse: collapse; }
+  #panel th, #panel td { border: 1px solid #414868; padding: 2px 6px; }
+  #panel a { color: #7aa2f7; }
+  #panel .meta { color: #565f89; font-size: 12px; }
+  #panel .backlinks { border-top: 1px solid #414868; margin-top: 16px; padding-top: 8px; }
+  #panel .backlinks li { list-style: none; margin: 6px 0; }
+  #panel .context { color: #565f89; font-size: 12px; }
+` + filterStyle + `
+</style>
+</head>
+<body>
+<div id="info">
+  <span id="stat-nodes">0</span> nodes &middot;
+  <span id="stat-edges">0</span> links &middot;
+  <span id="stat-orphans">0</span> orphans
+  <div class="legend">&#9679; note &nbsp; <b class="card">&#9632;</b> card &nbsp; <b class="board">&#9670;</b> board</div>
+  <div class="legend" id="relations"></div>
+` + filterControls + `
+  <div class="live" id="live">connecting&hellip;</div>
+</div>
+<svg xmlns="http://www.w3.org/2000/svg" id="graph" viewBox="0 0 1000 1000"><g class="links"></g><g class="link-labels"></g><g class="nodes"></g></svg>
+<div id="panel"><button class="close" id="close">&times;</button><div id="panel-body"></div></div>
+<script>
+const svg = document.getElementById("graph");
+` + panZoomScript + `
+
+const linkLayer = svg.querySelector(".links"), labelLayer = svg.querySelector(".link-labels"), nodeLayer = svg.querySelector(".nodes");
+let nodes = [], links = [], selected = null, sized = false;
+
+function nodeRadius(d) { return Math.max(6, Math.min(20, 4 + d.connections * 3)); }
+function hue(h) { return "hsl(" + h + ", 60%, 65%)"; }
+function relationColor(r) { return hue(Array.from(r).reduce((h, c) => (h * 31 + c.codePointAt(0)) % 360, 0)); }
+function nodeColor(d) {
+  if (d.type === "card") return "#e0af68";
+  if (d.type === "board") return "#bb9af7";
+  if (!d.workspace_id) return "#7aa2f7";
+  return hue(Array.from(d.workspace_id).reduce((a, c) => a + c.charCodeAt(0), 0) % 360);
+}
+function escapeHTML(s) { return String(s).replace(/[&<>"']/g, c => "&#" + c.charCodeAt(0) + ";"); }
+function el(name, attrs, parent) {
+  const e = document.createElementNS("http://www.w3.org/2000/svg", name);
+  for (const k in attrs) if (attrs[k] !== null) e.setAttribute(k, attrs[k]);
+  parent.appendChild(e);
+  return e;
+}
+
+function render(data) {
+  nodes = data.nodes;
+  links = data.edges;
+  const pos = data.positions;
+  if (!sized) {
+    view.x = 0; view.y = 0; view.width = data.size; view.height = data.size;
+    sized = true;
+  }
+
+  document.getElementById("stat-orphans").textContent = nodes.filter(n => n.connections === 0).length;
+  const relationNames = [...new Set(links.filter(d => d.relation).map(d => d.relation))].sort();
+  document.getElementById("relations").innerHTML = relationNames
+    .map(r => '<b style="color:' + relationColor(r) + '">&#8212;</b> ' + escapeHTML(r)).join(" &nbsp; ");
+
+  linkLayer.replaceChildren();
+  labelLayer.replaceChildren();
+  nodeLayer.replaceChildren();
+  for (const d of links) {
+    const s = pos[d.source], t = pos[d.target];
+    if (!s || !t) continue;
+    const ends = { "data-source": d.source, "data-target": d.target };
+    const line = el("line", Object.assign({ x1: s.x, y1: s.y, x2: t.x, y2: t.y, class: "link " + (d.type || "") + (d.relation ? " relation" : "") }, ends), linkLayer);
+    if (!d.relation) continue;
+    line.style.stroke = relationColor(d.relation);
+    el("text", Object.assign({ x: (s.x + t.x) / 2, y: (s.y + t.y) / 2 - 3, class: "link-label", "text-anchor": "middle", fill: relationColor(d.relation) }, ends), labelLayer)
+      .textContent = d.relation;
+  }
+  for (const d of nodes) {
+    const p = pos[d.id], r = nodeRadius(d);
+    const g = el("g", { class: "node", "data-id": d.id, transform: "translate(" + p.x + "," + p.y + ")" }, nodeLayer);
+    if (d.type === "note") {
+      el("circle", { r: r, fill: nodeColor(d) }, g);
+    } else {
+      el("rect", { x: -r, y: -r, width: 2 * r, height: 2 * r, rx: d.type === "card" ? 3 : null, transform: d.type === "board" ? "rotate(45)" : null, fill: nodeColor(d) }, g);
+    }
+    el("text", { x: r + 4, y: 4 }, g).textContent = d.label;
+    el("title", {}, g).textContent = d.label + (d.board ? " — " + d.board : "") + " (" + d.type + ", " + d.connections + " connections)";
+  }
+  highlight();
+  applyFilters();
+}
+
+` + filterScript + `
+function applyFilters() {
+  const shown = shownNodes(nodes);
+  const visible = e => shown.has(e.dataset.source) && shown.has(e.dataset.target);
+  nodeLayer.querySelectorAll(".node").forEach(n => { n.style.display = shown.has(n.dataset.id) ? "" : "none"; });
+  for (const e of [...linkLayer.children, ...labelLayer.children]) e.style.display = visible(e) ? "" : "none";
+  document.getElementById("stat-nodes").textContent = shown.size;
+  document.getElementById("stat-edges").textContent = links.filter(d => shown.has(d.source) && shown.has(d.target)).length;
+}
+onFilterChange(applyFilters);
+
+function highlight() {
+  nodeLayer.querySelectorAll(".node").forEach(n => n.classList.toggle("selected", n.dataset.id === selected));
+}
+
+async function select(id) {
+  selected = id;
+  highlight();
+  const d = nodes.find(n => n.id === id);
+  const panel = document.getElementById("panel");
+  const body = document.getElementById("panel-body");
+  if (!d) { panel.classList.remove("open"); return; }
+  panel.classList.add("open");
+  if (d.type !== "note") {
+    body.innerHTML = "<h1>" + escapeHTML(d.label) + "</h1><div class=\"meta\">" + escapeHTML(d.type) +
+      (d.board ? " on " + escapeHTML(d.board) : "") + (d.tags ? " &middot; " + d.tags.map(escapeHTML).join(", ") : "") + "</div>";
+    return;
+  }
+  const res = await fetch("/api/notes/" + encodeURIComponent(id));
+  if (!res.ok || selected !== id) return;
+  const note = await res.json();
+  let out = "<h1>" + escapeHTML(note.title) + "</h1><div class=\"meta\">" + escapeHTML(note.slug) +
+    (note.tags.length ? " &middot; " + note.tags.map(escapeHTML).join(", ") : "") + "</div>" + note.html;
+  out += "<div class=\"backlinks\"><b>Backlinks (" + note.backlinks.length + ")</b><ul>";
+  for (const bl of note.backlinks) {
+    out += "<li><a href=\"#\" data-id=\"" + escapeHTML(bl.source_id) + "\">" + escapeHTML(bl.label) + "</a>" +
+      (bl.relation ? " <span class=\"meta\">(" + escapeHTML(bl.relation) + ")</span>" : "") +
+      "<div class=\"context\">" + escapeHTML(bl.context) + "</div></li>";
+  }
+  body.innerHTML = out + "</ul></div>";
+}
+
+nodeLayer.addEventListener("click", e => {
+  const n = e.target.closest(".node");
+  if (n) select(n.dataset.id);
+});
+document.getElementById("panel-body").addEventListener("click", e => {
+  const a = e.target.closest("a[data-id], a.wikilink");
+  if (!a) return;
+  e.preventDefault();
+  if (a.dataset.id) { select(a.dataset.id); return; }
+  const ref = a.dataset.ref.toLowerCase();
+  const match = nodes.find(n => n.type === a.dataset.type &&
+    (n.id === a.dataset.ref || (n.slug || "").toLowerCase() === ref || n.label.toLowerCase() === ref));
+  if (match) select(match.id);
+});
+document.getElementById("close").addEventListener("click", () => select(null));
+
+async function reload() {
+  const res = await fetch("/api/graph");
+  if (!res.ok) return;
+  render(await res.json());
+  if (selected) select(selected);
+}
+
+const live = document.getElementById("live");
+const events = new EventSource("/events");
+events.onopen = () => { live.textContent = "live"; };
+events.onerror = () => { live.textContent = "reconnecting…"; };
+events.addEventListener("change", () => { live.textContent = "updated " + new Date().toLocaleTimeString(); reload(); });
+reload();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,207 @@
+package graph
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+type mockLiveSource struct {
+	mockDataSource
+	mu    sync.Mutex
+	stamp string
+}
+
+func (m *mockLiveSource) GetNote(id string) (*model.Note, error) {
+	for _, n := range m.notes {
+		if n.ID == id {
+			return n, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockLiveSource) GetCard(id string) (*model.Card, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockLiveSource) GetBacklinks(targetType, targetID string) ([]*model.Link, error) {
+	var links []*model.Link
+	for _, l := range m.links {
+		if l.TargetType == targetType && l.TargetID == targetID {
+			links = append(links, l)
+		}
+	}
+	return links, nil
+}
+
+func (m *mockLiveSource) ChangeStamp() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stamp, nil
+}
+
+func (m *mockLiveSource) setStamp(s string) {
+	m.mu.Lock()
+	m.stamp = s
+	m.mu.Unlock()
+}
+
+func testServer() (*Server, *mockLiveSource) {
+	src := &mockLiveSource{stamp: "1"}
+	src.notes = []*model.Note{
+		{ID: "n1", Title: "Alpha", Slug: "alpha", Body: "See [[Beta]] and {{x}}."},
+		{ID: "n2", Title: "Beta", Slug: "beta", Body: "# Beta"},
+	}
+	src.links = []*model.Link{
+		{ID: "l1", SourceType: "note", SourceID: "n1", TargetType: "note", TargetID: "n2", Relation: "extends", Context: "See [[Beta]]"},
+	}
+	srv := NewServer(src, "Test <Graph>", func() (*GraphData, error) { return BuildGraph(src, "") })
+	srv.Expand = func(body string) string { return strings.ReplaceAll(body, "{{x}}", "expanded") }
+	return srv, src
+}
+
+func get(t *testing.T, h http.Handler, host, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Host = host
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerPageAndGraph(t *testing.T) {
+	srv, _ := testServer()
+	h := srv.Handler()
+
+	rec := get(t, h, "127.0.0.1:7777", "/")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<title>Test &lt;Graph&gt;</title>") {
+		t.Fatalf("page: %d\n%s", rec.Code, rec.Body.String()[:200])
+	}
+	if strings.Contains(rec.Body.String(), "https://") {
+		t.Error("live page should make no external requests")
+	}
+
+	rec = get(t, h, "localhost:7777", "/api/graph")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("graph status = %d", rec.Code)
+	}
+	var data struct {
+		GraphData
+		Positions map[string]Point `json:"positions"`
+		Size      float64          `json:"size"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Nodes) != 2 || len(data.Edges) != 1 || data.Edges[0].Relation != "extends" {
+		t.Errorf("graph = %+v", data)
+	}
+	if _, ok := data.Positions["n1"]; !ok || data.Size == 0 {
+		t.Errorf("expected a layout, got %v in %v", data.Positions, data.Size)
+	}
+}
+
+func TestServerNotePreview(t *testing.T) {
+	srv, _ := testServer()
+	h := srv.Handler()
+
+	rec := get(t, h, "127.0.0.1", "/api/notes/n2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var p preview
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Title != "Beta" || p.HTML != "<h1>Beta</h1>\n" {
+		t.Errorf("preview = %+v", p)
+	}
+	if len(p.Backlinks) != 1 || p.Backlinks[0].Label != "Alpha" || p.Backlinks[0].Relation != "extends" {
+		t.Errorf("backlinks = %+v", p.Backlinks)
+	}
+
+	rec = get(t, h, "127.0.0.1", "/api/notes/n1")
+	if !strings.Contains(rec.Body.String(), "expanded") {
+		t.Errorf("body not expanded: %s", rec.Body.String())
+	}
+
+	if rec := get(t, h, "127.0.0.1", "/api/notes/missing"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing note status = %d, want 404", rec.Code)
+	}
+}
+
+func TestServerRejectsForeignHost(t *testing.T) {
+	srv, _ := testServer()
+	if rec := get(t, srv.Handler(), "evil.example:7777", "/api/graph"); rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+	srv.AnyHost = true
+	if rec := get(t, srv.Handler(), "evil.example:7777", "/api/graph"); rec.Code != http.StatusOK {
+		t.Errorf("AnyHost status = %d, want 200", rec.Code)
+	}
+}
+
+func TestLoopbackHost(t *testing.T) {
+	for host, want := range map[string]bool{
+		"127.0.0.1:7777": true,
+		"localhost":      true,
+		"[::1]:80":       true,
+		":7777":          false,
+		"0.0.0.0:7777":   false,
+		"example.com":    false,
+	} {
+		if got := LoopbackHost(host); got != want {
+			t.Errorf("LoopbackHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	srv, src := testServer()
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Watch(ctx, 10*time.Millisecond)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("content type = %q", ct)
+	}
+
+	src.setStamp("2")
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line := <-lines:
+			if line == "data: 2" {
+				return
+			}
+		case <-timeout:
+			t.Fatal("no change event")
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// GenerateSVG renders the graph as a self-contained SVG image, laid out
+// in Go with Layout. The same seed always gives the same image.
+func GenerateSVG(data *GraphData, title string, seed int64) string {
+	var b strings.Builder
+	writeSVG(&b, data, title, seed, "")
+	return b.String()
+}
+
+// GenerateOfflineHTML renders the graph as an HTML page that needs no
+// network access: the SVG from GenerateSVG plus a little inline script
+// for panning, zooming, filtering and coloring by community.
+func GenerateOfflineHTML(data *GraphData, title string, seed int64) (string, error) {
+	nodesJSON, err := json.Marshal(data.Nodes)
+	if err != nil {
+		return "", fmt.Errorf("marshaling nodes: %w", err)
+	}
+	nodes, edges, orphans := data.Stats()
+
+	var b strings.Builder
+	b.WriteString(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>`)
+	b.WriteString(html.EscapeString(title))
+	b.WriteString(`</title>
+<style>
+  * { margin: 0; padding: 0; box-sizing: border-box; }
+  body { background: #1a1b26; color: #c0caf5; font-family: -apple-system, system-ui, sans-serif; overflow: hidden; }
+  #graph { width: 100vw; height: 100vh; display: block; cursor: grab; }
+  #graph.panning { cursor: grabbing; }
+  #info { position: fixed; top: 16px; right: 16px; background: #24283b; padding: 12px 16px; border-radius: 8px; font-size: 13px; line-height: 1.6; border: 1px solid #414868; }
+  #info span { color: #7aa2f7; font-weight: 600; }
+  #info .legend { color: #565f89; }
+  #info .card { color: #e0af68; }
+  #info .board { color: #bb9af7; }
+  #info select { background: #1a1b26; color: #c0caf5; border: 1px solid #414868; border-radius: 4px; font-size: 12px; }
+` + filterStyle + `
+</style>
+</head>
+<body>
+<div id="info">
+`)
+	fmt.Fprintf(&b, `  <span id="stat-nodes">%d</span> nodes &middot;
+  <span id="stat-edges">%d</span> links &middot;
+  <span id="stat-orphans">%d</span> orphans
+`, nodes, edges, orphans)
+	b.WriteString(`  <div class="legend">&#9679; note &nbsp; <b class="card">&#9632;</b> card &nbsp; <b class="board">&#9670;</b> board</div>
+`)
+	if relations := data.Relations(); len(relations) > 0 {
+		var items []string
+		for _, r := range relations {
+			items = append(items, fmt.Sprintf(`<b style="color:%s">&#8212;</b> %s`, relationColor(r), html.EscapeString(r)))
+		}
+		fmt.Fprintf(&b, "  <div class=\"legend\">%s</div>\n", strings.Join(items, " &nbsp; "))
+	}
+	b.WriteString(`  <div class="legend">color by <select id="color-by"><option value="type">type</option><option value="community">community</option></select></div>
+` + filterControls + `
+</div>
+`)
+	writeSVG(&b, data, title, seed, "graph")
+	b.WriteString(`
+<script>
+const nodes = `)
+	b.Write(nodesJSON)
+	b.WriteString(`;
+const svg = document.getElementById("graph");
+` + panZoomScript + `
+
+document.getElementById("color-by").addEventListener("change", e => {
+  const attr = e.target.value === "community" ? "data-community-fill" : "data-type-fill";
+  svg.querySelectorAll(".node").forEach(n => n.firstElementChild.setAttribute("fill", n.getAttribute(attr)));
+});
+
+` + filterScript + `
+function applyFilters() {
+  const shown = shownNodes(nodes);
+  const visible = e => shown.has(e.dataset.source) && shown.has(e.dataset.target);
+  svg.querySelectorAll(".node").forEach(n => { n.style.display = shown.has(n.dataset.id) ? "" : "none"; });
+  svg.querySelectorAll(".link-labels text").forEach(e => { e.style.display = visible(e) ? "" : "none"; });
+  let edges = 0;
+  svg.querySelectorAll(".links line").forEach(e => {
+    e.style.display = visible(e) ? "" : "none";
+    if (visible(e)) edges++;
+  });
+  document.getElementById("stat-nodes").textContent = shown.size;
+  document.getElementById("stat-edges").textContent = edges;
+}
+onFilterChange(applyFilters);
+</script>
+</body>
+</html>`)
+	return b.String(), nil
+}
+
+// panZoomScript pans the SVG in svg by dragging and zooms it with the
+// wheel, by moving its viewBox. Presses on nodes are left to the page.
+const panZoomScript = `let view = svg.viewBox.baseVal;
+let drag = null;
+
+svg.addEventListener("wheel", e => {
+  e.preventDefault();
+  const scale = e.deltaY > 0 ? 1.1 : 1 / 1.1;
+  const r = svg.getBoundingClientRect();
+  const px = view.x + (e.clientX - r.left) / r.width * view.width;
+  const py = view.y + (e.clientY - r.top) / r.height * view.height;
+  view.x = px - (px - view.x) * scale;
+  view.y = py - (py - view.y) * scale;
+  view.width *= scale;
+  view.height *= scale;
+}, { passive: false });
+
+svg.addEventListener("pointerdown", e => {
+  if (e.target.closest(".node")) return;
+  drag = { x: e.clientX, y: e.clientY };
+  svg.classList.add("panning");
+  svg.setPointerCapture(e.pointerId);
+});
+svg.addEventListener("pointermove", e => {
+  if (!drag) return;
+  const r = svg.getBoundingClientRect();
+  view.x -= (e.clientX - drag.x) / r.width * view.width;
+  view.y -= (e.clientY - drag.y) / r.height * view.height;
+  drag = { x: e.clientX, y: e.clientY };
+});
+svg.addEventListener("pointerup", () => { drag = null; svg.classList.remove("panning"); });`
+
+func writeSVG(b *strings.Builder, data *GraphData, title string, seed int64, id string) {
+	layout, size := Layout(data, seed)
+	communities := data.Communities()
+
+	idAttr := ""
+	if id != "" {
+		idAttr = fmt.Sprintf(` id="%s"`, id)
+	}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg"%s viewBox="0 0 %.0f %.0f" width="%.0f" height="%.0f" font-family="-apple-system, system-ui, sans-serif">
+<title>%s</title>
+<rect x="-100%%" y="-100%%" width="300%%" height="300%%" fill="#1a1b26"/>
+<g class="links" stroke="#414868" stroke-opacity="0.6" stroke-width="1.5">
+`, idAttr, size, size, size, size, html.EscapeString(title))
+	for _, e := range data.Edges {
+		s, ok1 := layout[e.Source]
+		t, ok2 := layout[e.Target]
+		if !ok1 || !ok2 {
+			continue
+		}
+		style := ""
+		switch {
+		case e.Type == EdgeMember:
+			style = ` stroke-dasharray="4 3" stroke-opacity="0.4"`
+		case e.Relation != "":
+			style = fmt.Sprintf(` stroke="%s" stroke-opacity="0.9"`, relationColor(e.Relation))
+		}
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"%s%s/>`+"\n", s.X, s.Y, t.X, t.Y, style, edgeEnds(e))
+	}
+	b.WriteString("</g>\n<g class=\"link-labels\" font-size=\"9\" text-anchor=\"middle\">\n")
+	for _, e := range data.Edges {
+		s, ok1 := layout[e.Source]
+		t, ok2 := layout[e.Target]
+		if !ok1 || !ok2 || e.Relation == "" {
+			continue
+		}
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" fill="%s"%s>%s</text>`+"\n",
+			(s.X+t.X)/2, (s.Y+t.Y)/2-3, relationColor(e.Relation), edgeEnds(e), html.EscapeString(e.Relation))
+	}
+	b.WriteString("</g>\n<g class=\"nodes\" stroke=\"#414868\" stroke-width=\"1.5\" font-size=\"11\">\n")
+	for _, n := range data.Nodes {
+		p := layout[n.ID]
+		r := nodeRadius(n)
+		fill := nodeColor(n)
+		fmt.Fprintf(b, `<g class="node" transform="translate(%.1f,%.1f)" data-id="%s" data-type-fill="%s" data-community-fill="%s">`,
+			p.X, p.Y, html.EscapeString(n.ID), fill, hue(communities[n.ID]*137%360))
+		switch n.Type {
+		case TypeCard:
+			fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" rx="3" fill="%s"/>`, -r, -r, 2*r, 2*r, fill)
+		case TypeBoard:
+			fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" transform="rotate(45)" fill="%s"/>`, -r, -r, 2*r, 2*r, fill)
+		default:
+			fmt.Fprintf(b, `<circle r="%d" fill="%s"/>`, r, fill)
+		}
+		fmt.Fprintf(b, `<text x="%d" y="4" fill="#c0caf5" stroke="none">%s</text>`, r+4, html.EscapeString(n.Label))
+		tip := n.Label
+		if n.Board != "" {
+			tip += " — " + n.Board
+		}
+		fmt.Fprintf(b, "<title>%s (%s, %d connections)</title></g>\n", html.EscapeString(tip), n.Type, n.Connections)
+	}
+	b.WriteString("</g>\n</svg>")
+}
+
+// edgeEnds tags a line or label with its edge's ends, so the offline page
+// can hide it along with them.
+func edgeEnds(e Edge) string {
+	return fmt.Sprintf(` data-source="%s" data-target="%s"`, html.EscapeString(e.Source), html.EscapeString(e.Target))
+}
+
+// nodeRadius and nodeColor match the D3 view in GenerateHTML.
+func nodeRadius(n Node) int {
+	return int(math.Max(6, math.Min(20, float64(4+n.Connections*3))))
+}
+
+func nodeColor(n Node) string {
+	switch {
+	case n.Type == TypeCard:
+		return "#e0af68"
+	case n.Type == TypeBoard:
+		return "#bb9af7"
+	case n.WorkspaceID == "":
+		return "#7aa2f7"
+	}
+	h := 0
+	for _, c := range n.WorkspaceID {
+		h += int(c)
+	}
+	return hue(h % 360)
+}
+
+func hue(h int) string {
+	return fmt.Sprintf("hsl(%d, 60%%, 65%%)", h)
+}
@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func sampleGraph() *GraphData {
+	return &GraphData{
+		Nodes: []Node{
+			{ID: "n1", Label: "Design <draft>", Type: TypeNote, WorkspaceID: "ws", Connections: 2},
+			{ID: "n2", Label: "Notes", Type: TypeNote, Connections: 1},
+			{ID: "c1", Label: "Fix login", Type: TypeCard, Board: "Sprint", Connections: 2},
+			{ID: "b1", Label: "Sprint", Type: TypeBoard, Connections: 1},
+			{ID: "n3", Label: "Orphan", Type: TypeNote},
+		},
+		Edges: []Edge{
+			{Source: "n1", Target: "n2", Type: EdgeLink},
+			{Source: "c1", Target: "n1", Type: EdgeLink},
+			{Source: "c1", Target: "b1", Type: EdgeMember},
+		},
+	}
+}
+
+func TestLayout(t *testing.T) {
+	data := sampleGraph()
+	layout, size := Layout(data, 1)
+	if len(layout) != len(data.Nodes) {
+		t.Fatalf("expected a position for every node, got %v", layout)
+	}
+	for id, p := range layout {
+		if p.X < 0 || p.Y < 0 || p.X > size || p.Y > size {
+			t.Errorf("%s at %v is outside the %.0f square", id, p, size)
+		}
+	}
+
+	again, _ := Layout(data, 1)
+	for id, p := range layout {
+		if again[id] != p {
+			t.Errorf("layout of %s changed between runs: %v vs %v", id, p, again[id])
+		}
+	}
+	other, _ := Layout(data, 2)
+	same := true
+	for id, p := range layout {
+		same = same && other[id] == p
+	}
+	if same {
+		t.Error("expected a different seed to give a different layout")
+	}
+
+	if single, size := Layout(&GraphData{Nodes: []Node{{ID: "n"}}}, 1); single["n"] != (Point{X: size / 2, Y: size / 2}) {
+		t.Errorf("a lone node should sit in the middle, got %v", single["n"])
+	}
+	if empty, _ := Layout(&GraphData{}, 1); len(empty) != 0 {
+		t.Errorf("expected an empty layout, got %v", empty)
+	}
+}
+
+func TestGenerateSVG(t *testing.T) {
+	svg := GenerateSVG(sampleGraph(), "My Graph", 1)
+	if svg != GenerateSVG(sampleGraph(), "My Graph", 1) {
+		t.Error("expected the same SVG for the same seed")
+	}
+	checks := []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		"<title>My Graph</title>",
+		"Design &lt;draft&gt;",
+		`<circle r="10"`,
+		`rx="3" fill="#e0af68"`,
+		`transform="rotate(45)" fill="#bb9af7"`,
+		`stroke-dasharray="4 3"`,
+		"Fix login — Sprint (card, 2 connections)",
+	}
+	for _, c := range checks {
+		if !strings.Contains(svg, c) {
+			t.Errorf("missing %q", c)
+		}
+	}
+	if got := strings.Count(svg, "<line "); got != 3 {
+		t.Errorf("expected 3 links, got %d", got)
+	}
+}
+
+func TestGenerateOfflineHTML(t *testing.T) {
+	page, err := GenerateOfflineHTML(sampleGraph(), "Offline", 1)
+	if err != nil {
+		t.Fatalf("GenerateOfflineHTML: %v", err)
+	}
+	for _, c := range []string{"<title>Offline</title>", `<svg xmlns="http://www.w3.org/2000/svg" id="graph"`, `<span id="stat-nodes">5</span>`, `<span id="stat-orphans">1</span>`, "data-community-fill", `addEventListener("wheel"`, `id="f-tag"`, "function applyFilters", `data-id="n1"`, `data-source="n1"`} {
+		if !strings.Contains(page, c) {
+			t.Errorf("missing %q", c)
+		}
+	}
+	for _, external := range []string{"https://", "<script src", "<link "} {
+		if strings.Contains(page, external) {
+			t.Errorf("offline page should make no external requests, found %q", external)
+		}
+	}
+}
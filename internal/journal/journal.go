@@ -0,0 +1,253 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+	"github.com/jeryldev/kb/internal/templates"
+)
+
+const (
+	DefaultWorkspace = "Journal"
+	slugLayout       = "2006-01-02"
+	activityStart    = "<!-- kb:activity -->"
+	activityEnd      = "<!-- /kb:activity -->"
+)
+
+const defaultTemplate = `## Log
+
+## Notes
+`
+
+func Slug(day time.Time) string {
+	return day.Format(slugLayout)
+}
+
+func Title(day time.Time) string {
+	return day.Format("Monday, 2 January 2006")
+}
+
+func ParseSlug(slug string) (time.Time, bool) {
+	day, err := time.ParseInLocation(slugLayout, slug, time.Local)
+	return day, err == nil
+}
+
+// ParseDay accepts a date (2006-01-02), "today", "yesterday", "tomorrow"
+// or a day offset such as "+1" or "-3", relative to now.
+func ParseDay(arg string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(strings.TrimSpace(arg)) {
+	case "", "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	if strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-") {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid day offset %q", arg)
+		}
+		return today.AddDate(0, 0, n), nil
+	}
+	day, err := time.ParseInLocation(slugLayout, arg, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD, today, yesterday, tomorrow or +N/-N", arg)
+	}
+	return day, nil
+}
+
+func WorkspaceName() string {
+	if name := os.Getenv("KB_JOURNAL_WORKSPACE"); name != "" {
+		return name
+	}
+	return DefaultWorkspace
+}
+
+// LoadTemplate reads the daily note template from KB_JOURNAL_TEMPLATE or
+// $XDG_CONFIG_HOME/kb/journal.md, falling back to a built-in template.
+func LoadTemplate() (string, error) {
+	path := os.Getenv("KB_JOURNAL_TEMPLATE")
+	if path == "" {
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if configDir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return defaultTemplate, nil
+			}
+			configDir = filepath.Join(home, ".config")
+		}
+		path = filepath.Join(configDir, "kb", "journal.md")
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && os.Getenv("KB_JOURNAL_TEMPLATE") == "" {
+		return defaultTemplate, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading journal template: %w", err)
+	}
+	return string(data), nil
+}
+
+func Render(tmpl string, day time.Time, workspace string) string {
+	return templates.Expand(tmpl, map[string]string{
+		"date":      Slug(day),
+		"title":     Title(day),
+		"weekday":   day.Weekday().String(),
+		"yesterday": Slug(day.AddDate(0, 0, -1)),
+		"tomorrow":  Slug(day.AddDate(0, 0, 1)),
+		"workspace": workspace,
+	}, nil)
+}
+
+func ActivitySection(moves []*model.CardMove) string {
+	if len(moves) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(activityStart + "\n")
+	b.WriteString("## Board activity\n\n")
+	for _, m := range moves {
+		link := fmt.Sprintf("[[card:%s|%s]]", m.CardID, m.CardTitle)
+		if m.Done {
+			fmt.Fprintf(&b, "- ✓ %s completed in %s (%s)\n", link, m.ToColumn, m.BoardName)
+		} else {
+			fmt.Fprintf(&b, "- %s %s → %s (%s)\n", link, m.FromColumn, m.ToColumn, m.BoardName)
+		}
+	}
+	b.WriteString(activityEnd)
+	return b.String()
+}
+
+// ReplaceActivity swaps the generated activity section in body for section,
+// appending it when the body has none yet. Text outside the markers is kept.
+func ReplaceActivity(body, section string) string {
+	start := strings.Index(body, activityStart)
+	end := strings.Index(body, activityEnd)
+	if start != -1 && end > start {
+		rest := body[end+len(activityEnd):]
+		if section == "" {
+			return strings.TrimRight(body[:start], "\n") + "\n" + strings.TrimLeft(rest, "\n")
+		}
+		return body[:start] + section + rest
+	}
+	if section == "" {
+		return body
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + section + "\n"
+}
+
+// Open returns the daily note for day in the named workspace, creating the
+// workspace and the note from the template when missing. A daily note that
+// was archived or moved to the trash is brought back instead. The board
+// activity section is regenerated every time. created reports a new note.
+func Open(db *store.DB, day time.Time, workspace string) (note *model.Note, created bool, err error) {
+	workspaces, err := db.ListWorkspaces()
+	if err != nil {
+		return nil, false, err
+	}
+	var ws *model.Workspace
+	for _, w := range workspaces {
+		if strings.EqualFold(w.Name, workspace) {
+			ws = w
+		}
+	}
+	if ws == nil {
+		ws, err = db.CreateWorkspace(workspace, model.KindArea, "Daily notes", "")
+		if err != nil {
+			return nil, false, fmt.Errorf("creating journal workspace: %w", err)
+		}
+	}
+
+	slug := Slug(day)
+	note, err = db.GetNoteBySlug(slug)
+	if err != nil {
+		if note, err = revive(db, slug); err != nil {
+			return nil, false, err
+		}
+	}
+	if note == nil {
+		tmpl, err := LoadTemplate()
+		if err != nil {
+			return nil, false, err
+		}
+		note, err = db.CreateNote(Title(day), slug, Render(tmpl, day, ws.Name), ws.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		created = true
+	}
+
+	moves, err := db.ListCardMoves(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, false, err
+	}
+	body := ReplaceActivity(note.Body, ActivitySection(moves))
+	if body != note.Body || created {
+		note.Body = body
+		if err := db.UpdateNote(note); err != nil {
+			return nil, false, err
+		}
+		if err := db.SyncNoteLinks(note); err != nil {
+			return nil, false, err
+		}
+	}
+	return note, created, nil
+}
+
+// revive restores the archived or trashed note with slug, returning nil
+// when there is none.
+func revive(db *store.DB, slug string) (*model.Note, error) {
+	trashed, err := db.ListTrashedNotes()
+	if err != nil {
+		return nil, err
+	}
+	archived, err := db.ListArchivedNotes()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range append(trashed, archived...) {
+		if n.Slug != slug {
+			continue
+		}
+		if n.DeletedAt != nil {
+			if err := db.RestoreNote(n.ID); err != nil {
+				return nil, err
+			}
+		}
+		if n.ArchivedAt != nil {
+			if err := db.UnarchiveNote(n.ID); err != nil {
+				return nil, err
+			}
+		}
+		return db.GetNote(n.ID)
+	}
+	return nil, nil
+}
+
+func Adjacent(db *store.DB, day time.Time, dir int) (*model.Note, error) {
+	notes, err := db.ListNotes()
+	if err != nil {
+		return nil, err
+	}
+	var best *model.Note
+	var bestDay time.Time
+	for _, n := range notes {
+		d, ok := ParseSlug(n.Slug)
+		if !ok || d.Equal(day) || (dir < 0) != d.Before(day) {
+			continue
+		}
+		if best == nil || (dir < 0 && d.After(bestDay)) || (dir > 0 && d.Before(bestDay)) {
+			best, bestDay = n, d
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,224 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+)
+
+func testDB(t *testing.T) *store.DB {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KB_JOURNAL_TEMPLATE", "")
+	db, err := store.OpenWithPath(":memory:")
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestParseDay(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.Local)
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"", "2026-10-18"},
+		{"today", "2026-10-18"},
+		{"Yesterday", "2026-10-17"},
+		{"tomorrow", "2026-10-19"},
+		{"+1", "2026-10-19"},
+		{"-14", "2026-10-04"},
+		{"2026-02-28", "2026-02-28"},
+	}
+	for _, tt := range tests {
+		got, err := ParseDay(tt.arg, now)
+		if err != nil {
+			t.Errorf("ParseDay(%q) error: %v", tt.arg, err)
+			continue
+		}
+		if Slug(got) != tt.want {
+			t.Errorf("ParseDay(%q) = %s, want %s", tt.arg, Slug(got), tt.want)
+		}
+	}
+
+	for _, bad := range []string{"next week", "+x", "2026-13-01"} {
+		if _, err := ParseDay(bad, now); err == nil {
+			t.Errorf("ParseDay(%q) expected error", bad)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+	got := Render("{{title}} ({{weekday}}) [[{{yesterday}}]] [[{{tomorrow}}]] {{workspace}} {{date}}", day, "Journal")
+	want := "Sunday, 18 October 2026 (Sunday) [[2026-10-17]] [[2026-10-19]] Journal 2026-10-18"
+	if got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestReplaceActivity(t *testing.T) {
+	section := ActivitySection([]*model.CardMove{
+		{CardID: "c1", CardTitle: "Fix login", BoardName: "sprint", FromColumn: "Todo", ToColumn: "In Progress"},
+		{CardID: "c2", CardTitle: "Ship", BoardName: "sprint", FromColumn: "Review", ToColumn: "Done", Done: true},
+	})
+	if !strings.Contains(section, "[[card:c1|Fix login]] Todo → In Progress (sprint)") {
+		t.Errorf("missing move line: %q", section)
+	}
+	if !strings.Contains(section, "✓ [[card:c2|Ship]] completed in Done") {
+		t.Errorf("missing completed line: %q", section)
+	}
+
+	body := ReplaceActivity("## Log\n\nwrote code\n", section)
+	if !strings.HasPrefix(body, "## Log\n\nwrote code\n\n"+activityStart) {
+		t.Errorf("expected section appended, got %q", body)
+	}
+
+	body += "\nafter"
+	replaced := ReplaceActivity(body, ActivitySection([]*model.CardMove{
+		{CardID: "c3", CardTitle: "Other", BoardName: "b", FromColumn: "A", ToColumn: "B"},
+	}))
+	if strings.Contains(replaced, "Fix login") || !strings.Contains(replaced, "Other") {
+		t.Errorf("expected section replaced, got %q", replaced)
+	}
+	if !strings.HasSuffix(replaced, "\nafter") || !strings.Contains(replaced, "wrote code") {
+		t.Errorf("expected text outside markers kept, got %q", replaced)
+	}
+
+	if got := ReplaceActivity("plain", ""); got != "plain" {
+		t.Errorf("expected body unchanged, got %q", got)
+	}
+}
+
+func TestOpenCreatesNoteOnce(t *testing.T) {
+	db := testDB(t)
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+
+	note, created, err := Open(db, day, DefaultWorkspace)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if !created || note.Slug != "2026-10-18" || note.Title != "Sunday, 18 October 2026" {
+		t.Errorf("unexpected note: created=%v slug=%q title=%q", created, note.Slug, note.Title)
+	}
+	if !strings.Contains(note.Body, "## Log") {
+		t.Errorf("expected default template, got %q", note.Body)
+	}
+
+	ws, err := db.GetWorkspaceByName(DefaultWorkspace)
+	if err != nil {
+		t.Fatalf("expected journal workspace: %v", err)
+	}
+	if note.WorkspaceID != ws.ID {
+		t.Errorf("note workspace = %q, want %q", note.WorkspaceID, ws.ID)
+	}
+
+	again, created, err := Open(db, day, "journal")
+	if err != nil {
+		t.Fatalf("second Open failed: %v", err)
+	}
+	if created || again.ID != note.ID {
+		t.Error("expected existing note to be reused")
+	}
+}
+
+func TestOpenRevivesArchivedAndTrashedNotes(t *testing.T) {
+	db := testDB(t)
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+	note, _, _ := Open(db, day, DefaultWorkspace)
+
+	db.ArchiveNote(note.ID)
+	again, created, err := Open(db, day, DefaultWorkspace)
+	if err != nil || created || again.ID != note.ID {
+		t.Fatalf("expected the archived note back: %v, created = %v", err, created)
+	}
+
+	db.ArchiveNote(note.ID)
+	db.DeleteNote(note.ID)
+	again, created, err = Open(db, day, DefaultWorkspace)
+	if err != nil || created || again.ID != note.ID {
+		t.Fatalf("expected the trashed note back: %v, created = %v", err, created)
+	}
+	if archived, _ := db.ListArchivedNotes(); len(archived) != 0 {
+		t.Errorf("expected the note to be active again, %d archived", len(archived))
+	}
+}
+
+func TestOpenUsesTemplateFile(t *testing.T) {
+	db := testDB(t)
+	path := filepath.Join(t.TempDir(), "daily.md")
+	os.WriteFile(path, []byte("# {{date}}\n\n- [ ] plan the day\n"), 0o644)
+	t.Setenv("KB_JOURNAL_TEMPLATE", path)
+
+	note, _, err := Open(db, time.Date(2026, 1, 2, 0, 0, 0, 0, time.Local), DefaultWorkspace)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if note.Body != "# 2026-01-02\n\n- [ ] plan the day\n" {
+		t.Errorf("body = %q", note.Body)
+	}
+
+	t.Setenv("KB_JOURNAL_TEMPLATE", filepath.Join(t.TempDir(), "missing.md"))
+	if _, _, err := Open(db, time.Date(2026, 1, 3, 0, 0, 0, 0, time.Local), DefaultWorkspace); err == nil {
+		t.Error("expected error for missing template file")
+	}
+}
+
+func TestOpenListsCardActivity(t *testing.T) {
+	db := testDB(t)
+	ws, _ := db.GetDefaultWorkspace()
+	board, _ := db.CreateBoard("sprint", "", ws.ID)
+	columns, _ := db.ListColumns(board.ID)
+	card, _ := db.CreateCard(columns[0].ID, "Fix login", model.PriorityHigh)
+	db.MoveCard(card.ID, columns[len(columns)-1].ID)
+
+	today, _ := ParseDay("today", time.Now())
+	note, _, err := Open(db, today, DefaultWorkspace)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if !strings.Contains(note.Body, "✓ [[card:"+card.ID+"|Fix login]]") {
+		t.Errorf("expected completed card in activity section, got %q", note.Body)
+	}
+
+	links, _ := db.GetBacklinks("card", card.ID)
+	if len(links) != 1 {
+		t.Errorf("expected journal note to link the card, got %d links", len(links))
+	}
+
+	yesterday, _, _ := Open(db, today.AddDate(0, 0, -1), DefaultWorkspace)
+	if strings.Contains(yesterday.Body, "Fix login") {
+		t.Error("expected no activity on a day without moves")
+	}
+}
+
+func TestAdjacent(t *testing.T) {
+	db := testDB(t)
+	ws, _ := db.GetDefaultWorkspace()
+	for _, slug := range []string{"2026-10-01", "2026-10-10", "2026-10-20"} {
+		db.CreateNote(slug, slug, "", ws.ID)
+	}
+	db.CreateNote("Unrelated", "unrelated", "", ws.ID)
+
+	day, _ := ParseSlug("2026-10-10")
+	prev, _ := Adjacent(db, day, -1)
+	next, _ := Adjacent(db, day, 1)
+	if prev == nil || prev.Slug != "2026-10-01" {
+		t.Errorf("prev = %v, want 2026-10-01", prev)
+	}
+	if next == nil || next.Slug != "2026-10-20" {
+		t.Errorf("next = %v, want 2026-10-20", next)
+	}
+
+	last, _ := ParseSlug("2026-10-20")
+	if n, _ := Adjacent(db, last, 1); n != nil {
+		t.Errorf("expected no later note, got %q", n.Slug)
+	}
+}
@@ -0,0 +1,135 @@
+package mdkanban
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+)
+
+// Load reads board from db for export. The last column is marked complete,
+// and card attachments not already mentioned in a description are added to
+// it as ![[file]] embeds; the attachments are returned so their files can
+// be copied alongside the markdown.
+func Load(db *store.DB, board *model.Board) (*Board, []*model.Attachment, error) {
+	columns, err := db.ListColumns(board.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cards, err := db.ListBoardCards(board.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	archived, err := db.ListArchivedBoardCards(board.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var attachments []*model.Attachment
+	embed := func(card *model.Card) error {
+		list, err := db.ListAttachments("card", card.ID)
+		if err != nil {
+			return err
+		}
+		for _, a := range list {
+			attachments = append(attachments, a)
+			if strings.Contains(card.Description, "[["+a.Name) {
+				continue
+			}
+			if card.Description != "" {
+				card.Description += "\n"
+			}
+			card.Description += "![[" + a.Name + "]]"
+		}
+		return nil
+	}
+
+	b := &Board{}
+	lanes := make(map[string]int, len(columns))
+	for i, col := range columns {
+		lanes[col.ID] = i
+		b.Lanes = append(b.Lanes, Lane{Name: col.Name, WIPLimit: col.WIPLimit, Complete: i == len(columns)-1})
+	}
+	for _, card := range cards {
+		if err := embed(card); err != nil {
+			return nil, nil, err
+		}
+		i := lanes[card.ColumnID]
+		b.Lanes[i].Items = append(b.Lanes[i].Items, Item{Card: card, Checked: b.Lanes[i].Complete})
+	}
+	for _, card := range archived {
+		if err := embed(card); err != nil {
+			return nil, nil, err
+		}
+		b.Archived = append(b.Archived, Item{Card: card, Checked: lanes[card.ColumnID] == len(columns)-1})
+	}
+	return b, attachments, nil
+}
+
+type ImportResult struct {
+	Board    *model.Board
+	Cards    int
+	Archived int
+	Attached int
+	Missing  []string
+}
+
+// Import creates a board named name from b. Archived items are restored
+// into the last column when checked and the first column otherwise. Files
+// embedded in card descriptions are attached from dir when they exist
+// there; paths leaving dir are ignored.
+func Import(db *store.DB, b *Board, name, workspaceID, dir string) (*ImportResult, error) {
+	var columns []*model.Column
+	var cards []*model.Card
+	for _, lane := range b.Lanes {
+		col := &model.Column{ID: uuid.New().String(), Name: lane.Name, WIPLimit: lane.WIPLimit}
+		columns = append(columns, col)
+		for _, item := range lane.Items {
+			item.Card.ColumnID = col.ID
+			cards = append(cards, item.Card)
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, item := range b.Archived {
+		item.Card.ColumnID = columns[0].ID
+		if item.Checked {
+			item.Card.ColumnID = columns[len(columns)-1].ID
+		}
+		item.Card.ArchivedAt = &now
+		cards = append(cards, item.Card)
+	}
+
+	board := &model.Board{Name: name, WorkspaceID: workspaceID}
+	if err := db.ImportBoard(board, columns, cards); err != nil {
+		return nil, err
+	}
+	result := &ImportResult{Board: board, Cards: len(cards) - len(b.Archived), Archived: len(b.Archived)}
+	if dir == "" {
+		return result, nil
+	}
+
+	for _, card := range cards {
+		seen := make(map[string]bool)
+		for _, link := range model.ParseWikilinks(card.Description) {
+			if link.TargetType != "attachment" || !filepath.IsLocal(link.TargetRef) || seen[strings.ToLower(link.TargetRef)] {
+				continue
+			}
+			seen[strings.ToLower(link.TargetRef)] = true
+			path := filepath.Join(dir, link.TargetRef)
+			if _, err := os.Stat(path); err != nil {
+				result.Missing = append(result.Missing, link.TargetRef)
+				continue
+			}
+			if _, err := db.AttachFile("card", card.ID, path); err != nil {
+				return result, err
+			}
+			result.Attached++
+		}
+	}
+	return result, nil
+}
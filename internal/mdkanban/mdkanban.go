@@ -0,0 +1,226 @@
+// Package mdkanban reads and writes boards in the markdown format of the
+// Obsidian Kanban plugin: "## Column" headings holding "- [ ] card" items,
+// an optional archive after a "***" rule and a trailing settings block.
+package mdkanban
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// Format is the name used for this format on the command line.
+const Format = "md-kanban"
+
+const settingsBlock = "%% kanban:settings\n```\n{\"kanban-plugin\":\"basic\"}\n```\n%%"
+
+type Board struct {
+	Lanes    []Lane
+	Archived []Item
+}
+
+// Lane is a column. Complete marks the lane whose items the plugin treats
+// as done.
+type Lane struct {
+	Name     string
+	WIPLimit *int
+	Complete bool
+	Items    []Item
+}
+
+type Item struct {
+	Card    *model.Card
+	Checked bool
+}
+
+var (
+	laneRe     = regexp.MustCompile(`^##\s+(.*?)(?:\s+\((\d+)\))?\s*$`)
+	itemRe     = regexp.MustCompile(`^[-*+]\s+\[([ xX])\]\s?(.*)$`)
+	labelRe    = regexp.MustCompile(`(^|\s)#([\p{L}\p{N}_/-]+)`)
+	priorityRe = regexp.MustCompile(`(?i)(^|\s)!(low|medium|high|urgent)\b`)
+	externalRe = regexp.MustCompile(`(^|\s)\[external-id::\s*([^\]]*?)\s*\]`)
+	spacesRe   = regexp.MustCompile(`\s{2,}`)
+)
+
+// Parse reads a board in Obsidian Kanban markdown. Card titles carry labels
+// as #tags, priorities as !high and external IDs as [external-id:: X]; lines
+// indented below an item become the card description.
+func Parse(text string) (*Board, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(model.StripFrontMatter(text), "\n")
+
+	b := &Board{}
+	var lane *Lane
+	var item *Item
+	var desc []string
+	inArchive, afterRule := false, false
+
+	flush := func() {
+		if item == nil {
+			return
+		}
+		if d := strings.TrimSpace(strings.Join(desc, "\n")); d != "" {
+			if item.Card.Description != "" {
+				item.Card.Description += "\n"
+			}
+			item.Card.Description += d
+		}
+		if inArchive {
+			b.Archived = append(b.Archived, *item)
+		} else {
+			lane.Items = append(lane.Items, *item)
+		}
+		item, desc = nil, nil
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "%% kanban:settings") {
+			break
+		}
+
+		if item != nil && (strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "  ") || (trimmed == "" && len(desc) > 0)) {
+			desc = append(desc, unindent(line))
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+		flush()
+
+		switch {
+		case trimmed == "***":
+			afterRule = true
+		case laneRe.MatchString(trimmed):
+			if afterRule {
+				inArchive = true
+				continue
+			}
+			m := laneRe.FindStringSubmatch(trimmed)
+			b.Lanes = append(b.Lanes, Lane{Name: m[1]})
+			lane = &b.Lanes[len(b.Lanes)-1]
+			if m[2] != "" {
+				n, _ := strconv.Atoi(m[2])
+				lane.WIPLimit = &n
+			}
+		case trimmed == "**Complete**":
+			if lane != nil && !inArchive {
+				lane.Complete = true
+			}
+		case itemRe.MatchString(trimmed):
+			if lane == nil && !inArchive {
+				return nil, fmt.Errorf("card %q is not under a \"## Column\" heading", trimmed)
+			}
+			m := itemRe.FindStringSubmatch(trimmed)
+			item = &Item{Card: parseCard(m[2]), Checked: m[1] != " "}
+		}
+	}
+	flush()
+
+	if len(b.Lanes) == 0 {
+		return nil, fmt.Errorf("no columns found: expected \"## Column\" headings")
+	}
+	return b, nil
+}
+
+func unindent(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	for i := 0; i < 4 && strings.HasPrefix(line, " "); i++ {
+		line = line[1:]
+	}
+	return line
+}
+
+// parseCard lifts labels, priority and external ID out of an item's text.
+// Older versions of the plugin join multi-line items with <br>.
+func parseCard(text string) *model.Card {
+	card := &model.Card{Priority: model.PriorityMedium}
+	if title, rest, ok := strings.Cut(text, "<br>"); ok {
+		text = title
+		card.Description = strings.TrimSpace(strings.ReplaceAll(rest, "<br>", "\n"))
+	}
+
+	if m := externalRe.FindStringSubmatch(text); m != nil {
+		card.ExternalID = m[2]
+		text = externalRe.ReplaceAllString(text, "$1")
+	}
+	if m := priorityRe.FindStringSubmatch(text); m != nil {
+		card.Priority, _ = model.ParsePriority(m[2])
+		text = priorityRe.ReplaceAllString(text, "$1")
+	}
+	var labels []string
+	text = labelRe.ReplaceAllStringFunc(text, func(s string) string {
+		tag := s[strings.Index(s, "#")+1:]
+		if !strings.ContainsFunc(tag, unicode.IsLetter) {
+			return s
+		}
+		labels = append(labels, tag)
+		return s[:strings.Index(s, "#")]
+	})
+	card.Labels = strings.Join(labels, ",")
+	card.Title = strings.TrimSpace(spacesRe.ReplaceAllString(text, " "))
+	return card
+}
+
+// Render writes b in Obsidian Kanban markdown.
+func Render(b *Board) string {
+	var sb strings.Builder
+	sb.WriteString("---\n\nkanban-plugin: basic\n\n---\n\n")
+	for _, lane := range b.Lanes {
+		sb.WriteString("## " + lane.Name)
+		if lane.WIPLimit != nil {
+			fmt.Fprintf(&sb, " (%d)", *lane.WIPLimit)
+		}
+		sb.WriteString("\n\n")
+		if lane.Complete {
+			sb.WriteString("**Complete**\n")
+		}
+		for _, item := range lane.Items {
+			writeItem(&sb, item)
+		}
+		sb.WriteString("\n\n")
+	}
+	if len(b.Archived) > 0 {
+		sb.WriteString("***\n\n## Archive\n\n")
+		for _, item := range b.Archived {
+			writeItem(&sb, item)
+		}
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString(settingsBlock + "\n")
+	return sb.String()
+}
+
+func writeItem(sb *strings.Builder, item Item) {
+	box := " "
+	if item.Checked {
+		box = "x"
+	}
+	card := item.Card
+	parts := []string{card.Title}
+	for _, l := range card.LabelList() {
+		parts = append(parts, "#"+strings.ReplaceAll(l, " ", "-"))
+	}
+	if card.Priority != "" && card.Priority != model.PriorityMedium {
+		parts = append(parts, "!"+string(card.Priority))
+	}
+	if card.ExternalID != "" {
+		parts = append(parts, "[external-id:: "+card.ExternalID+"]")
+	}
+	fmt.Fprintf(sb, "- [%s] %s\n", box, strings.Join(parts, " "))
+	if desc := strings.TrimSpace(card.Description); desc != "" {
+		for _, line := range strings.Split(desc, "\n") {
+			if strings.TrimSpace(line) == "" {
+				sb.WriteString("\n")
+				continue
+			}
+			sb.WriteString("\t" + line + "\n")
+		}
+	}
+}
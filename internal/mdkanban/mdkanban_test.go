@@ -0,0 +1,172 @@
+package mdkanban
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+)
+
+const sample = `---
+
+kanban-plugin: basic
+
+---
+
+## Todo (3)
+
+- [ ] Fix login bug #bug #auth !urgent [external-id:: JIRA-12]
+	Steps to reproduce:
+
+	1. Log in twice
+- [ ] Issue #42 stays in the title
+- [ ] Old style<br>second line
+
+
+## Done
+
+**Complete**
+- [x] Ship v1
+
+
+***
+
+## Archive
+
+- [x] Retired task
+- [ ] Dropped idea
+
+%% kanban:settings
+` + "```" + `
+{"kanban-plugin":"basic"}
+` + "```" + `
+%%
+`
+
+func TestParse(t *testing.T) {
+	b, err := Parse(sample)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+	if len(b.Lanes) != 2 || b.Lanes[0].Name != "Todo" || b.Lanes[0].WIPLimit == nil || *b.Lanes[0].WIPLimit != 3 {
+		t.Fatalf("unexpected lanes: %+v", b.Lanes)
+	}
+	if b.Lanes[0].Complete || !b.Lanes[1].Complete {
+		t.Error("expected only Done to be complete")
+	}
+
+	bug := b.Lanes[0].Items[0].Card
+	if bug.Title != "Fix login bug" || bug.Labels != "bug,auth" || bug.Priority != model.PriorityUrgent ||
+		bug.ExternalID != "JIRA-12" {
+		t.Errorf("unexpected card: %+v", bug)
+	}
+	if bug.Description != "Steps to reproduce:\n\n1. Log in twice" {
+		t.Errorf("description = %q", bug.Description)
+	}
+
+	issue := b.Lanes[0].Items[1].Card
+	if issue.Title != "Issue #42 stays in the title" || issue.Labels != "" || issue.Priority != model.PriorityMedium {
+		t.Errorf("unexpected card: %+v", issue)
+	}
+	old := b.Lanes[0].Items[2].Card
+	if old.Title != "Old style" || old.Description != "second line" {
+		t.Errorf("unexpected <br> card: %+v", old)
+	}
+
+	if len(b.Lanes[1].Items) != 1 || !b.Lanes[1].Items[0].Checked {
+		t.Errorf("unexpected Done items: %+v", b.Lanes[1].Items)
+	}
+	if len(b.Archived) != 2 || !b.Archived[0].Checked || b.Archived[1].Checked {
+		t.Errorf("unexpected archive: %+v", b.Archived)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse("just text"); err == nil {
+		t.Error("expected error without columns")
+	}
+	if _, err := Parse("- [ ] orphan\n## Todo"); err == nil {
+		t.Error("expected error for a card before any column")
+	}
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	b, err := Parse(sample)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+	out := Render(b)
+	for _, want := range []string{
+		"---\n\nkanban-plugin: basic\n\n---\n\n## Todo (3)\n\n",
+		"- [ ] Fix login bug #bug #auth !urgent [external-id:: JIRA-12]\n\tSteps to reproduce:\n\n\t1. Log in twice\n",
+		"## Done\n\n**Complete**\n- [x] Ship v1\n",
+		"***\n\n## Archive\n\n- [x] Retired task\n- [ ] Dropped idea\n",
+		"%% kanban:settings",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	again, err := Parse(out)
+	if err != nil {
+		t.Fatalf("parsing rendered board: %v", err)
+	}
+	if Render(again) != out {
+		t.Errorf("round trip changed the board:\n%s", Render(again))
+	}
+}
+
+func TestImportAndLoad(t *testing.T) {
+	db, err := store.OpenWithPath(":memory:")
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetAttachmentDir(t.TempDir())
+	ws, _ := db.GetDefaultWorkspace()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "shot.png"), []byte("png"), 0o644)
+	b, _ := Parse("## Todo\n\n- [ ] With files\n\t![[shot.png]] ![[gone.pdf]] ![[../secret.txt]]\n## Done\n\n***\n## Archive\n- [x] Old")
+
+	result, err := Import(db, b, "Imported", ws.ID, dir)
+	if err != nil {
+		t.Fatalf("importing: %v", err)
+	}
+	if result.Cards != 1 || result.Archived != 1 || result.Attached != 1 ||
+		len(result.Missing) != 1 || result.Missing[0] != "gone.pdf" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	board, _ := db.GetBoardByName("Imported")
+	cols, _ := db.ListColumns(board.ID)
+	if len(cols) != 2 || cols[0].Name != "Todo" || cols[1].Name != "Done" {
+		t.Fatalf("unexpected columns: %+v", cols)
+	}
+	archived, _ := db.ListArchivedBoardCards(board.ID)
+	if len(archived) != 1 || archived[0].ColumnID != cols[1].ID {
+		t.Errorf("expected checked archived card in the last column: %+v", archived)
+	}
+
+	loaded, attachments, err := Load(db, board)
+	if err != nil {
+		t.Fatalf("loading: %v", err)
+	}
+	if len(attachments) != 1 || attachments[0].Name != "shot.png" {
+		t.Errorf("unexpected attachments: %+v", attachments)
+	}
+	if !loaded.Lanes[1].Complete || len(loaded.Archived) != 1 || !loaded.Archived[0].Checked {
+		t.Errorf("unexpected loaded board: %+v", loaded)
+	}
+	if strings.Count(Render(loaded), "![[shot.png]]") != 1 {
+		t.Errorf("attachment embed should not be duplicated:\n%s", Render(loaded))
+	}
+
+	if _, err := Import(db, b, "Imported", ws.ID, ""); err == nil {
+		t.Error("expected error for duplicate board name")
+	}
+}
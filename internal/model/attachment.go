@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Attachment struct {
+	ID        string
+	Hash      string
+	Name      string
+	MimeType  string
+	Size      int64
+	NoteID    string
+	CardID    string
+	CreatedAt time.Time
+}
+
+// Owner returns the kind and ID of the note or card the file is attached to.
+func (a *Attachment) Owner() (kind, id string) {
+	if a.CardID != "" {
+		return "card", a.CardID
+	}
+	return "note", a.NoteID
+}
+
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.MimeType, "image/")
+}
+
+// attachmentExts are the file extensions that make a wikilink point at an
+// attachment rather than a note, as in ![[diagram.png]].
+var attachmentExts = map[string]bool{
+	"png": true, "jpg": true, "jpeg": true, "gif": true, "webp": true, "svg": true, "bmp": true,
+	"pdf": true, "txt": true, "log": true, "csv": true, "json": true, "xml": true, "yaml": true, "yml": true,
+	"zip": true, "gz": true, "tar": true,
+	"mp3": true, "wav": true, "ogg": true, "mp4": true, "mov": true, "webm": true,
+	"doc": true, "docx": true, "xls": true, "xlsx": true, "ppt": true, "pptx": true, "odt": true,
+}
+
+// IsAttachmentRef reports whether a wikilink target names a file.
+func IsAttachmentRef(ref string) bool {
+	ext := strings.TrimPrefix(filepath.Ext(ref), ".")
+	return attachmentExts[strings.ToLower(ext)]
+}
+
+func ValidateAttachmentName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("attachment name cannot be empty")
+	}
+	if strings.ContainsAny(name, "/\\[]|#^") {
+		return fmt.Errorf("attachment name %q cannot contain / \\ [ ] | # or ^", name)
+	}
+	return nil
+}
+
+// FormatSize renders a byte count as a short human-readable size.
+func FormatSize(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	}
+}
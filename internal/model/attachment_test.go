@@ -0,0 +1,52 @@
+package model
+
+import "testing"
+
+func TestIsAttachmentRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"diagram.png", true},
+		{"Report.PDF", true},
+		{"build output.log", true},
+		{"my-note", false},
+		{"Node.js", false},
+		{"v1.2", false},
+	}
+	for _, tt := range tests {
+		if got := IsAttachmentRef(tt.ref); got != tt.want {
+			t.Errorf("IsAttachmentRef(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAttachmentName(t *testing.T) {
+	if err := ValidateAttachmentName("screenshot 1.png"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"", "  ", "a/b.png", "x[1].png", "a|b.pdf"} {
+		if err := ValidateAttachmentName(name); err == nil {
+			t.Errorf("ValidateAttachmentName(%q) expected error", name)
+		}
+	}
+}
+
+func TestAttachmentOwner(t *testing.T) {
+	kind, id := (&Attachment{CardID: "c1"}).Owner()
+	if kind != "card" || id != "c1" {
+		t.Errorf("Owner() = %s %s", kind, id)
+	}
+	kind, id = (&Attachment{NoteID: "n1"}).Owner()
+	if kind != "note" || id != "n1" {
+		t.Errorf("Owner() = %s %s", kind, id)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 2048: "2.0 KB", 3 * 1024 * 1024: "3.0 MB"} {
+		if got := FormatSize(n); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -54,6 +54,10 @@ func (p Priority) Prev() Priority {
 	return Priorities[len(Priorities)-1]
 }
 
+// MinCardIDPrefix is the shortest card ID prefix accepted in place of a
+// full ID.
+const MinCardIDPrefix = 4
+
 type Card struct {
 	ID          string
 	ColumnID    string
@@ -69,6 +73,17 @@ type Card struct {
 	UpdatedAt   time.Time
 }
 
+type CardMove struct {
+	ID         string
+	CardID     string
+	CardTitle  string
+	BoardName  string
+	FromColumn string
+	ToColumn   string
+	Done       bool
+	MovedAt    time.Time
+}
+
 func (c *Card) LabelList() []string {
 	if c.Labels == "" {
 		return nil
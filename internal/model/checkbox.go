@@ -0,0 +1,84 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+var checkboxRe = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+\[)([ xX])(\]\s+)(.*)$`)
+
+// Checkbox is a "- [ ] task" line in a note body. Marker is the block ID at
+// the end of the line, if any, which keeps a promoted checkbox tied to its
+// card across edits.
+type Checkbox struct {
+	Line    int
+	Text    string
+	Checked bool
+	Marker  string
+}
+
+// ParseCheckboxes returns the task list items of body, skipping front
+// matter and fenced code.
+func ParseCheckboxes(body string) []Checkbox {
+	var boxes []Checkbox
+	forEachCheckbox(strings.Split(body, "\n"), func(i int, m []string) {
+		text := m[4]
+		marker := ""
+		if id, rest, ok := ParseBlockID(text); ok {
+			marker, text = id, rest
+		}
+		boxes = append(boxes, Checkbox{
+			Line:    i,
+			Text:    strings.TrimSpace(text),
+			Checked: m[2] != " ",
+			Marker:  marker,
+		})
+	})
+	return boxes
+}
+
+// SetCheckbox ticks or clears the checkbox carrying marker. It reports
+// whether body changed.
+func SetCheckbox(body, marker string, checked bool) (string, bool) {
+	lines := strings.Split(body, "\n")
+	changed := false
+	forEachCheckbox(lines, func(i int, m []string) {
+		if id, _, ok := ParseBlockID(m[4]); !ok || id != marker || (m[2] != " ") == checked {
+			return
+		}
+		mark := " "
+		if checked {
+			mark = "x"
+		}
+		lines[i] = m[1] + mark + m[3] + m[4]
+		changed = true
+	})
+	return strings.Join(lines, "\n"), changed
+}
+
+// MarkCheckbox appends a block ID marker to the checkbox on line i.
+func MarkCheckbox(body string, line int, marker string) string {
+	lines := strings.Split(body, "\n")
+	if line >= 0 && line < len(lines) {
+		lines[line] = strings.TrimRight(lines[line], " \t") + " ^" + marker
+	}
+	return strings.Join(lines, "\n")
+}
+
+func forEachCheckbox(lines []string, fn func(i int, m []string)) {
+	first := frontMatterLines(lines)
+
+	inFence := false
+	for i := first; i < len(lines); i++ {
+		if isFence(lines[i]) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := checkboxRe.FindStringSubmatch(strings.TrimRight(lines[i], "\r")); m != nil {
+			fn(i, m)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCheckboxes(t *testing.T) {
+	body := `---
+todo: "- [ ] not a task"
+---
+# Actions
+
+- [ ] Send the agenda
+* [x] Book a room ^card-1a2b3c4d
+  1. [X] Nested and numbered
+- [] not a checkbox
+` + "```" + `
+- [ ] in code
+` + "```"
+
+	got := ParseCheckboxes(body)
+	want := []Checkbox{
+		{Line: 5, Text: "Send the agenda"},
+		{Line: 6, Text: "Book a room", Checked: true, Marker: "card-1a2b3c4d"},
+		{Line: 7, Text: "Nested and numbered", Checked: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCheckboxes = %+v, want %+v", got, want)
+	}
+}
+
+func TestMarkAndSetCheckbox(t *testing.T) {
+	body := "Intro\n- [ ] Send the agenda  \n- [ ] Other"
+
+	body = MarkCheckbox(body, 1, "card-abc")
+	if body != "Intro\n- [ ] Send the agenda ^card-abc\n- [ ] Other" {
+		t.Fatalf("MarkCheckbox = %q", body)
+	}
+
+	got, changed := SetCheckbox(body, "card-abc", true)
+	if !changed || got != "Intro\n- [x] Send the agenda ^card-abc\n- [ ] Other" {
+		t.Fatalf("SetCheckbox(true) = %q, %v", got, changed)
+	}
+	if _, changed := SetCheckbox(got, "card-abc", true); changed {
+		t.Error("expected no change when already checked")
+	}
+	if _, changed := SetCheckbox(got, "card-missing", false); changed {
+		t.Error("expected no change for unknown marker")
+	}
+
+	got, changed = SetCheckbox(got, "card-abc", false)
+	if !changed || got != body {
+		t.Errorf("SetCheckbox(false) = %q, %v", got, changed)
+	}
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultEase is the SM-2 ease factor of a card that was never reviewed.
+const DefaultEase = 2.5
+
+const minEase = 1.3
+
+var listMarkerRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+`)
+
+// Flashcard is a question and answer found in a note body together with
+// its SM-2 review state. Due is a YYYY-MM-DD date; new cards are due at
+// once.
+type Flashcard struct {
+	ID           string
+	NoteID       string
+	NoteTitle    string
+	NoteSlug     string
+	Line         int
+	Front        string
+	Back         string
+	Reps         int
+	Interval     int
+	Ease         float64
+	Due          string
+	LastReviewed *time.Time
+}
+
+// IsNew reports whether the card was never reviewed.
+func (f *Flashcard) IsNew() bool {
+	return f.LastReviewed == nil
+}
+
+// IsDue reports whether the card should be reviewed on the day of now.
+func (f *Flashcard) IsDue(now time.Time) bool {
+	return f.Due <= now.Format("2006-01-02")
+}
+
+// Review applies a recall grade from 0 (blackout) to 5 (perfect) using
+// SM-2: grades below 3 restart the card and leave the ease factor alone,
+// others stretch the interval by the ease factor, which itself follows
+// the grade.
+func (f *Flashcard) Review(grade int, now time.Time) error {
+	if grade < 0 || grade > 5 {
+		return fmt.Errorf("invalid grade %d: use 0 (forgot) to 5 (perfect)", grade)
+	}
+	if f.Ease == 0 {
+		f.Ease = DefaultEase
+	}
+
+	if grade < 3 {
+		f.Reps = 0
+		f.Interval = 1
+	} else {
+		switch f.Reps {
+		case 0:
+			f.Interval = 1
+		case 1:
+			f.Interval = 6
+		default:
+			f.Interval = int(math.Round(float64(f.Interval) * f.Ease))
+		}
+		f.Reps++
+		q := float64(5 - grade)
+		f.Ease = max(minEase, f.Ease+0.1-q*(0.08+q*0.02))
+	}
+	f.Due = now.AddDate(0, 0, f.Interval).Format("2006-01-02")
+	f.LastReviewed = &now
+	return nil
+}
+
+// ParseFlashcards returns the flashcards of a note body: single lines
+// written as "Question :: Answer", and paragraphs split by a line holding
+// only "?", with the question above and the answer below. Front matter and
+// fenced code are skipped. IDs depend on the note and the question, so a
+// card keeps its schedule when its answer or position changes.
+func ParseFlashcards(noteID, body string) []Flashcard {
+	lines := strings.Split(body, "\n")
+	var cards []Flashcard
+	seen := make(map[string]int)
+	add := func(line int, front, back string) {
+		if front == "" || back == "" {
+			return
+		}
+		seen[front]++
+		cards = append(cards, Flashcard{
+			ID:     contentID(noteID, "flashcard\x00"+front, seen[front]),
+			NoteID: noteID,
+			Line:   line,
+			Front:  front,
+			Back:   back,
+		})
+	}
+
+	var para []string
+	start := 0
+	flush := func() {
+		defer func() { para = nil }()
+		for i, l := range para {
+			if strings.TrimSpace(l) == "?" {
+				add(start, strings.TrimSpace(strings.Join(para[:i], "\n")), strings.TrimSpace(strings.Join(para[i+1:], "\n")))
+				return
+			}
+		}
+		for i, l := range para {
+			if front, back, ok := strings.Cut(l, " :: "); ok {
+				add(start+i, strings.TrimSpace(listMarkerRe.ReplaceAllString(front, "")), strings.TrimSpace(back))
+			}
+		}
+	}
+
+	inFence := false
+	for i := frontMatterLines(lines); i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		if isFence(line) {
+			flush()
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if para == nil {
+			start = i
+		}
+		_, line, _ = ParseBlockID(line)
+		para = append(para, line)
+	}
+	flush()
+	return cards
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFlashcards(t *testing.T) {
+	body := "---\nq :: not a card\n---\n# Go\n\n- What is a goroutine? :: A lightweight thread\nCapital of France :: Paris ^fr\n\n" +
+		"What does SM-2 stand for?\n?\nSuperMemo 2\nalgorithm\n\n```\nx :: y\n```\nNo separator :: "
+	cards := ParseFlashcards("n1", body)
+	if len(cards) != 3 {
+		t.Fatalf("expected 3 cards, got %+v", cards)
+	}
+	if cards[0].Front != "What is a goroutine?" || cards[0].Back != "A lightweight thread" || cards[0].Line != 5 {
+		t.Errorf("unexpected card: %+v", cards[0])
+	}
+	if cards[1].Back != "Paris" {
+		t.Errorf("block ID should be stripped from the answer: %+v", cards[1])
+	}
+	if cards[2].Front != "What does SM-2 stand for?" || cards[2].Back != "SuperMemo 2\nalgorithm" || cards[2].Line != 8 {
+		t.Errorf("unexpected multi-line card: %+v", cards[2])
+	}
+
+	moved := ParseFlashcards("n1", "Intro\n\nCapital of France :: Paris, on the Seine")
+	if moved[0].ID != cards[1].ID {
+		t.Error("editing the answer or moving the card should keep its ID")
+	}
+}
+
+func TestFlashcardReview(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	f := &Flashcard{Due: "2026-10-01"}
+	if !f.IsNew() || !f.IsDue(now) {
+		t.Fatal("a fresh card should be new and due")
+	}
+
+	for _, want := range []int{1, 6, 16} {
+		if err := f.Review(5, now); err != nil {
+			t.Fatal(err)
+		}
+		if f.Interval != want {
+			t.Fatalf("interval = %d, want %d", f.Interval, want)
+		}
+	}
+	if f.Reps != 3 || f.Ease <= DefaultEase || f.Due != "2026-10-17" {
+		t.Errorf("unexpected state after three perfect reviews: %+v", f)
+	}
+
+	ease := f.Ease
+	f.Review(1, now)
+	if f.Reps != 0 || f.Interval != 1 || f.Due != "2026-10-02" {
+		t.Errorf("a failed recall should restart the card: %+v", f)
+	}
+	if f.Ease != ease {
+		t.Errorf("a failed recall should leave the ease at %v, got %v", ease, f.Ease)
+	}
+	for range 20 {
+		f.Review(3, now)
+	}
+	if f.Ease != minEase {
+		t.Errorf("ease should bottom out at %v, got %v", minEase, f.Ease)
+	}
+	if err := f.Review(6, now); err == nil {
+		t.Error("expected error for grade 6")
+	}
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MaxEmbedDepth is how many levels of ![[note]] embeds are expanded
+// inside one another, in the TUI and in published posts.
+const MaxEmbedDepth = 3
+
+var (
+	headingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	blockIDRe  = regexp.MustCompile(`(^|\s)\^([A-Za-z0-9-]+)\s*$`)
+	listItemRe = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s`)
+)
+
+func HeadingAnchor(heading string) string {
+	return Slugify(heading)
+}
+
+func BlockAnchor(id string) string {
+	return "block-" + strings.ToLower(id)
+}
+
+func ParseHeading(line string) (level int, text string, ok bool) {
+	m := headingRe.FindStringSubmatch(line)
+	if m == nil {
+		return 0, "", false
+	}
+	return len(m[1]), m[2], true
+}
+
+func ParseBlockID(line string) (id, rest string, ok bool) {
+	loc := blockIDRe.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return "", line, false
+	}
+	return line[loc[4]:loc[5]], strings.TrimRight(line[:loc[0]], " \t"), true
+}
+
+func ExtractSection(body, heading string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	want := HeadingAnchor(heading)
+	start, level := -1, 0
+	inFence := false
+	for i, line := range lines {
+		if isFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		lvl, text, ok := ParseHeading(line)
+		if !ok {
+			continue
+		}
+		if start == -1 {
+			if strings.EqualFold(text, heading) || HeadingAnchor(text) == want {
+				start, level = i, lvl
+			}
+			continue
+		}
+		if lvl <= level {
+			return strings.TrimRight(strings.Join(lines[start:i], "\n"), "\n"), true
+		}
+	}
+	if start == -1 {
+		return "", false
+	}
+	return strings.TrimRight(strings.Join(lines[start:], "\n"), "\n"), true
+}
+
+// ExtractBlock returns the block carrying the ^id marker, with the marker
+// stripped. A list item is its own block; otherwise the block is the
+// paragraph ending on the marked line.
+func ExtractBlock(body, id string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		found, rest, ok := ParseBlockID(line)
+		if !ok || !strings.EqualFold(found, id) {
+			continue
+		}
+		if listItemRe.MatchString(line) {
+			return rest, true
+		}
+		start := i
+		for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+			start--
+		}
+		block := append(append([]string{}, lines[start:i]...), rest)
+		return strings.Join(block, "\n"), true
+	}
+	return "", false
+}
+
+func ExtractFragment(body string, link ParsedLink) (string, bool) {
+	switch {
+	case link.BlockID != "":
+		return ExtractBlock(body, link.BlockID)
+	case link.Heading != "":
+		return ExtractSection(body, link.Heading)
+	default:
+		return body, true
+	}
+}
+
+func StripBlockIDs(body string) string {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if _, rest, ok := ParseBlockID(line); ok {
+			lines[i] = rest
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"testing"
+)
+
+const fragmentBody = `# Design
+
+Intro paragraph.
+
+## Storage
+
+We use SQLite.
+It is embedded. ^db-choice
+
+### Migrations
+
+Versioned.
+
+## API
+
+- first item
+- second item ^item2
+
+` + "```" + `
+## Not A Heading
+` + "```"
+
+func TestExtractSection(t *testing.T) {
+	got, ok := ExtractSection(fragmentBody, "storage")
+	if !ok {
+		t.Fatal("expected section to be found")
+	}
+	want := "## Storage\n\nWe use SQLite.\nIt is embedded. ^db-choice\n\n### Migrations\n\nVersioned."
+	if got != want {
+		t.Errorf("ExtractSection() = %q, want %q", got, want)
+	}
+}
+
+func TestExtractSectionByAnchor(t *testing.T) {
+	if _, ok := ExtractSection(fragmentBody, "Migrations"); !ok {
+		t.Error("expected Migrations section to be found")
+	}
+	if _, ok := ExtractSection(fragmentBody, "Not A Heading"); ok {
+		t.Error("headings inside code fences should be ignored")
+	}
+}
+
+func TestExtractBlockParagraph(t *testing.T) {
+	got, ok := ExtractBlock(fragmentBody, "db-choice")
+	if !ok {
+		t.Fatal("expected block to be found")
+	}
+	want := "We use SQLite.\nIt is embedded."
+	if got != want {
+		t.Errorf("ExtractBlock() = %q, want %q", got, want)
+	}
+}
+
+func TestExtractBlockListItem(t *testing.T) {
+	got, ok := ExtractBlock(fragmentBody, "item2")
+	if !ok {
+		t.Fatal("expected block to be found")
+	}
+	if got != "- second item" {
+		t.Errorf("ExtractBlock() = %q, want '- second item'", got)
+	}
+}
+
+func TestExtractBlockMissing(t *testing.T) {
+	if _, ok := ExtractBlock(fragmentBody, "nope"); ok {
+		t.Error("expected missing block to be reported")
+	}
+}
+
+func TestExtractFragmentWholeNote(t *testing.T) {
+	got, ok := ExtractFragment("body", ParsedLink{TargetRef: "x"})
+	if !ok || got != "body" {
+		t.Errorf("ExtractFragment() = %q, %v", got, ok)
+	}
+}
+
+func TestStripBlockIDs(t *testing.T) {
+	got := StripBlockIDs("one ^a1\ntwo\nthree ^b-2")
+	want := "one\ntwo\nthree"
+	if got != want {
+		t.Errorf("StripBlockIDs() = %q, want %q", got, want)
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	if got := HeadingAnchor("Storage Layer!"); got != "storage-layer" {
+		t.Errorf("HeadingAnchor() = %q", got)
+	}
+	if got := BlockAnchor("Abc123"); got != "block-abc123" {
+		t.Errorf("BlockAnchor() = %q", got)
+	}
+}
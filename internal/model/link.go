@@ -12,6 +12,8 @@ type Link struct {
 	SourceID   string
 	TargetType string
 	TargetID   string
+	Fragment   string
+	Relation   string
 	Context    string
 	CreatedAt  time.Time
 }
@@ -19,11 +21,44 @@ type Link struct {
 type ParsedLink struct {
 	TargetType string
 	TargetRef  string
+	Heading    string
+	BlockID    string
 	Display    string
+	HasDisplay bool
+	Relation   string
 	Context    string
+	Embed      bool
 }
 
-var wikilinkRe = regexp.MustCompile(`\[\[([^\]]+)\]\]`)
+// Fragment returns the section the link points at, encoded as "#Heading"
+// or "^blockid", or "" when the link targets the whole note.
+func (p ParsedLink) Fragment() string {
+	switch {
+	case p.BlockID != "":
+		return "^" + p.BlockID
+	case p.Heading != "":
+		return "#" + p.Heading
+	default:
+		return ""
+	}
+}
+
+var wikilinkRe = regexp.MustCompile(`(!?)\[\[([^\]]+)\]\]`)
+
+var (
+	// relationPrefixRe matches a relation written inside a link, as in
+	// [[implements>card:abcd]] or [[part of>roadmap]].
+	relationPrefixRe = regexp.MustCompile(`^\s*([A-Za-z][\w-]*(?: [\w-]+)*)>`)
+	// relationFieldRe matches a relation field starting a line, as in
+	// "supersedes:: [[adr-3]]", optionally in a list item.
+	relationFieldRe = regexp.MustCompile(`^\s*(?:(?:[-*+]|\d+[.)])\s+)?([A-Za-z][\w-]*(?:[ \t]+[\w-]+)*)::\s`)
+)
+
+// NormalizeRelation lowercases a relation name and joins its words with
+// hyphens, so "Part of" becomes "part-of".
+func NormalizeRelation(relation string) string {
+	return strings.ToLower(strings.Join(strings.Fields(relation), "-"))
+}
 
 func ParseWikilinks(text string) []ParsedLink {
 	matches := wikilinkRe.FindAllStringSubmatchIndex(text, -1)
@@ -33,27 +68,8 @@ func ParseWikilinks(text string) []ParsedLink {
 
 	var links []ParsedLink
 	for _, match := range matches {
-		inner := text[match[2]:match[3]]
-
-		ref := inner
-		display := inner
-		if idx := strings.Index(inner, "|"); idx != -1 {
-			ref = inner[:idx]
-			display = inner[idx+1:]
-		}
-
-		targetType := "note"
-		if strings.HasPrefix(ref, "card:") {
-			targetType = "card"
-			ref = ref[5:]
-		} else if strings.HasPrefix(ref, "board:") {
-			targetType = "board"
-			ref = ref[6:]
-		}
-
-		if display == inner && strings.Contains(inner, ":") {
-			display = ref
-		}
+		link := parseWikilink(text[match[4]:match[5]])
+		link.Embed = match[3] > match[2]
 
 		lineStart := strings.LastIndex(text[:match[0]], "\n") + 1
 		lineEnd := strings.Index(text[match[1]:], "\n")
@@ -62,19 +78,96 @@ func ParseWikilinks(text string) []ParsedLink {
 		} else {
 			lineEnd += match[1]
 		}
-		context := text[lineStart:lineEnd]
+		link.Context = text[lineStart:lineEnd]
+		if link.Relation == "" {
+			if m := relationFieldRe.FindStringSubmatchIndex(text[lineStart:match[0]]); m != nil {
+				link.Relation = NormalizeRelation(text[lineStart+m[2] : lineStart+m[3]])
+			}
+		}
 
-		links = append(links, ParsedLink{
-			TargetType: targetType,
-			TargetRef:  ref,
-			Display:    display,
-			Context:    context,
-		})
+		links = append(links, link)
 	}
 
 	return links
 }
 
+func ReplaceWikilinks(text string, fn func(link ParsedLink, raw string) string) string {
+	return wikilinkRe.ReplaceAllStringFunc(text, func(match string) string {
+		groups := wikilinkRe.FindStringSubmatch(match)
+		link := parseWikilink(groups[2])
+		link.Embed = groups[1] == "!"
+		return fn(link, match)
+	})
+}
+
+func parseWikilink(inner string) ParsedLink {
+	relation := ""
+	if m := relationPrefixRe.FindStringSubmatch(inner); m != nil {
+		relation = NormalizeRelation(m[1])
+		inner = inner[len(m[0]):]
+	}
+
+	ref := inner
+	display := inner
+	hasDisplay := false
+	if idx := strings.Index(inner, "|"); idx != -1 {
+		// Inside tables the separator is escaped as "\|".
+		ref = strings.TrimSuffix(inner[:idx], "\\")
+		display = inner[idx+1:]
+		hasDisplay = true
+	}
+
+	targetType := "note"
+	if strings.HasPrefix(ref, "card:") {
+		targetType = "card"
+		ref = ref[5:]
+	} else if strings.HasPrefix(ref, "board:") {
+		targetType = "board"
+		ref = ref[6:]
+	}
+	if targetType == "note" && IsAttachmentRef(strings.TrimSpace(ref)) {
+		targetType = "attachment"
+		ref = strings.TrimSpace(ref)
+	}
+
+	var heading, blockID string
+	if targetType == "note" {
+		if idx := strings.Index(ref, "#"); idx != -1 {
+			heading = strings.TrimSpace(ref[idx+1:])
+			ref = ref[:idx]
+			if strings.HasPrefix(heading, "^") {
+				blockID = heading[1:]
+				heading = ""
+			}
+		} else if idx := strings.Index(ref, "^"); idx != -1 {
+			blockID = strings.TrimSpace(ref[idx+1:])
+			ref = ref[:idx]
+		}
+		ref = strings.TrimSpace(ref)
+	}
+
+	if !hasDisplay {
+		switch {
+		case heading != "" && ref != "":
+			display = ref + " > " + heading
+		case heading != "":
+			display = heading
+		case blockID != "" || strings.Contains(inner, ":"):
+			display = ref
+		}
+	}
+
+	return ParsedLink{
+		TargetType: targetType,
+		TargetRef:  ref,
+		Heading:    heading,
+		BlockID:    blockID,
+		Display:    display,
+		HasDisplay: hasDisplay,
+		Relation:   relation,
+	}
+}
+
 var markdownLinkRe = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
 
 func ExtractMarkdownLinks(text string) []ParsedLink {
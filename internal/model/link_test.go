@@ -58,6 +58,95 @@ func TestParseWikilinks(t *testing.T) {
 				{TargetType: "card", TargetRef: "abc12345", Display: "Login Bug"},
 			},
 		},
+		{
+			name:  "escaped separator in a table",
+			input: "| [[my-note\\|My Note]] | x |",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "my-note", Display: "My Note"},
+			},
+		},
+		{
+			name:  "heading link",
+			input: "See [[design#Storage Layer]]",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "design", Heading: "Storage Layer", Display: "design > Storage Layer"},
+			},
+		},
+		{
+			name:  "block link",
+			input: "See [[design^abc123]]",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "design", BlockID: "abc123", Display: "design"},
+			},
+		},
+		{
+			name:  "block link via heading syntax",
+			input: "See [[design#^abc123|the decision]]",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "design", BlockID: "abc123", Display: "the decision"},
+			},
+		},
+		{
+			name:  "embed",
+			input: "![[design]]",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "design", Display: "design", Embed: true},
+			},
+		},
+		{
+			name:  "embed heading",
+			input: "![[design#Storage]]",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "design", Heading: "Storage", Display: "design > Storage", Embed: true},
+			},
+		},
+		{
+			name:  "same-note heading",
+			input: "Jump to [[#Summary]]",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "", Heading: "Summary", Display: "Summary"},
+			},
+		},
+		{
+			name:  "attachment embed",
+			input: "Diagram: ![[Architecture.PNG]] and [[report.pdf|the report]]",
+			want: []ParsedLink{
+				{TargetType: "attachment", TargetRef: "Architecture.PNG", Display: "Architecture.PNG", Embed: true},
+				{TargetType: "attachment", TargetRef: "report.pdf", Display: "the report"},
+			},
+		},
+		{
+			name:  "dotted note ref",
+			input: "See [[Node.js]]",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "Node.js", Display: "Node.js"},
+			},
+		},
+		{
+			name:  "relation prefix",
+			input: "Done in [[implements>card:abcd]] and [[Part-Of>roadmap|the roadmap]]",
+			want: []ParsedLink{
+				{TargetType: "card", TargetRef: "abcd", Display: "abcd", Relation: "implements"},
+				{TargetType: "note", TargetRef: "roadmap", Display: "the roadmap", Relation: "part-of"},
+			},
+		},
+		{
+			name:  "relation field",
+			input: "- supersedes:: [[adr-3]], [[contradicts>adr-1]]\nSee [[adr-2]] supersedes:: no",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "adr-3", Display: "adr-3", Relation: "supersedes"},
+				{TargetType: "note", TargetRef: "adr-1", Display: "adr-1", Relation: "contradicts"},
+				{TargetType: "note", TargetRef: "adr-2", Display: "adr-2"},
+			},
+		},
+		{
+			name:  "multi-word relations",
+			input: "Part  of:: [[roadmap]]\nSee [[depends on>card:abcd]]",
+			want: []ParsedLink{
+				{TargetType: "note", TargetRef: "roadmap", Display: "roadmap", Relation: "part-of"},
+				{TargetType: "card", TargetRef: "abcd", Display: "abcd", Relation: "depends-on"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -76,11 +165,46 @@ func TestParseWikilinks(t *testing.T) {
 				if link.Display != tt.want[i].Display {
 					t.Errorf("link[%d].Display = %q, want %q", i, link.Display, tt.want[i].Display)
 				}
+				if link.Heading != tt.want[i].Heading {
+					t.Errorf("link[%d].Heading = %q, want %q", i, link.Heading, tt.want[i].Heading)
+				}
+				if link.BlockID != tt.want[i].BlockID {
+					t.Errorf("link[%d].BlockID = %q, want %q", i, link.BlockID, tt.want[i].BlockID)
+				}
+				if link.Embed != tt.want[i].Embed {
+					t.Errorf("link[%d].Embed = %v, want %v", i, link.Embed, tt.want[i].Embed)
+				}
+				if link.Relation != tt.want[i].Relation {
+					t.Errorf("link[%d].Relation = %q, want %q", i, link.Relation, tt.want[i].Relation)
+				}
 			}
 		})
 	}
 }
 
+func TestParsedLinkFragment(t *testing.T) {
+	links := ParseWikilinks("[[a#Intro]] [[b^x1]] [[c]]")
+	want := []string{"#Intro", "^x1", ""}
+	for i, w := range want {
+		if got := links[i].Fragment(); got != w {
+			t.Errorf("links[%d].Fragment() = %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestReplaceWikilinks(t *testing.T) {
+	got := ReplaceWikilinks("a [[x]] b ![[y#H]] c", func(link ParsedLink, raw string) string {
+		if link.Embed {
+			return "<" + link.TargetRef + link.Fragment() + ">"
+		}
+		return raw
+	})
+	want := "a [[x]] b <y#H> c"
+	if got != want {
+		t.Errorf("ReplaceWikilinks() = %q, want %q", got, want)
+	}
+}
+
 func TestExtractMarkdownLinks(t *testing.T) {
 	input := "Visit [example](https://example.com) and [docs](https://docs.go.dev)"
 	got := ExtractMarkdownLinks(input)
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Mention is a plain-text occurrence of a name, such as a note title, in
+// a text.
+type Mention struct {
+	Start   int // byte offsets of the occurrence in the text
+	End     int
+	Line    int // 1-based
+	Text    string
+	Context string
+}
+
+// mentionSkipRe matches the parts of a line that must not be linked:
+// wikilinks, inline code, URLs, #tags and Markdown links.
+var mentionSkipRe = regexp.MustCompile("!?\\[\\[[^\\]]+\\]\\]|`[^`]*`|https?://\\S+|#[\\w/-]+|\\[[^\\]]*\\]\\([^)]*\\)")
+
+// FindMentions finds the case-insensitive, whole-word occurrences of names
+// in text that are not already inside a wikilink, code, a URL, a tag, a
+// Markdown link or the front matter. Where names overlap the longest one
+// wins.
+func FindMentions(text string, names []string) []Mention {
+	re := mentionPattern(names)
+	if re == nil {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	skip := frontMatterLines(strings.Split(text, "\n"))
+	var mentions []Mention
+	offset := 0
+	inFence := false
+	for i, raw := range lines {
+		start := offset
+		offset += len(raw)
+		if i < skip {
+			continue
+		}
+		if isFence(raw) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		line := strings.TrimRight(raw, "\r\n")
+		if _, rest, ok := ParseBlockID(line); ok {
+			line = rest
+		}
+		blocked := mentionSkipRe.FindAllStringIndex(line, -1)
+		for pos := 0; pos < len(line); {
+			m := re.FindStringIndex(line[pos:])
+			if m == nil {
+				break
+			}
+			from, to := pos+m[0], pos+m[1]
+			if !wordBoundary(line, from, to) || overlapsAny(blocked, from, to) {
+				_, size := utf8.DecodeRuneInString(line[from:])
+				pos = from + size
+				continue
+			}
+			mentions = append(mentions, Mention{
+				Start:   start + from,
+				End:     start + to,
+				Line:    i + 1,
+				Text:    line[from:to],
+				Context: strings.TrimRight(raw, "\r\n"),
+			})
+			pos = to
+		}
+	}
+	return mentions
+}
+
+func mentionPattern(names []string) *regexp.Regexp {
+	var alts []string
+	seen := make(map[string]bool)
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" || seen[strings.ToLower(n)] {
+			continue
+		}
+		seen[strings.ToLower(n)] = true
+		alts = append(alts, n)
+	}
+	if len(alts) == 0 {
+		return nil
+	}
+	sort.SliceStable(alts, func(i, j int) bool { return len(alts[i]) > len(alts[j]) })
+	for i, n := range alts {
+		alts[i] = regexp.QuoteMeta(n)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(alts, "|"))
+}
+
+// wordBoundary reports whether text[from:to] does not start or end in the
+// middle of a word.
+func wordBoundary(text string, from, to int) bool {
+	first, _ := utf8.DecodeRuneInString(text[from:])
+	last, _ := utf8.DecodeLastRuneInString(text[:to])
+	if before, _ := utf8.DecodeLastRuneInString(text[:from]); from > 0 && isWordRune(first) && isWordRune(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(text[to:]); to < len(text) && isWordRune(last) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func overlapsAny(ranges [][]int, from, to int) bool {
+	for _, r := range ranges {
+		if from < r[1] && to > r[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkMentions replaces mentions found in text with [[ref|text]] links,
+// keeping the text as written. Inside table rows the separator is escaped
+// as "\|".
+func LinkMentions(text, ref string, mentions []Mention) string {
+	sorted := append([]Mention(nil), mentions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start > sorted[j].Start })
+	for _, m := range sorted {
+		sep := "|"
+		if strings.HasPrefix(strings.TrimSpace(m.Context), "|") {
+			sep = `\|`
+		}
+		text = text[:m.Start] + "[[" + ref + sep + m.Text + "]]" + text[m.End:]
+	}
+	return text
+}
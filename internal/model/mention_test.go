@@ -0,0 +1,64 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindMentions(t *testing.T) {
+	body := "---\ntitle: Query Planning\n---\n" +
+		"Read about query planning first. ^intro\n" +
+		"Already linked: [[query-planning|Query Planning]] and `query planning`.\n" +
+		"```\nquery planning\n```\n" +
+		"Subquery planning is different. See QP, then https://example.com/query-planning.\n" +
+		"| topic | Query Planning |"
+
+	got := FindMentions(body, []string{"Query Planning", "QP", ""})
+	want := []struct {
+		text string
+		line int
+	}{
+		{"query planning", 4},
+		{"QP", 9},
+		{"Query Planning", 10},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FindMentions = %+v, want %d mentions", got, len(want))
+	}
+	for i, w := range want {
+		m := got[i]
+		if m.Text != w.text || m.Line != w.line || body[m.Start:m.End] != w.text {
+			t.Errorf("mention %d = %+v, want %q on line %d", i, m, w.text, w.line)
+		}
+	}
+	if got[0].Context != "Read about query planning first. ^intro" {
+		t.Errorf("context = %q", got[0].Context)
+	}
+}
+
+func TestFindMentionsPrefersLongestName(t *testing.T) {
+	got := FindMentions("Design docs and design.", []string{"Design", "Design docs"})
+	if len(got) != 2 || got[0].Text != "Design docs" || got[1].Text != "design" {
+		t.Errorf("FindMentions = %+v", got)
+	}
+}
+
+func TestFindMentionsSkipsTagsAndLinks(t *testing.T) {
+	text := "Tagged #alpha and #team/alpha, see [Alpha](https://example.com) or [docs](alpha.md). Alpha."
+	got := FindMentions(text, []string{"Alpha"})
+	if len(got) != 1 || got[0].Start != strings.LastIndex(text, "Alpha") {
+		t.Errorf("FindMentions = %+v, want only the last Alpha", got)
+	}
+}
+
+func TestLinkMentions(t *testing.T) {
+	body := "Query planning matters.\n| a | query planning |"
+	got := LinkMentions(body, "query-planning", FindMentions(body, []string{"Query Planning"}))
+	want := "[[query-planning|Query planning]] matters.\n| a | [[query-planning\\|query planning]] |"
+	if got != want {
+		t.Errorf("LinkMentions = %q, want %q", got, want)
+	}
+	if len(FindMentions(got, []string{"Query Planning"})) != 0 {
+		t.Error("linked mentions should not be found again")
+	}
+}
@@ -16,6 +16,7 @@ type Note struct {
 	Pinned      bool
 	WorkspaceID string
 	ArchivedAt  *time.Time
+	DeletedAt   *time.Time
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -77,3 +78,16 @@ func ValidateNoteSlug(slug string) error {
 	}
 	return nil
 }
+
+func ValidateNoteAlias(alias string) error {
+	if strings.TrimSpace(alias) == "" {
+		return fmt.Errorf("note alias cannot be empty")
+	}
+	if len(alias) > 200 {
+		return fmt.Errorf("note alias cannot exceed 200 characters")
+	}
+	if strings.ContainsAny(alias, "[]|#^") {
+		return fmt.Errorf("note alias cannot contain [ ] | # or ^")
+	}
+	return nil
+}
@@ -57,6 +57,20 @@ func TestValidateNoteSlug(t *testing.T) {
 	}
 }
 
+func TestValidateNoteAlias(t *testing.T) {
+	if err := ValidateNoteAlias("ADR-7"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateNoteAlias("Use SQLite for storage"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, bad := range []string{"", "  ", "a|b", "a#b", "[[x]]", "x^1"} {
+		if err := ValidateNoteAlias(bad); err == nil {
+			t.Errorf("expected error for alias %q", bad)
+		}
+	}
+}
+
 func TestNoteTagList(t *testing.T) {
 	n := &Note{Tags: "go,pkm,tools"}
 	tags := n.TagList()
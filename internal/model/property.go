@@ -0,0 +1,178 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PropertyType string
+
+const (
+	PropertyString PropertyType = "string"
+	PropertyNumber PropertyType = "number"
+	PropertyDate   PropertyType = "date"
+	PropertyList   PropertyType = "list"
+)
+
+const propertyDateLayout = "2006-01-02"
+
+type Property struct {
+	Key   string
+	Type  PropertyType
+	Value string
+	Items []string
+}
+
+// String renders the property value the way it reads in front matter.
+func (p Property) String() string {
+	if p.Type == PropertyList {
+		return strings.Join(p.Items, ", ")
+	}
+	return p.Value
+}
+
+// SplitFrontMatter separates a leading front matter block, delimited by
+// "---" lines, from the rest of body.
+func SplitFrontMatter(body string) (frontMatter, rest string, ok bool) {
+	normalized := strings.ReplaceAll(body, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return "", body, false
+	}
+	lines := strings.Split(normalized, "\n")
+	for i := 1; i < len(lines); i++ {
+		if line := strings.TrimRight(lines[i], " \t"); line == "---" || line == "..." {
+			return strings.Join(lines[1:i], "\n"), strings.TrimLeft(strings.Join(lines[i+1:], "\n"), "\n"), true
+		}
+	}
+	return "", body, false
+}
+
+// frontMatterLines returns how many leading lines form a front matter block.
+func frontMatterLines(lines []string) int {
+	if len(lines) == 0 || strings.TrimRight(lines[0], "\r") != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if l := strings.TrimRight(lines[i], " \t\r"); l == "---" || l == "..." {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func StripFrontMatter(body string) string {
+	_, rest, _ := SplitFrontMatter(body)
+	return rest
+}
+
+// ParseFrontMatter reads the simple YAML subset notes use for properties:
+// "key: value" pairs, inline lists ([a, b]) and block lists ("- item").
+// Nested maps and multi-line strings are not supported and are skipped.
+func ParseFrontMatter(body string) []Property {
+	fm, _, ok := SplitFrontMatter(body)
+	if !ok {
+		return nil
+	}
+
+	var props []Property
+	var list *Property
+	for _, line := range strings.Split(fm, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if list != nil {
+				if item := unquote(strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))); item != "" {
+					list.Items = append(list.Items, item)
+				}
+			}
+			continue
+		}
+		if list != nil && len(list.Items) > 0 {
+			props = append(props, *list)
+		}
+		list = nil
+		if line != strings.TrimLeft(line, " \t") {
+			continue
+		}
+
+		key, value, found := strings.Cut(trimmed, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			list = &Property{Key: key, Type: PropertyList}
+			continue
+		}
+		props = append(props, parsePropertyValue(key, value))
+	}
+	if list != nil && len(list.Items) > 0 {
+		props = append(props, *list)
+	}
+	return props
+}
+
+func parsePropertyValue(key, value string) Property {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		p := Property{Key: key, Type: PropertyList}
+		for _, item := range strings.Split(value[1:len(value)-1], ",") {
+			if item = unquote(strings.TrimSpace(item)); item != "" {
+				p.Items = append(p.Items, item)
+			}
+		}
+		return p
+	}
+	if unquoted := unquote(value); unquoted != value {
+		return Property{Key: key, Type: PropertyString, Value: unquoted}
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return Property{Key: key, Type: PropertyNumber, Value: value}
+	}
+	if _, err := time.Parse(propertyDateLayout, value); err == nil {
+		return Property{Key: key, Type: PropertyDate, Value: value}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return Property{Key: key, Type: PropertyDate, Value: t.Format(propertyDateLayout)}
+	}
+	return Property{Key: key, Type: PropertyString, Value: value}
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+type PropertyFilter struct {
+	Key   string
+	Op    string
+	Value string
+}
+
+var filterOps = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+// ParsePropertyFilter parses expressions such as "status=draft" or
+// "due<2026-12-01".
+func ParsePropertyFilter(expr string) (PropertyFilter, error) {
+	idx := strings.IndexAny(expr, "!=<>")
+	if idx <= 0 {
+		return PropertyFilter{}, fmt.Errorf("invalid filter %q: use key=value, key!=value, key<value, key<=value, key>value or key>=value", expr)
+	}
+	for _, op := range filterOps {
+		if strings.HasPrefix(expr[idx:], op) {
+			key := strings.TrimSpace(expr[:idx])
+			value := unquote(strings.TrimSpace(expr[idx+len(op):]))
+			if key == "" {
+				break
+			}
+			return PropertyFilter{Key: key, Op: op, Value: value}, nil
+		}
+	}
+	return PropertyFilter{}, fmt.Errorf("invalid filter %q: use key=value, key!=value, key<value, key<=value, key>value or key>=value", expr)
+}
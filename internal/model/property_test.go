@@ -0,0 +1,82 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	body := `---
+status: draft
+priority: 2
+due: 2026-12-01
+title: "2026"
+tags: [go, "sqlite"]
+owners:
+  - ana
+  - ben
+empty:
+nested:
+  key: skipped
+# comment
+---
+# Heading
+
+Body text.`
+
+	got := ParseFrontMatter(body)
+	want := []Property{
+		{Key: "status", Type: PropertyString, Value: "draft"},
+		{Key: "priority", Type: PropertyNumber, Value: "2"},
+		{Key: "due", Type: PropertyDate, Value: "2026-12-01"},
+		{Key: "title", Type: PropertyString, Value: "2026"},
+		{Key: "tags", Type: PropertyList, Items: []string{"go", "sqlite"}},
+		{Key: "owners", Type: PropertyList, Items: []string{"ana", "ben"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFrontMatter() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if rest := StripFrontMatter(body); rest != "# Heading\n\nBody text." {
+		t.Errorf("StripFrontMatter() = %q", rest)
+	}
+}
+
+func TestParseFrontMatterMissing(t *testing.T) {
+	for _, body := range []string{"", "no front matter", "---\nstatus: draft\nno closing line", "text\n---\na: b\n---"} {
+		if props := ParseFrontMatter(body); props != nil {
+			t.Errorf("ParseFrontMatter(%q) = %v, want nil", body, props)
+		}
+		if StripFrontMatter(body) != body {
+			t.Errorf("StripFrontMatter(%q) should leave body unchanged", body)
+		}
+	}
+}
+
+func TestParsePropertyFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want PropertyFilter
+	}{
+		{"status=draft", PropertyFilter{"status", "=", "draft"}},
+		{"due<2026-12-01", PropertyFilter{"due", "<", "2026-12-01"}},
+		{"priority >= 2", PropertyFilter{"priority", ">=", "2"}},
+		{"status!='in review'", PropertyFilter{"status", "!=", "in review"}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePropertyFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParsePropertyFilter(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePropertyFilter(%q) = %+v, want %+v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"status", "=draft", "a!b"} {
+		if _, err := ParsePropertyFilter(bad); err == nil {
+			t.Errorf("ParsePropertyFilter(%q) expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	inlineTagRe = regexp.MustCompile(`(^|[\s(,;])#([\p{L}\p{N}_-]+(?:/[\p{L}\p{N}_-]+)*)`)
+	validTagRe  = regexp.MustCompile(`^[\p{L}\p{N}_-]+(?:/[\p{L}\p{N}_-]+)*$`)
+	codeSpanRe  = regexp.MustCompile("`[^`]*`")
+)
+
+// NormalizeTag lowercases a tag and trims a leading "#" and stray slashes.
+func NormalizeTag(tag string) string {
+	tag = strings.TrimSpace(tag)
+	tag = strings.TrimPrefix(tag, "#")
+	return strings.ToLower(strings.Trim(tag, "/"))
+}
+
+func ValidateTag(tag string) error {
+	if !validTagRe.MatchString(tag) {
+		return fmt.Errorf("invalid tag %q: use letters, digits, - and _, with / for nesting", tag)
+	}
+	return nil
+}
+
+// TagMatches reports whether tag is filter itself or nested below it, so
+// "area" matches "area/backend/db".
+func TagMatches(tag, filter string) bool {
+	tag, filter = NormalizeTag(tag), NormalizeTag(filter)
+	return tag == filter || strings.HasPrefix(tag, filter+"/")
+}
+
+// NoteTags returns the normalized, de-duplicated tags of a note: the tags
+// column plus inline #tags in the body.
+func NoteTags(n *Note) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	add := func(t string) {
+		t = NormalizeTag(t)
+		if t != "" && !seen[t] && ValidateTag(t) == nil {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	for _, t := range n.TagList() {
+		add(t)
+	}
+	for _, t := range ParseInlineTags(n.Body) {
+		add(t)
+	}
+	return tags
+}
+
+// ParseInlineTags finds #tags in body, ignoring headings, front matter,
+// code blocks and code spans. A tag needs at least one letter, so issue
+// references such as #42 are not tags.
+func ParseInlineTags(body string) []string {
+	var tags []string
+	forEachInlineTag(strings.Split(body, "\n"), func(i, start, end int, line string) {
+		tags = append(tags, line[start:end])
+	})
+	return tags
+}
+
+// RenameInlineTag rewrites #old, and tags nested below it, to #new.
+func RenameInlineTag(body, old, new string) string {
+	lines := strings.Split(body, "\n")
+	var b strings.Builder
+	current, last := -1, 0
+	flush := func() {
+		if current >= 0 {
+			b.WriteString(lines[current][last:])
+			lines[current] = b.String()
+			b.Reset()
+		}
+	}
+	forEachInlineTag(lines, func(i, start, end int, line string) {
+		tag := line[start:end]
+		if !TagMatches(tag, old) {
+			return
+		}
+		if i != current {
+			flush()
+			current, last = i, 0
+		}
+		b.WriteString(line[last:start])
+		b.WriteString(new + tag[len(NormalizeTag(old)):])
+		last = end
+	})
+	flush()
+	return strings.Join(lines, "\n")
+}
+
+// forEachInlineTag calls fn with the line index and the byte range of each
+// tag name (without the "#"), skipping front matter and fenced code.
+func forEachInlineTag(lines []string, fn func(i, start, end int, line string)) {
+	first := frontMatterLines(lines)
+
+	inFence := false
+	for i := first; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		masked := codeSpanRe.ReplaceAllStringFunc(line, func(s string) string {
+			return strings.Repeat(" ", len(s))
+		})
+		for _, m := range inlineTagRe.FindAllStringSubmatchIndex(masked, -1) {
+			start, end := m[4], m[5]
+			if strings.IndexFunc(line[start:end], unicode.IsLetter) == -1 {
+				continue
+			}
+			fn(i, start, end, line)
+		}
+	}
+}
+
+type TagNode struct {
+	Name     string
+	Path     string
+	Count    int
+	Children []*TagNode
+}
+
+// BuildTagTree nests tags by "/" segments. Each node counts the distinct
+// notes tagged with it or any tag below it. noteTags maps note IDs to tags.
+func BuildTagTree(noteTags map[string][]string) []*TagNode {
+	nodes := make(map[string]*TagNode)
+	notes := make(map[string]map[string]bool)
+	var roots []*TagNode
+
+	for noteID, tags := range noteTags {
+		for _, tag := range tags {
+			parts := strings.Split(NormalizeTag(tag), "/")
+			for i := range parts {
+				path := strings.Join(parts[:i+1], "/")
+				node, ok := nodes[path]
+				if !ok {
+					node = &TagNode{Name: parts[i], Path: path}
+					nodes[path] = node
+					notes[path] = make(map[string]bool)
+					if i == 0 {
+						roots = append(roots, node)
+					} else {
+						parent := nodes[strings.Join(parts[:i], "/")]
+						parent.Children = append(parent.Children, node)
+					}
+				}
+				notes[path][noteID] = true
+			}
+		}
+	}
+
+	for path, node := range nodes {
+		node.Count = len(notes[path])
+		sort.Slice(node.Children, func(i, j int) bool { return node.Children[i].Name < node.Children[j].Name })
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].Name < roots[j].Name })
+	return roots
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInlineTags(t *testing.T) {
+	body := `---
+status: #notatag
+---
+# Heading
+
+Working on #Area/Backend/DB and #idea, see issue #42.
+Not a tag: foo#bar or ` + "`#code`" + `.
+(#paren) [[#heading]] x,#comma
+
+` + "```" + `
+#fenced
+` + "```"
+
+	got := ParseInlineTags(body)
+	want := []string{"Area/Backend/DB", "idea", "paren", "comma"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseInlineTags = %v, want %v", got, want)
+	}
+}
+
+func TestNoteTags(t *testing.T) {
+	n := &Note{Tags: "Go, area/backend", Body: "#go #area/backend/db #2024"}
+	got := NoteTags(n)
+	want := []string{"go", "area/backend", "area/backend/db"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NoteTags = %v, want %v", got, want)
+	}
+}
+
+func TestTagMatches(t *testing.T) {
+	tests := []struct {
+		tag, filter string
+		want        bool
+	}{
+		{"area", "area", true},
+		{"area/backend/db", "area", true},
+		{"Area/Backend", "#area/backend", true},
+		{"areas", "area", false},
+		{"area", "area/backend", false},
+	}
+	for _, tt := range tests {
+		if got := TagMatches(tt.tag, tt.filter); got != tt.want {
+			t.Errorf("TagMatches(%q, %q) = %v, want %v", tt.tag, tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestRenameInlineTag(t *testing.T) {
+	body := `---
+tags: keep
+---
+
+#area/backend and #area/backend/db, not #area/frontend or #areas.
+` + "`#area/backend`"
+
+	got := RenameInlineTag(body, "area/backend", "server")
+	want := `---
+tags: keep
+---
+
+#server and #server/db, not #area/frontend or #areas.
+` + "`#area/backend`"
+	if got != want {
+		t.Errorf("RenameInlineTag =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{"go", "area/backend/db", "my_tag-2"} {
+		if err := ValidateTag(tag); err != nil {
+			t.Errorf("ValidateTag(%q) = %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"", "two words", "a//b", "/a"} {
+		if err := ValidateTag(tag); err == nil {
+			t.Errorf("ValidateTag(%q) expected error", tag)
+		}
+	}
+}
+
+func TestBuildTagTree(t *testing.T) {
+	tree := BuildTagTree(map[string][]string{
+		"n1": {"area/backend/db", "area/backend"},
+		"n2": {"area/frontend"},
+		"n3": {"idea"},
+	})
+	if len(tree) != 2 || tree[0].Name != "area" || tree[1].Name != "idea" {
+		t.Fatalf("unexpected roots: %+v", tree)
+	}
+	area := tree[0]
+	if area.Count != 2 {
+		t.Errorf("area count = %d, want 2", area.Count)
+	}
+	if len(area.Children) != 2 || area.Children[0].Name != "backend" {
+		t.Fatalf("unexpected area children: %+v", area.Children)
+	}
+	backend := area.Children[0]
+	if backend.Count != 1 || backend.Path != "area/backend" {
+		t.Errorf("backend = %+v", backend)
+	}
+	if len(backend.Children) != 1 || backend.Children[0].Path != "area/backend/db" {
+		t.Errorf("unexpected backend children: %+v", backend.Children)
+	}
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	taskDueRe      = regexp.MustCompile(`(^|\s)@due\((\d{4}-\d{2}-\d{2})\)`)
+	taskPriorityRe = regexp.MustCompile(`(?i)(^|\s)!(low|medium|high|urgent)\b`)
+	spacesRe       = regexp.MustCompile(`\s{2,}`)
+)
+
+// Task is a checkbox found in a note body, with the optional @due(date) and
+// !priority metadata lifted out of its text. Context is the heading the
+// checkbox sits under.
+type Task struct {
+	ID        string
+	NoteID    string
+	NoteTitle string
+	NoteSlug  string
+	Line      int
+	Text      string
+	Checked   bool
+	Due       string
+	Priority  Priority
+	Context   string
+}
+
+// IsOverdue reports whether an open task was due before today.
+func (t *Task) IsOverdue(now time.Time) bool {
+	return !t.Checked && t.Due != "" && t.Due < now.Format("2006-01-02")
+}
+
+// ParseTasks returns the checkboxes of a note body as tasks. Task IDs are
+// derived from the note, the item text and its occurrence, so they survive
+// edits elsewhere in the note and toggling the checkbox itself.
+func ParseTasks(noteID, body string) []Task {
+	lines := strings.Split(body, "\n")
+	headings := make([]string, len(lines))
+	heading := ""
+	inFence := false
+	for i := frontMatterLines(lines); i < len(lines); i++ {
+		if isFence(lines[i]) {
+			inFence = !inFence
+		} else if !inFence {
+			if _, text, ok := ParseHeading(lines[i]); ok {
+				heading = text
+			}
+		}
+		headings[i] = heading
+	}
+
+	var tasks []Task
+	seen := make(map[string]int)
+	for _, box := range ParseCheckboxes(body) {
+		if box.Text == "" {
+			continue
+		}
+		text, due, priority := parseTaskMeta(box.Text)
+		seen[box.Text]++
+		tasks = append(tasks, Task{
+			ID:       contentID(noteID, box.Text, seen[box.Text]),
+			NoteID:   noteID,
+			Line:     box.Line,
+			Text:     text,
+			Checked:  box.Checked,
+			Due:      due,
+			Priority: priority,
+			Context:  headings[box.Line],
+		})
+	}
+	return tasks
+}
+
+// parseTaskMeta strips @due(YYYY-MM-DD) and !priority from a task's text.
+func parseTaskMeta(text string) (clean, due string, priority Priority) {
+	if m := taskDueRe.FindStringSubmatch(text); m != nil {
+		if _, err := time.Parse("2006-01-02", m[2]); err == nil {
+			due = m[2]
+			text = taskDueRe.ReplaceAllString(text, "$1")
+		}
+	}
+	if m := taskPriorityRe.FindStringSubmatch(text); m != nil {
+		priority, _ = ParsePriority(m[2])
+		text = taskPriorityRe.ReplaceAllString(text, "$1")
+	}
+	return strings.TrimSpace(spacesRe.ReplaceAllString(text, " ")), due, priority
+}
+
+func contentID(noteID, text string, n int) string {
+	h := sha1.New()
+	h.Write([]byte(noteID))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	h.Write([]byte{0, byte(n)})
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// ToggleCheckboxLine flips the checkbox on line i of body. It reports false
+// when that line holds no checkbox.
+func ToggleCheckboxLine(body string, line int) (string, bool) {
+	lines := strings.Split(body, "\n")
+	changed := false
+	forEachCheckbox(lines, func(i int, m []string) {
+		if i != line {
+			return
+		}
+		mark := "x"
+		if m[2] != " " {
+			mark = " "
+		}
+		lines[i] = m[1] + mark + m[3] + m[4]
+		changed = true
+	})
+	return strings.Join(lines, "\n"), changed
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTasks(t *testing.T) {
+	body := `---
+status: active
+---
+- [ ] Loose task
+# Launch
+
+## Prep
+- [ ] Write the post @due(2026-11-01) !high
+- [x] Book a room ^card-1a2b3c4d
+- [ ] Write the post @due(2026-13-40)
+` + "```" + `
+# not a heading
+` + "```" + `
+- [ ] Write the post @due(2026-11-01) !high`
+
+	tasks := ParseTasks("n1", body)
+	if len(tasks) != 5 {
+		t.Fatalf("got %d tasks, want 5: %+v", len(tasks), tasks)
+	}
+
+	if tasks[0].Text != "Loose task" || tasks[0].Context != "" {
+		t.Errorf("tasks[0] = %+v", tasks[0])
+	}
+	prep := tasks[1]
+	if prep.Text != "Write the post" || prep.Due != "2026-11-01" || prep.Priority != PriorityHigh ||
+		prep.Context != "Prep" || prep.Line != 7 || prep.Checked {
+		t.Errorf("tasks[1] = %+v", prep)
+	}
+	if !tasks[2].Checked || tasks[2].Text != "Book a room" {
+		t.Errorf("tasks[2] = %+v", tasks[2])
+	}
+	if tasks[3].Due != "" || tasks[3].Text != "Write the post @due(2026-13-40)" {
+		t.Errorf("invalid date should stay in the text: %+v", tasks[3])
+	}
+	if tasks[4].Context != "Prep" {
+		t.Errorf("heading inside a fence should be ignored, got context %q", tasks[4].Context)
+	}
+	if tasks[4].ID == prep.ID {
+		t.Error("repeated items should get distinct IDs")
+	}
+
+	again := ParseTasks("n1", "Intro\n\n"+body)
+	if again[1].ID != prep.ID {
+		t.Error("task ID should not depend on the line number")
+	}
+	if ParseTasks("n2", body)[1].ID == prep.ID {
+		t.Error("task ID should depend on the note")
+	}
+}
+
+func TestTaskIsOverdue(t *testing.T) {
+	now := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		task Task
+		want bool
+	}{
+		{Task{Due: "2026-11-01"}, true},
+		{Task{Due: "2026-11-02"}, false},
+		{Task{Due: "2026-11-01", Checked: true}, false},
+		{Task{}, false},
+	}
+	for _, c := range cases {
+		if got := c.task.IsOverdue(now); got != c.want {
+			t.Errorf("IsOverdue(%+v) = %v, want %v", c.task, got, c.want)
+		}
+	}
+}
+
+func TestToggleCheckboxLine(t *testing.T) {
+	body := "# Todo\n- [ ] One\n  * [X] Two ^blk"
+
+	got, ok := ToggleCheckboxLine(body, 1)
+	if !ok || got != "# Todo\n- [x] One\n  * [X] Two ^blk" {
+		t.Errorf("toggle line 1 = %q, %v", got, ok)
+	}
+	got, ok = ToggleCheckboxLine(body, 2)
+	if !ok || got != "# Todo\n- [ ] One\n  * [ ] Two ^blk" {
+		t.Errorf("toggle line 2 = %q, %v", got, ok)
+	}
+	if _, ok := ToggleCheckboxLine(body, 0); ok {
+		t.Error("expected no checkbox on a heading line")
+	}
+}
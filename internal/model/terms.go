@@ -0,0 +1,74 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// titleWeight is how many times a title word counts compared to a body
+// word.
+const titleWeight = 3
+
+var urlRe = regexp.MustCompile(`https?://\S+`)
+
+var stopWords = make(map[string]bool)
+
+func init() {
+	for _, w := range strings.Fields(`a about above after again against all also am an and any are as at be
+		because been before being below between both but by can could did do does doing done down during each
+		else etc even ever few for from further get got had has have having he her here hers herself him
+		himself his how however i if in into is it its itself just let like made make many may me might
+		more most much must my myself no nor not now of off on once one only or other our ours ourselves out
+		over own per same she should since so some still such than that the their theirs them themselves
+		then there these they this those though through thus to too under until up upon us use used using
+		very via was we well were what when where whether which while who whom whose why will with within
+		without would yet you your yours yourself yourselves todo tbd`) {
+		stopWords[w] = true
+	}
+}
+
+// NoteTerms counts the words of a note for similarity search. Words are
+// lowercased, split on anything but letters and digits and reduced to a
+// rough singular; stop words, numbers, URLs, block IDs and words shorter
+// than three letters are left out. Title words count three times.
+func NoteTerms(title, body string) map[string]int {
+	terms := make(map[string]int)
+	for _, t := range tokenize(title) {
+		terms[t] += titleWeight
+	}
+	body = StripBlockIDs(StripFrontMatter(body))
+	for _, t := range tokenize(urlRe.ReplaceAllString(body, " ")) {
+		terms[t]++
+	}
+	return terms
+}
+
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var terms []string
+	for _, w := range words {
+		if len([]rune(w)) < 3 || stopWords[w] || strings.IndexFunc(w, unicode.IsLetter) < 0 {
+			continue
+		}
+		terms = append(terms, singular(w))
+	}
+	return terms
+}
+
+// singular strips common English plural endings, so "queries" and "query"
+// count as the same word.
+func singular(w string) string {
+	switch {
+	case len(w) > 4 && strings.HasSuffix(w, "ies"):
+		return strings.TrimSuffix(w, "ies") + "y"
+	case strings.HasSuffix(w, "xes"), strings.HasSuffix(w, "ches"), strings.HasSuffix(w, "shes"), strings.HasSuffix(w, "sses"):
+		return strings.TrimSuffix(w, "es")
+	case len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") &&
+		!strings.HasSuffix(w, "us") && !strings.HasSuffix(w, "is"):
+		return strings.TrimSuffix(w, "s")
+	}
+	return w
+}
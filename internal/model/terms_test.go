@@ -0,0 +1,36 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNoteTerms(t *testing.T) {
+	body := `---
+status: draft
+---
+Postgres indexes and the query planner. ^abc123
+See https://example.com/postgres for 2026 notes on Indexes.`
+
+	got := NoteTerms("Postgres Tuning", body)
+	want := map[string]int{
+		"postgre": 4,
+		"tuning":  3,
+		"index":   2,
+		"query":   1,
+		"planner": 1,
+		"see":     1,
+		"note":    1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NoteTerms = %v, want %v", got, want)
+	}
+}
+
+func TestNoteTermsKeepsShortPlurals(t *testing.T) {
+	got := NoteTerms("", "Class status bus analysis keys queries")
+	want := map[string]int{"class": 1, "status": 1, "bus": 1, "analysis": 1, "key": 1, "query": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NoteTerms = %v, want %v", got, want)
+	}
+}
@@ -2,18 +2,21 @@ package publish
 
 import (
 	"fmt"
+	"mime"
+	"net/url"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/jeryldev/kb/internal/model"
 )
 
-var wikilinkPattern = regexp.MustCompile(`\[\[([^\]|]+?)(?:\|([^\]]+?))?\]\]`)
+// AssetsDir is where attachments are copied inside the site, one
+// directory per note so files with the same name do not collide.
+const AssetsDir = "assets"
 
 type NoteResolver interface {
-	GetNoteBySlug(slug string) (*model.Note, error)
+	GetNoteByRef(ref string) (*model.Note, error)
 }
 
 func JekyllFileName(slug string, date time.Time) string {
@@ -24,65 +27,132 @@ func JekyllPermalink(slug string, date time.Time) string {
 	return fmt.Sprintf("/blog/%s/%s/", date.Format("2006/01/02"), slug)
 }
 
-func GenerateFrontMatter(note *model.Note, date time.Time, draft bool) string {
-	var b strings.Builder
-	b.WriteString("---\n")
-	b.WriteString("layout: post\n")
-	fmt.Fprintf(&b, "title: %q\n", note.Title)
-	fmt.Fprintf(&b, "date: %s\n", date.Format("2006-01-02"))
+// GenerateFrontMatter writes the Jekyll front matter for note. props are
+// note properties passed through as-is; one with the same key as a
+// generated field replaces it.
+func GenerateFrontMatter(note *model.Note, date time.Time, draft bool, props []model.Property) string {
+	var keys []string
+	fields := make(map[string]string)
+	set := func(key, value string) {
+		if _, ok := fields[key]; !ok {
+			keys = append(keys, key)
+		}
+		fields[key] = value
+	}
+
+	set("layout", "post")
+	set("title", fmt.Sprintf("%q", note.Title))
+	set("date", date.Format("2006-01-02"))
 
 	if note.Tags != "" {
 		tags := note.TagList()
-		fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(tags, ", "))
+		set("tags", fmt.Sprintf("[%s]", strings.Join(tags, ", ")))
 	}
 
-	excerpt := extractExcerpt(note.Body)
+	excerpt := extractExcerpt(model.StripFrontMatter(note.Body))
 	if excerpt != "" {
-		fmt.Fprintf(&b, "excerpt: %q\n", excerpt)
+		set("excerpt", fmt.Sprintf("%q", excerpt))
 	}
 
 	if draft {
-		b.WriteString("published: false\n")
+		set("published", "false")
 	}
 
+	for _, p := range props {
+		switch p.Type {
+		case model.PropertyList:
+			set(p.Key, fmt.Sprintf("[%s]", strings.Join(p.Items, ", ")))
+		case model.PropertyString:
+			set(p.Key, fmt.Sprintf("%q", p.Value))
+		default:
+			set(p.Key, p.Value)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s: %s\n", key, fields[key])
+	}
 	b.WriteString("---\n")
 	return b.String()
 }
 
-func GeneratePost(note *model.Note, date time.Time, draft bool, publishedSlugs map[string]string, resolver NoteResolver) string {
-	frontMatter := GenerateFrontMatter(note, date, draft)
-	body := ResolveWikilinks(note.Body, publishedSlugs, resolver)
+// SelectProperties returns the properties named in keys, in the order of
+// keys. Unknown keys are ignored.
+func SelectProperties(props []model.Property, keys []string) []model.Property {
+	var selected []model.Property
+	for _, key := range keys {
+		for _, p := range props {
+			if strings.EqualFold(p.Key, key) {
+				selected = append(selected, p)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func GeneratePost(note *model.Note, date time.Time, draft bool, props []model.Property, publishedSlugs map[string]string, resolver NoteResolver) string {
+	frontMatter := GenerateFrontMatter(note, date, draft, props)
+	body := ResolveWikilinks(model.StripFrontMatter(note.Body), note.Slug, publishedSlugs, resolver)
 	return frontMatter + "\n" + body + "\n"
 }
 
-func ResolveWikilinks(body string, publishedSlugs map[string]string, resolver NoteResolver) string {
-	return wikilinkPattern.ReplaceAllStringFunc(body, func(match string) string {
-		groups := wikilinkPattern.FindStringSubmatch(match)
-		target := strings.TrimSpace(groups[1])
-		displayText := ""
-		if len(groups) > 2 {
-			displayText = strings.TrimSpace(groups[2])
+// ResolveWikilinks rewrites the wikilinks in body, the body of the note
+// with the given slug, as Markdown links for the site.
+func ResolveWikilinks(body, slug string, publishedSlugs map[string]string, resolver NoteResolver) string {
+	return resolveWikilinks(body, slug, publishedSlugs, resolver, 0)
+}
+
+func resolveWikilinks(body, slug string, publishedSlugs map[string]string, resolver NoteResolver, depth int) string {
+	body = anchorBlockIDs(body)
+	return model.ReplaceWikilinks(body, func(link model.ParsedLink, _ string) string {
+		if link.TargetType == "attachment" {
+			return attachmentMarkdown(link, slug)
+		}
+		if link.TargetType != "note" {
+			return strings.TrimSpace(link.Display)
 		}
 
-		if strings.HasPrefix(target, "card:") || strings.HasPrefix(target, "board:") {
-			if displayText != "" {
-				return displayText
+		var target *model.Note
+		if link.TargetRef != "" && resolver != nil {
+			if note, err := resolver.GetNoteByRef(link.TargetRef); err == nil {
+				target = note
 			}
-			return strings.TrimPrefix(strings.TrimPrefix(target, "card:"), "board:")
 		}
 
-		title := displayText
-		if title == "" && resolver != nil {
-			note, err := resolver.GetNoteBySlug(target)
-			if err == nil {
-				title = note.Title
+		if link.Embed && target != nil && depth < model.MaxEmbedDepth {
+			if content, ok := model.ExtractFragment(model.StripFrontMatter(target.Body), link); ok {
+				return resolveWikilinks(content, target.Slug, publishedSlugs, resolver, depth+1)
 			}
 		}
+
+		title := ""
+		switch {
+		case link.HasDisplay:
+			title = strings.TrimSpace(link.Display)
+		case link.Heading != "":
+			title = link.Heading
+		case target != nil:
+			title = target.Title
+		}
 		if title == "" {
-			title = target
+			title = link.TargetRef
 		}
 
-		if permalink, ok := publishedSlugs[target]; ok {
+		anchor := fragmentAnchor(link)
+		if link.TargetRef == "" && anchor != "" {
+			return fmt.Sprintf("[%s](#%s)", title, anchor)
+		}
+		permalink, ok := publishedSlugs[link.TargetRef]
+		if !ok && target != nil {
+			permalink, ok = publishedSlugs[target.Slug]
+		}
+		if ok {
+			if anchor != "" {
+				permalink += "#" + anchor
+			}
 			return fmt.Sprintf("[%s](%s)", title, permalink)
 		}
 
@@ -90,6 +160,93 @@ func ResolveWikilinks(body string, publishedSlugs map[string]string, resolver No
 	})
 }
 
+// AssetPath returns where, relative to the site root, the attachment name
+// referenced by the note with the given slug is copied.
+func AssetPath(slug, name string) string {
+	return filepath.Join(AssetsDir, slug, name)
+}
+
+// AssetURL returns the site URL of an attachment copied to AssetPath.
+func AssetURL(slug, name string) string {
+	return "/" + AssetsDir + "/" + url.PathEscape(slug) + "/" + url.PathEscape(name)
+}
+
+func attachmentMarkdown(link model.ParsedLink, slug string) string {
+	display := strings.TrimSpace(link.Display)
+	mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(link.TargetRef)))
+	if link.Embed && strings.HasPrefix(mimeType, "image/") {
+		return fmt.Sprintf("![%s](%s)", display, AssetURL(slug, link.TargetRef))
+	}
+	return fmt.Sprintf("[%s](%s)", display, AssetURL(slug, link.TargetRef))
+}
+
+// AttachmentRef is an attachment name a post references, with the note
+// whose body references it.
+type AttachmentRef struct {
+	Note *model.Note
+	Name string
+}
+
+// AttachmentRefs lists the attachments a post for note will reference,
+// including those inside embedded notes.
+func AttachmentRefs(note *model.Note, resolver NoteResolver) []AttachmentRef {
+	seen := make(map[string]bool)
+	var refs []AttachmentRef
+	var walk func(note *model.Note, body string, depth int)
+	walk = func(note *model.Note, body string, depth int) {
+		for _, link := range model.ParseWikilinks(body) {
+			switch {
+			case link.TargetType == "attachment":
+				key := note.Slug + "/" + strings.ToLower(link.TargetRef)
+				if !seen[key] {
+					seen[key] = true
+					refs = append(refs, AttachmentRef{Note: note, Name: link.TargetRef})
+				}
+			case link.TargetType == "note" && link.Embed && link.TargetRef != "" && resolver != nil && depth < model.MaxEmbedDepth:
+				target, err := resolver.GetNoteByRef(link.TargetRef)
+				if err != nil {
+					continue
+				}
+				if content, ok := model.ExtractFragment(model.StripFrontMatter(target.Body), link); ok {
+					walk(target, content, depth+1)
+				}
+			}
+		}
+	}
+	walk(note, model.StripFrontMatter(note.Body), 0)
+	return refs
+}
+
+func fragmentAnchor(link model.ParsedLink) string {
+	switch {
+	case link.BlockID != "":
+		return model.BlockAnchor(link.BlockID)
+	case link.Heading != "":
+		return model.HeadingAnchor(link.Heading)
+	default:
+		return ""
+	}
+}
+
+func anchorBlockIDs(body string) string {
+	lines := strings.Split(body, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if id, rest, ok := model.ParseBlockID(line); ok {
+			lines[i] = fmt.Sprintf(`%s <a id="%s"></a>`, rest, model.BlockAnchor(id))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func PostFilePath(postsDir, slug string, date time.Time) string {
 	return filepath.Join(postsDir, JekyllFileName(slug, date))
 }
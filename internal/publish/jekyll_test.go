@@ -13,8 +13,8 @@ type mockResolver struct {
 	notes map[string]*model.Note
 }
 
-func (m *mockResolver) GetNoteBySlug(slug string) (*model.Note, error) {
-	if n, ok := m.notes[slug]; ok {
+func (m *mockResolver) GetNoteByRef(ref string) (*model.Note, error) {
+	if n, ok := m.notes[ref]; ok {
 		return n, nil
 	}
 	return nil, fmt.Errorf("not found")
@@ -46,7 +46,7 @@ func TestGenerateFrontMatter(t *testing.T) {
 	}
 	date := time.Date(2026, 2, 24, 0, 0, 0, 0, time.UTC)
 
-	got := GenerateFrontMatter(note, date, false)
+	got := GenerateFrontMatter(note, date, false, nil)
 
 	if !strings.Contains(got, `title: "My Great Post"`) {
 		t.Errorf("missing title in front matter: %s", got)
@@ -69,7 +69,7 @@ func TestGenerateFrontMatterDraft(t *testing.T) {
 	note := &model.Note{Title: "Draft Post", Body: "Content"}
 	date := time.Date(2026, 2, 24, 0, 0, 0, 0, time.UTC)
 
-	got := GenerateFrontMatter(note, date, true)
+	got := GenerateFrontMatter(note, date, true, nil)
 
 	if !strings.Contains(got, "published: false") {
 		t.Errorf("expected published: false for draft: %s", got)
@@ -80,13 +80,41 @@ func TestGenerateFrontMatterNoTags(t *testing.T) {
 	note := &model.Note{Title: "No Tags", Body: "Content"}
 	date := time.Date(2026, 2, 24, 0, 0, 0, 0, time.UTC)
 
-	got := GenerateFrontMatter(note, date, false)
+	got := GenerateFrontMatter(note, date, false, nil)
 
 	if strings.Contains(got, "tags:") {
 		t.Errorf("should not have tags line when note has no tags: %s", got)
 	}
 }
 
+func TestGenerateFrontMatterProperties(t *testing.T) {
+	note := &model.Note{Title: "Props", Body: "---\nstatus: draft\n---\nFirst line."}
+	date := time.Date(2026, 2, 24, 0, 0, 0, 0, time.UTC)
+	props := SelectProperties(model.ParseFrontMatter(
+		"---\nstatus: draft\nlayout: page\nseries: [kb, pkm]\nweight: 3\nprivate: x\n---\n",
+	), []string{"layout", "series", "weight", "missing"})
+
+	got := GenerateFrontMatter(note, date, false, props)
+
+	if !strings.HasPrefix(got, "---\nlayout: \"page\"\n") {
+		t.Errorf("expected property to replace generated layout: %s", got)
+	}
+	if !strings.Contains(got, "series: [kb, pkm]\n") || !strings.Contains(got, "weight: 3\n") {
+		t.Errorf("missing passed-through properties: %s", got)
+	}
+	if strings.Contains(got, "private") || strings.Contains(got, "status") {
+		t.Errorf("unselected properties should not be published: %s", got)
+	}
+	if !strings.Contains(got, `excerpt: "First line."`) {
+		t.Errorf("excerpt should skip the note's own front matter: %s", got)
+	}
+
+	post := GeneratePost(note, date, false, props, nil, nil)
+	if strings.Count(post, "---\n") != 2 {
+		t.Errorf("expected the note's front matter to be stripped from the body: %s", post)
+	}
+}
+
 func TestResolveWikilinksPublished(t *testing.T) {
 	resolver := &mockResolver{
 		notes: map[string]*model.Note{
@@ -98,7 +126,7 @@ func TestResolveWikilinksPublished(t *testing.T) {
 	}
 
 	body := "Check out [[target-note]] for details."
-	got := ResolveWikilinks(body, published, resolver)
+	got := ResolveWikilinks(body, "post", published, resolver)
 	want := "Check out [Target Note](/blog/2026/02/24/target-note/) for details."
 
 	if got != want {
@@ -106,6 +134,22 @@ func TestResolveWikilinksPublished(t *testing.T) {
 	}
 }
 
+func TestResolveWikilinksAlias(t *testing.T) {
+	target := &model.Note{Title: "Use SQLite", Slug: "use-sqlite"}
+	resolver := &mockResolver{
+		notes: map[string]*model.Note{"use-sqlite": target, "ADR-7": target},
+	}
+	published := map[string]string{
+		"use-sqlite": "/blog/2026/02/24/use-sqlite/",
+	}
+
+	got := ResolveWikilinks("Per [[ADR-7]].", "post", published, resolver)
+	want := "Per [Use SQLite](/blog/2026/02/24/use-sqlite/)."
+	if got != want {
+		t.Errorf("ResolveWikilinks() = %q, want %q", got, want)
+	}
+}
+
 func TestResolveWikilinksUnpublished(t *testing.T) {
 	resolver := &mockResolver{
 		notes: map[string]*model.Note{
@@ -115,7 +159,7 @@ func TestResolveWikilinksUnpublished(t *testing.T) {
 	published := map[string]string{}
 
 	body := "See [[private-note]] for internal info."
-	got := ResolveWikilinks(body, published, resolver)
+	got := ResolveWikilinks(body, "post", published, resolver)
 	want := "See Private Note for internal info."
 
 	if got != want {
@@ -129,7 +173,7 @@ func TestResolveWikilinksWithDisplayText(t *testing.T) {
 	}
 
 	body := "Check [[target|my link text]] here."
-	got := ResolveWikilinks(body, published, nil)
+	got := ResolveWikilinks(body, "post", published, nil)
 	want := "Check [my link text](/blog/2026/02/24/target/) here."
 
 	if got != want {
@@ -139,7 +183,7 @@ func TestResolveWikilinksWithDisplayText(t *testing.T) {
 
 func TestResolveWikilinksCardPrefix(t *testing.T) {
 	body := "Related to [[card:abc123]]."
-	got := ResolveWikilinks(body, map[string]string{}, nil)
+	got := ResolveWikilinks(body, "post", map[string]string{}, nil)
 	want := "Related to abc123."
 
 	if got != want {
@@ -149,7 +193,7 @@ func TestResolveWikilinksCardPrefix(t *testing.T) {
 
 func TestResolveWikilinksBoardPrefix(t *testing.T) {
 	body := "See [[board:my-board]]."
-	got := ResolveWikilinks(body, map[string]string{}, nil)
+	got := ResolveWikilinks(body, "post", map[string]string{}, nil)
 	want := "See my-board."
 
 	if got != want {
@@ -159,7 +203,7 @@ func TestResolveWikilinksBoardPrefix(t *testing.T) {
 
 func TestResolveWikilinksCardWithDisplayText(t *testing.T) {
 	body := "See [[card:abc123|the task]]."
-	got := ResolveWikilinks(body, map[string]string{}, nil)
+	got := ResolveWikilinks(body, "post", map[string]string{}, nil)
 	want := "See the task."
 
 	if got != want {
@@ -179,7 +223,7 @@ func TestResolveWikilinksMultiple(t *testing.T) {
 	}
 
 	body := "First [[note-a]], then [[note-b]]."
-	got := ResolveWikilinks(body, published, resolver)
+	got := ResolveWikilinks(body, "post", published, resolver)
 	want := "First [Note A](/blog/2026/02/24/note-a/), then Note B."
 
 	if got != want {
@@ -189,13 +233,96 @@ func TestResolveWikilinksMultiple(t *testing.T) {
 
 func TestResolveWikilinksNoLinks(t *testing.T) {
 	body := "No wikilinks here, just text."
-	got := ResolveWikilinks(body, map[string]string{}, nil)
+	got := ResolveWikilinks(body, "post", map[string]string{}, nil)
 
 	if got != body {
 		t.Errorf("ResolveWikilinks() = %q, want %q", got, body)
 	}
 }
 
+func TestResolveWikilinksHeadingPermalink(t *testing.T) {
+	resolver := &mockResolver{
+		notes: map[string]*model.Note{
+			"design": {Title: "Design"},
+		},
+	}
+	published := map[string]string{
+		"design": "/blog/2026/02/24/design/",
+	}
+
+	body := "Read [[design#Storage Layer]] and [[design^db1|this]]."
+	got := ResolveWikilinks(body, "post", published, resolver)
+	want := "Read [Storage Layer](/blog/2026/02/24/design/#storage-layer) and [this](/blog/2026/02/24/design/#block-db1)."
+
+	if got != want {
+		t.Errorf("ResolveWikilinks() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveWikilinksSameNoteHeading(t *testing.T) {
+	got := ResolveWikilinks("Back to [[#Summary]]", "post", map[string]string{}, nil)
+	want := "Back to [Summary](#summary)"
+
+	if got != want {
+		t.Errorf("ResolveWikilinks() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveWikilinksBlockAnchors(t *testing.T) {
+	got := ResolveWikilinks("A key decision. ^d1", "post", map[string]string{}, nil)
+	want := `A key decision. <a id="block-d1"></a>`
+
+	if got != want {
+		t.Errorf("ResolveWikilinks() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveWikilinksExpandsEmbeds(t *testing.T) {
+	resolver := &mockResolver{
+		notes: map[string]*model.Note{
+			"design": {Title: "Design", Body: "# Design\n\n## Storage\n\nSQLite, see [[api]].\n\n## API\n\nREST"},
+			"api":    {Title: "API Notes"},
+		},
+	}
+
+	got := ResolveWikilinks("Before\n\n![[design#Storage]]\n\nAfter", "post", map[string]string{}, resolver)
+	want := "Before\n\n## Storage\n\nSQLite, see API Notes.\n\nAfter"
+
+	if got != want {
+		t.Errorf("ResolveWikilinks() = %q, want %q", got, want)
+	}
+}
+
+func TestEmbedsSkipFrontMatter(t *testing.T) {
+	resolver := &mockResolver{
+		notes: map[string]*model.Note{
+			"spec": {Title: "Spec", Slug: "spec", Body: "---\ncover: ![[cover.png]]\nstatus: draft\n---\nThe spec body ![[diagram.png]]"},
+		},
+	}
+
+	got := ResolveWikilinks("![[spec]]", "post", map[string]string{}, resolver)
+	if want := "The spec body ![diagram.png](/assets/spec/diagram.png)"; got != want {
+		t.Errorf("ResolveWikilinks() = %q, want %q", got, want)
+	}
+	refs := AttachmentRefs(&model.Note{Slug: "post", Body: "![[spec]]"}, resolver)
+	if len(refs) != 1 || refs[0].Name != "diagram.png" {
+		t.Errorf("AttachmentRefs = %+v, want only diagram.png", refs)
+	}
+}
+
+func TestResolveWikilinksEmbedCycle(t *testing.T) {
+	resolver := &mockResolver{
+		notes: map[string]*model.Note{
+			"loop": {Title: "Loop", Body: "x ![[loop]]"},
+		},
+	}
+
+	got := ResolveWikilinks("![[loop]]", "post", map[string]string{}, resolver)
+	if strings.Count(got, "x ") != model.MaxEmbedDepth {
+		t.Errorf("expected embed recursion to stop at depth %d, got %q", model.MaxEmbedDepth, got)
+	}
+}
+
 func TestGeneratePost(t *testing.T) {
 	note := &model.Note{
 		Title: "Test Post",
@@ -212,7 +339,7 @@ func TestGeneratePost(t *testing.T) {
 	}
 	date := time.Date(2026, 2, 24, 0, 0, 0, 0, time.UTC)
 
-	got := GeneratePost(note, date, false, published, resolver)
+	got := GeneratePost(note, date, false, nil, published, resolver)
 
 	if !strings.HasPrefix(got, "---\n") {
 		t.Error("expected front matter at start")
@@ -258,3 +385,32 @@ func TestExtractExcerptTruncates(t *testing.T) {
 		t.Errorf("expected ... suffix, got %q", got[len(got)-5:])
 	}
 }
+
+func TestResolveWikilinksAttachments(t *testing.T) {
+	body := "![[Architecture Diagram.png]]\n![[spec.pdf]]\n[[crash.log|the log]]"
+	got := ResolveWikilinks(body, "post", nil, nil)
+	want := "![Architecture Diagram.png](/assets/post/Architecture%20Diagram.png)\n[spec.pdf](/assets/post/spec.pdf)\n[the log](/assets/post/crash.log)"
+	if got != want {
+		t.Errorf("ResolveWikilinks =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAttachmentRefs(t *testing.T) {
+	resolver := &mockResolver{
+		notes: map[string]*model.Note{
+			"design": {Title: "Design", Slug: "design", Body: "## Storage\n\n![[schema.png]]\n![[chart.png]]\n\n## API\n\n![[api.pdf]]"},
+		},
+	}
+	note := &model.Note{Slug: "post", Body: "---\ncover: x\n---\n![[chart.png]] [[Chart.PNG]]\n![[design#Storage]]\n[[other-note]]"}
+	var got []string
+	for _, ref := range AttachmentRefs(note, resolver) {
+		got = append(got, AssetPath(ref.Note.Slug, ref.Name))
+	}
+	want := []string{"assets/post/chart.png", "assets/design/schema.png", "assets/design/chart.png"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("AttachmentRefs = %v, want %v", got, want)
+	}
+	if embedded := ResolveWikilinks("![[design#Storage]]", "post", nil, resolver); !strings.Contains(embedded, "](/assets/design/schema.png)") {
+		t.Errorf("expected embedded attachments under the embedded note, got %q", embedded)
+	}
+}
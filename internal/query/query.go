@@ -0,0 +1,155 @@
+// Package query evaluates ```kb-query blocks embedded in note bodies, such
+// as "cards board:sprint-1 priority:urgent" or "notes tag:adr sort:updated",
+// and renders their results as markdown tables or lists.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// Lang is the info string that marks a fenced block as a query.
+const Lang = "kb-query"
+
+// Sources lists what a query can select.
+var Sources = []string{"cards", "notes", "tasks"}
+
+var sourceKeys = map[string][]string{
+	"cards": {"board", "column", "priority", "label", "workspace", "sort", "limit", "format"},
+	"notes": {"tag", "workspace", "sort", "limit", "format"},
+	"tasks": {"tag", "workspace", "sort", "limit", "format"},
+}
+
+// Query is a parsed kb-query block. Filters holds key:value terms, Where
+// the property comparisons of a notes query and Search any bare words.
+type Query struct {
+	Source  string
+	Filters map[string]string
+	Where   []model.PropertyFilter
+	Open    bool
+	Search  string
+	Limit   int
+}
+
+// Get returns the value of a key:value filter, or "".
+func (q *Query) Get(key string) string {
+	return q.Filters[key]
+}
+
+// Parse reads a query: the source first, then key:value filters, property
+// comparisons like status=draft (notes only), "open" (tasks only) and free
+// text to search for. Values with spaces can be double-quoted.
+func Parse(src string) (*Query, error) {
+	fields := splitFields(src)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty query: start with one of %s", strings.Join(Sources, ", "))
+	}
+
+	source := strings.ToLower(fields[0])
+	if !strings.HasSuffix(source, "s") {
+		source += "s"
+	}
+	keys, ok := sourceKeys[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown source %q: use one of %s", fields[0], strings.Join(Sources, ", "))
+	}
+
+	q := &Query{Source: source, Filters: make(map[string]string)}
+	var search []string
+	for _, field := range fields[1:] {
+		if key, value, ok := strings.Cut(field, ":"); ok && isKey(key) {
+			key = strings.ToLower(key)
+			if !contains(keys, key) {
+				return nil, fmt.Errorf("unknown filter %q for %s: use %s", key, source, strings.Join(keys, ", "))
+			}
+			value = unquote(value)
+			if value == "" {
+				return nil, fmt.Errorf("filter %q needs a value", key)
+			}
+			q.Filters[key] = value
+			continue
+		}
+		if source == "notes" && strings.ContainsAny(field, "=<>") {
+			f, err := model.ParsePropertyFilter(field)
+			if err != nil {
+				return nil, err
+			}
+			q.Where = append(q.Where, f)
+			continue
+		}
+		if source == "tasks" && strings.EqualFold(field, "open") {
+			q.Open = true
+			continue
+		}
+		search = append(search, unquote(field))
+	}
+	q.Search = strings.Join(search, " ")
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid limit %q: use a positive number", v)
+		}
+		q.Limit = n
+	}
+	if v := q.Get("format"); v != "" && v != "table" && v != "list" {
+		return nil, fmt.Errorf("invalid format %q: use table or list", v)
+	}
+	if v := q.Get("priority"); v != "" {
+		p, err := model.ParsePriority(v)
+		if err != nil {
+			return nil, err
+		}
+		q.Filters["priority"] = string(p)
+	}
+	return q, nil
+}
+
+func isKey(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) }) == -1
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// splitFields splits on whitespace, keeping double-quoted runs together.
+func splitFields(s string) []string {
+	var fields []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			cur.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`cards board:"Sprint 1" priority:URGENT login limit:5`)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+	if q.Source != "cards" || q.Get("board") != "Sprint 1" || q.Get("priority") != "urgent" ||
+		q.Search != "login" || q.Limit != 5 {
+		t.Errorf("unexpected query: %+v", q)
+	}
+
+	q, err = Parse("note tag:adr status=accepted sort:-date")
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+	if q.Source != "notes" || len(q.Where) != 1 || q.Where[0].Key != "status" || q.Get("sort") != "-date" {
+		t.Errorf("unexpected query: %+v", q)
+	}
+
+	for _, bad := range []string{"", "boards", "cards tag:x", "notes limit:0", "cards priority:soon", "tasks format:grid", "notes tag:"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	db, err := store.OpenWithPath(":memory:")
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ws, _ := db.GetDefaultWorkspace()
+
+	board, _ := db.CreateBoard("Sprint 1", "", ws.ID)
+	todo, _ := db.CreateColumn(board.ID, "Todo")
+	card, _ := db.CreateCard(todo.ID, "Fix a|b login", model.PriorityUrgent)
+	card.Labels = "bug"
+	db.UpdateCard(card)
+	db.CreateCard(todo.ID, "Polish", model.PriorityLow)
+	adr, _ := db.CreateNote("Use SQLite", "adr-1", "Decision.\n\n- [ ] Write migration @due(2026-01-02)", ws.ID)
+	adr.Tags = "adr"
+	db.UpdateNote(adr)
+
+	body := "# Dashboard\n\n```kb-query\ncards board:sprint-1\npriority:urgent\n```\n\n" +
+		"```kb-query\nnotes tag:adr sort:updated\n```\n\n```go\n```kb-query\n```\n\n" +
+		"```kb-query\ntasks open\n```\n\n```kb-query\nwidgets\n```"
+	out := Expand(db, body)
+
+	for _, want := range []string{
+		"| Card | Board | Column | Priority | Labels |",
+		`Fix a/b login]] | Sprint 1 | Todo | urgent | bug |`,
+		"- [[adr-1|Use SQLite]]",
+		"```go\n```kb-query\n```",
+		"- [ ] Write migration · due 2026-01-02 — [[adr-1|Use SQLite]]",
+		`> kb-query: unknown source "widgets"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Polish") {
+		t.Errorf("priority filter not applied:\n%s", out)
+	}
+
+	links := model.ParseWikilinks(out)
+	if len(links) == 0 || links[0].TargetType != "card" || links[0].Display != `Fix a/b login` || strings.HasSuffix(links[0].TargetRef, `\`) {
+		t.Errorf("expected escaped card link in table, got %+v", links)
+	}
+
+	if got := Expand(db, "```kb-query\ncards board:missing\n```"); got != `> kb-query: board "missing" not found` {
+		t.Errorf("unexpected error rendering %q", got)
+	}
+	if got, _ := Evaluate(db, "notes tag:none"); got != "_No matching notes._\n" {
+		t.Errorf("unexpected empty result %q", got)
+	}
+}
@@ -0,0 +1,336 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+)
+
+// cell escapes text for a table cell; Obsidian reads "\|" inside a
+// wikilink as the alias separator.
+var cell = strings.NewReplacer("|", "\\|", "\n", " ")
+
+var linkText = strings.NewReplacer("|", "/", "[", "(", "]", ")")
+
+// Expand replaces every kb-query block in body with its results. A query
+// that fails is replaced by a quote explaining the error, so one bad block
+// does not hide the rest of the note.
+func Expand(db *store.DB, body string) string {
+	lines := strings.Split(body, "\n")
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "```") && !strings.HasPrefix(trimmed, "~~~") {
+			out = append(out, lines[i])
+			continue
+		}
+
+		fence := trimmed[:3]
+		end := i + 1
+		for end < len(lines) && !isClosingFence(lines[end], fence) {
+			end++
+		}
+		if end == len(lines) || strings.TrimSpace(trimmed[3:]) != Lang {
+			stop := min(end, len(lines)-1)
+			out = append(out, lines[i:stop+1]...)
+			i = stop
+			continue
+		}
+
+		result, err := Evaluate(db, strings.Join(lines[i+1:end], " "))
+		if err != nil {
+			result = fmt.Sprintf("> %s: %v", Lang, err)
+		}
+		out = append(out, strings.TrimRight(result, "\n"))
+		i = end
+	}
+	return strings.Join(out, "\n")
+}
+
+// isClosingFence reports whether line closes a block opened with fence. A
+// closing fence carries no info string.
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// Evaluate parses and runs a query, returning its markdown.
+func Evaluate(db *store.DB, src string) (string, error) {
+	q, err := Parse(src)
+	if err != nil {
+		return "", err
+	}
+	return Run(db, q)
+}
+
+// Run executes q and renders the results. Cards default to a table, notes
+// and tasks to a list; format:table or format:list overrides that.
+func Run(db *store.DB, q *Query) (string, error) {
+	switch q.Source {
+	case "cards":
+		return runCards(db, q)
+	case "notes":
+		return runNotes(db, q)
+	default:
+		return runTasks(db, q)
+	}
+}
+
+type cardRow struct {
+	card   *model.Card
+	board  string
+	column string
+}
+
+func runCards(db *store.DB, q *Query) (string, error) {
+	var boards []*model.Board
+	var err error
+	if ws := q.Get("workspace"); ws != "" {
+		w, err := db.GetWorkspaceByName(ws)
+		if err != nil {
+			return "", err
+		}
+		boards, err = db.ListBoardsByWorkspace(w.ID)
+		if err != nil {
+			return "", err
+		}
+	} else if boards, err = db.ListBoards(); err != nil {
+		return "", err
+	}
+	if name := q.Get("board"); name != "" {
+		var matched []*model.Board
+		for _, b := range boards {
+			if strings.EqualFold(b.Name, name) || model.Slugify(b.Name) == model.Slugify(name) {
+				matched = append(matched, b)
+			}
+		}
+		if len(matched) == 0 {
+			return "", fmt.Errorf("board %q not found", name)
+		}
+		boards = matched
+	}
+
+	filter := store.CardFilter{Priority: q.Get("priority"), Column: q.Get("column"), Label: q.Get("label"), Search: q.Search}
+	var rows []cardRow
+	for _, b := range boards {
+		columns, err := db.ListColumns(b.ID)
+		if err != nil {
+			return "", err
+		}
+		names := make(map[string]string, len(columns))
+		for _, c := range columns {
+			names[c.ID] = c.Name
+		}
+		cards, err := db.ListBoardCardsFiltered(b.ID, filter)
+		if err != nil {
+			return "", err
+		}
+		for _, c := range cards {
+			rows = append(rows, cardRow{card: c, board: b.Name, column: names[c.ColumnID]})
+		}
+	}
+
+	switch key := q.Get("sort"); key {
+	case "":
+	case "priority":
+		sort.SliceStable(rows, func(i, j int) bool {
+			return slices.Index(model.Priorities, rows[i].card.Priority) < slices.Index(model.Priorities, rows[j].card.Priority)
+		})
+	case "updated":
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].card.UpdatedAt.After(rows[j].card.UpdatedAt) })
+	case "created":
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].card.CreatedAt.After(rows[j].card.CreatedAt) })
+	case "title":
+		sort.SliceStable(rows, func(i, j int) bool {
+			return strings.ToLower(rows[i].card.Title) < strings.ToLower(rows[j].card.Title)
+		})
+	default:
+		return "", fmt.Errorf("invalid sort %q for cards: use priority, updated, created or title", key)
+	}
+	if q.Limit > 0 && len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+	}
+	if len(rows) == 0 {
+		return "_No matching cards._\n", nil
+	}
+
+	var b strings.Builder
+	if q.Get("format") == "list" {
+		for _, r := range rows {
+			fmt.Fprintf(&b, "- [[card:%s|%s]] · %s / %s · %s\n",
+				r.card.ID, linkText.Replace(r.card.Title), r.board, r.column, r.card.Priority)
+		}
+		return b.String(), nil
+	}
+	b.WriteString("| Card | Board | Column | Priority | Labels |\n| --- | --- | --- | --- | --- |\n")
+	for _, r := range rows {
+		fmt.Fprintf(&b, "| [[card:%s\\|%s]] | %s | %s | %s | %s |\n",
+			r.card.ID, cell.Replace(linkText.Replace(r.card.Title)), cell.Replace(r.board), cell.Replace(r.column),
+			r.card.Priority, cell.Replace(strings.Join(r.card.LabelList(), ", ")))
+	}
+	return b.String(), nil
+}
+
+func runNotes(db *store.DB, q *Query) (string, error) {
+	propSort := ""
+	key := q.Get("sort")
+	switch key {
+	case "", "updated", "created", "title":
+	default:
+		propSort = key
+	}
+	notes, err := db.QueryNotes(q.Where, propSort)
+	if err != nil {
+		return "", err
+	}
+
+	var tagged map[string]bool
+	if tag := q.Get("tag"); tag != "" {
+		list, err := db.ListNotesByTag(tag)
+		if err != nil {
+			return "", err
+		}
+		tagged = make(map[string]bool, len(list))
+		for _, n := range list {
+			tagged[n.ID] = true
+		}
+	}
+	workspaceID := ""
+	if ws := q.Get("workspace"); ws != "" {
+		w, err := db.GetWorkspaceByName(ws)
+		if err != nil {
+			return "", err
+		}
+		workspaceID = w.ID
+	}
+	search := strings.ToLower(q.Search)
+
+	var matched []*model.Note
+	for _, n := range notes {
+		if tagged != nil && !tagged[n.ID] {
+			continue
+		}
+		if workspaceID != "" && n.WorkspaceID != workspaceID {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(n.Title+"\n"+n.Body), search) {
+			continue
+		}
+		matched = append(matched, n)
+	}
+
+	switch key {
+	case "updated":
+		sort.SliceStable(matched, func(i, j int) bool { return matched[i].UpdatedAt.After(matched[j].UpdatedAt) })
+	case "created":
+		sort.SliceStable(matched, func(i, j int) bool { return matched[i].CreatedAt.After(matched[j].CreatedAt) })
+	case "title":
+		sort.SliceStable(matched, func(i, j int) bool {
+			return strings.ToLower(matched[i].Title) < strings.ToLower(matched[j].Title)
+		})
+	}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+	if len(matched) == 0 {
+		return "_No matching notes._\n", nil
+	}
+
+	var b strings.Builder
+	if q.Get("format") == "table" {
+		b.WriteString("| Note | Tags | Updated |\n| --- | --- | --- |\n")
+		for _, n := range matched {
+			fmt.Fprintf(&b, "| [[%s\\|%s]] | %s | %s |\n",
+				n.Slug, cell.Replace(linkText.Replace(n.Title)), cell.Replace(strings.Join(n.TagList(), ", ")),
+				n.UpdatedAt.Local().Format("2006-01-02"))
+		}
+		return b.String(), nil
+	}
+	for _, n := range matched {
+		fmt.Fprintf(&b, "- [[%s|%s]]\n", n.Slug, linkText.Replace(n.Title))
+	}
+	return b.String(), nil
+}
+
+func runTasks(db *store.DB, q *Query) (string, error) {
+	filter := store.TaskFilter{Open: q.Open, Tag: q.Get("tag")}
+	if ws := q.Get("workspace"); ws != "" {
+		w, err := db.GetWorkspaceByName(ws)
+		if err != nil {
+			return "", err
+		}
+		filter.WorkspaceID = w.ID
+	}
+	tasks, err := db.ListTasks(filter)
+	if err != nil {
+		return "", err
+	}
+
+	search := strings.ToLower(q.Search)
+	var matched []*model.Task
+	for _, t := range tasks {
+		if search == "" || strings.Contains(strings.ToLower(t.Text), search) {
+			matched = append(matched, t)
+		}
+	}
+
+	switch key := q.Get("sort"); key {
+	case "":
+	case "due":
+		sort.SliceStable(matched, func(i, j int) bool {
+			if (matched[i].Due == "") != (matched[j].Due == "") {
+				return matched[j].Due == ""
+			}
+			return matched[i].Due < matched[j].Due
+		})
+	case "priority":
+		rank := func(t *model.Task) int {
+			if t.Priority == "" {
+				return slices.Index(model.Priorities, model.PriorityMedium)
+			}
+			return slices.Index(model.Priorities, t.Priority)
+		}
+		sort.SliceStable(matched, func(i, j int) bool { return rank(matched[i]) < rank(matched[j]) })
+	default:
+		return "", fmt.Errorf("invalid sort %q for tasks: use due or priority", key)
+	}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+	if len(matched) == 0 {
+		return "_No matching tasks._\n", nil
+	}
+
+	var b strings.Builder
+	if q.Get("format") == "table" {
+		b.WriteString("| Done | Task | Note | Due | Priority |\n| --- | --- | --- | --- | --- |\n")
+		for _, t := range matched {
+			done := ""
+			if t.Checked {
+				done = "x"
+			}
+			fmt.Fprintf(&b, "| %s | %s | [[%s\\|%s]] | %s | %s |\n",
+				done, cell.Replace(t.Text), t.NoteSlug, cell.Replace(linkText.Replace(t.NoteTitle)), t.Due, t.Priority)
+		}
+		return b.String(), nil
+	}
+	for _, t := range matched {
+		box := " "
+		if t.Checked {
+			box = "x"
+		}
+		parts := []string{t.Text}
+		if t.Priority != "" {
+			parts = append(parts, "!"+string(t.Priority))
+		}
+		if t.Due != "" {
+			parts = append(parts, "due "+t.Due)
+		}
+		fmt.Fprintf(&b, "- [%s] %s — [[%s|%s]]\n", box, strings.Join(parts, " · "), t.NoteSlug, linkText.Replace(t.NoteTitle))
+	}
+	return b.String(), nil
+}
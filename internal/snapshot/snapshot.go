@@ -0,0 +1,129 @@
+// Package snapshot records the state of a board as a markdown note.
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+)
+
+const stampLayout = "2006-01-02 15:04"
+
+var linkText = strings.NewReplacer("|", "/", "[", "(", "]", ")")
+
+// Slug returns the default slug of a snapshot of board taken at at.
+func Slug(board *model.Board, at time.Time) string {
+	return model.Slugify(board.Name) + "-snapshot-" + at.Format("2006-01-02-1504")
+}
+
+// Tags returns the tags of a snapshot note: the board name and the date
+// nested under "snapshot".
+func Tags(board *model.Board, at time.Time) []string {
+	return []string{model.Slugify(board.Name), "snapshot/" + at.Format("2006-01-02")}
+}
+
+// Render writes the columns and cards of board as markdown, one heading of
+// the given level per column and one [[card:...]] link per card.
+func Render(board *model.Board, columns []*model.Column, cards []*model.Card, level int) string {
+	byColumn := make(map[string][]*model.Card)
+	for _, c := range cards {
+		byColumn[c.ColumnID] = append(byColumn[c.ColumnID], c)
+	}
+
+	var b strings.Builder
+	for i, col := range columns {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		colCards := byColumn[col.ID]
+		count := fmt.Sprintf("%d", len(colCards))
+		if col.WIPLimit != nil {
+			count += fmt.Sprintf("/%d", *col.WIPLimit)
+		}
+		fmt.Fprintf(&b, "%s %s (%s)\n\n", strings.Repeat("#", level), col.Name, count)
+		if len(colCards) == 0 {
+			b.WriteString("_No cards._\n")
+			continue
+		}
+		for _, c := range colCards {
+			parts := []string{fmt.Sprintf("[[card:%s|%s]]", c.ID, linkText.Replace(c.Title)), string(c.Priority)}
+			if labels := c.LabelList(); len(labels) > 0 {
+				parts = append(parts, "`"+strings.Join(labels, "` `")+"`")
+			}
+			if c.ExternalID != "" {
+				parts = append(parts, c.ExternalID)
+			}
+			fmt.Fprintf(&b, "- %s\n", strings.Join(parts, " · "))
+		}
+	}
+	return b.String()
+}
+
+// Take snapshots board into a note. With an empty slug a new note is
+// created in the board's workspace; a slug or alias naming an existing
+// note gets the snapshot appended as a new section. created reports a new
+// note.
+func Take(db *store.DB, board *model.Board, slug string, at time.Time) (note *model.Note, created bool, err error) {
+	columns, err := db.ListColumns(board.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	cards, err := db.ListBoardCards(board.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	intro := fmt.Sprintf("Snapshot of [[board:%s]] taken %s.", board.Name, at.Format(stampLayout))
+	if slug != "" {
+		existing, err := db.GetNoteByRef(slug)
+		switch {
+		case err == nil:
+			note = existing
+		case !errors.Is(err, store.ErrNotFound):
+			return nil, false, err
+		}
+	}
+
+	if note == nil {
+		if slug == "" {
+			slug = Slug(board, at)
+		}
+		workspaceID := board.WorkspaceID
+		if workspaceID == "" {
+			ws, err := db.GetDefaultWorkspace()
+			if err != nil {
+				return nil, false, err
+			}
+			workspaceID = ws.ID
+		}
+		title := fmt.Sprintf("%s snapshot %s", board.Name, at.Format(stampLayout))
+		note, err = db.CreateNote(title, slug, intro+"\n\n"+Render(board, columns, cards, 2), workspaceID)
+		if err != nil {
+			return nil, false, err
+		}
+		created = true
+	} else {
+		section := fmt.Sprintf("## %s snapshot %s\n\n%s\n\n%s",
+			board.Name, at.Format(stampLayout), intro, Render(board, columns, cards, 3))
+		note.Body = strings.TrimRight(note.Body, "\n") + "\n\n" + section
+	}
+
+	tags := note.TagList()
+	for _, t := range Tags(board, at) {
+		if !note.HasTag(t) {
+			tags = append(tags, t)
+		}
+	}
+	note.Tags = strings.Join(tags, ",")
+	if err := db.UpdateNote(note); err != nil {
+		return nil, false, err
+	}
+	if err := db.SyncNoteLinks(note); err != nil {
+		return nil, false, err
+	}
+	return note, created, nil
+}
@@ -0,0 +1,98 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+)
+
+func TestRender(t *testing.T) {
+	limit := 2
+	board := &model.Board{ID: "b1", Name: "Sprint 4"}
+	columns := []*model.Column{
+		{ID: "todo", Name: "Todo", WIPLimit: &limit},
+		{ID: "done", Name: "Done"},
+	}
+	cards := []*model.Card{
+		{ID: "c1", ColumnID: "todo", Title: "Fix [login] | SSO", Priority: model.PriorityUrgent, Labels: "bug, auth", ExternalID: "JIRA-7"},
+		{ID: "c2", ColumnID: "todo", Title: "Docs", Priority: model.PriorityLow},
+	}
+
+	got := Render(board, columns, cards, 2)
+	want := "## Todo (2/2)\n\n" +
+		"- [[card:c1|Fix (login) / SSO]] · urgent · `bug` `auth` · JIRA-7\n" +
+		"- [[card:c2|Docs]] · low\n" +
+		"\n## Done (0)\n\n_No cards._\n"
+	if got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+	if !strings.HasPrefix(Render(board, columns, nil, 3), "### Todo (0/2)") {
+		t.Error("expected level 3 headings")
+	}
+}
+
+func TestTake(t *testing.T) {
+	db, err := store.OpenWithPath(":memory:")
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ws, _ := db.GetDefaultWorkspace()
+	board, _ := db.CreateBoard("Sprint 4", "", ws.ID)
+	cols, _ := db.ListColumns(board.ID)
+	card, _ := db.CreateCard(cols[1].ID, "Fix login", model.PriorityHigh)
+	at := time.Date(2026, 10, 18, 14, 5, 0, 0, time.UTC)
+
+	note, created, err := Take(db, board, "", at)
+	if err != nil || !created {
+		t.Fatalf("taking snapshot: %v, created = %v", err, created)
+	}
+	if note.Slug != "sprint-4-snapshot-2026-10-18-1405" || note.WorkspaceID != ws.ID {
+		t.Errorf("unexpected note: %+v", note)
+	}
+	if !strings.Contains(note.Body, "[[board:Sprint 4]] taken 2026-10-18 14:05") ||
+		!strings.Contains(note.Body, "## Todo (1)\n\n- [[card:"+card.ID+"|Fix login]] · high") {
+		t.Errorf("unexpected body:\n%s", note.Body)
+	}
+	if note.Tags != "sprint-4,snapshot/2026-10-18" {
+		t.Errorf("tags = %q", note.Tags)
+	}
+	backlinks, _ := db.GetBacklinks("card", card.ID)
+	if len(backlinks) != 1 || backlinks[0].SourceID != note.ID {
+		t.Errorf("expected a backlink from the snapshot to the card, got %+v", backlinks)
+	}
+
+	retro, _ := db.CreateNote("Retro", "retro", "# Retro", ws.ID)
+	retro.Tags = "sprint-4"
+	db.UpdateNote(retro)
+	appended, created, err := Take(db, board, "retro", at.AddDate(0, 0, 1))
+	if err != nil || created {
+		t.Fatalf("appending snapshot: %v, created = %v", err, created)
+	}
+	if !strings.HasPrefix(appended.Body, "# Retro\n\n## Sprint 4 snapshot 2026-10-19 14:05\n\n") ||
+		!strings.Contains(appended.Body, "### Todo (1)") {
+		t.Errorf("unexpected appended body:\n%s", appended.Body)
+	}
+	if appended.Tags != "sprint-4,snapshot/2026-10-19" {
+		t.Errorf("tags = %q", appended.Tags)
+	}
+
+	db.AddNoteAlias(retro.ID, "Sprint 4 retro")
+	aliased, created, err := Take(db, board, "Sprint 4 retro", at.AddDate(0, 0, 2))
+	if err != nil || created || aliased.ID != retro.ID {
+		t.Errorf("expected the alias to reach the retro note: %v, created = %v", err, created)
+	}
+
+	named, created, err := Take(db, board, "sprint-4-final", at)
+	if err != nil || !created || named.Slug != "sprint-4-final" {
+		t.Errorf("expected a new note with the given slug: %v, %+v", err, named)
+	}
+
+	db.ArchiveNote(named.ID)
+	if _, _, err := Take(db, board, "sprint-4-final", at); err == nil || !strings.Contains(err.Error(), "archived") {
+		t.Errorf("expected a clear error for an archived note, got %v", err)
+	}
+}
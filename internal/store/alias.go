@@ -0,0 +1,209 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// ErrNotFound is wrapped by lookups that found nothing, so callers can
+// tell a missing note from a failed query.
+var ErrNotFound = errors.New("not found")
+
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// resolveNoteRef finds the active note a wikilink target refers to, by
+// exact slug first and then by alias.
+func resolveNoteRef(q queryRower, ref string) (string, error) {
+	var id string
+	err := q.QueryRow(
+		"SELECT id FROM notes WHERE slug = ? AND archived_at IS NULL AND deleted_at IS NULL", ref,
+	).Scan(&id)
+	if err != sql.ErrNoRows {
+		return id, err
+	}
+	err = q.QueryRow(
+		`SELECT n.id FROM note_aliases a
+		 JOIN notes n ON n.id = a.note_id
+		 WHERE a.alias = ? AND n.archived_at IS NULL AND n.deleted_at IS NULL`,
+		strings.TrimSpace(ref),
+	).Scan(&id)
+	return id, err
+}
+
+// checkSlugNotAlias rejects a slug that is already an alias of another
+// note, since [[slug]] could then reach either note.
+func checkSlugNotAlias(q queryRower, noteID, slug string) error {
+	var owner string
+	err := q.QueryRow(
+		`SELECT n.slug FROM note_aliases a JOIN notes n ON n.id = a.note_id
+		 WHERE a.alias = ? COLLATE NOCASE AND a.note_id != ?`,
+		slug, noteID,
+	).Scan(&owner)
+	if err == nil {
+		return fmt.Errorf("slug %q is already an alias of note %q", slug, owner)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("checking aliases: %w", err)
+	}
+	return nil
+}
+
+func (d *DB) GetNoteByRef(ref string) (*model.Note, error) {
+	id, err := resolveNoteRef(d.conn, ref)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("note %q %w", ref, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolving note: %w", err)
+	}
+	return d.GetNote(id)
+}
+
+func (d *DB) AddNoteAlias(noteID, alias string) error {
+	alias = strings.TrimSpace(alias)
+	if err := model.ValidateNoteAlias(alias); err != nil {
+		return err
+	}
+
+	var owner string
+	err := d.conn.QueryRow(
+		`SELECT n.slug FROM note_aliases a JOIN notes n ON n.id = a.note_id WHERE a.alias = ?`, alias,
+	).Scan(&owner)
+	if err == nil {
+		return fmt.Errorf("alias %q is already used by note %q", alias, owner)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("checking alias: %w", err)
+	}
+	err = d.conn.QueryRow(
+		"SELECT slug FROM notes WHERE slug = ? COLLATE NOCASE AND deleted_at IS NULL", alias,
+	).Scan(&owner)
+	if err == nil {
+		return fmt.Errorf("alias %q conflicts with the slug of note %q", alias, owner)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("checking alias: %w", err)
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"INSERT INTO note_aliases (alias, note_id) VALUES (?, ?)", alias, noteID,
+	); err != nil {
+		return fmt.Errorf("adding alias: %w", err)
+	}
+	// Links written before the alias existed are stored unresolved.
+	if _, err := tx.Exec(
+		"UPDATE OR IGNORE links SET target_id = ? WHERE target_type = 'note' AND target_id = ? COLLATE NOCASE",
+		noteID, alias,
+	); err != nil {
+		return fmt.Errorf("resolving links to alias: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (d *DB) RemoveNoteAlias(noteID, alias string) error {
+	result, err := d.conn.Exec(
+		"DELETE FROM note_aliases WHERE note_id = ? AND alias = ?", noteID, strings.TrimSpace(alias),
+	)
+	if err != nil {
+		return fmt.Errorf("removing alias: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("alias %q not found", alias)
+	}
+
+	backlinks, err := d.GetBacklinks("note", noteID)
+	if err != nil {
+		return err
+	}
+	for _, l := range backlinks {
+		switch l.SourceType {
+		case "note":
+			source, err := d.GetNote(l.SourceID)
+			if err != nil {
+				continue
+			}
+			if err := d.SyncNoteLinks(source); err != nil {
+				return err
+			}
+		case "card":
+			source, err := d.GetCard(l.SourceID)
+			if err != nil {
+				continue
+			}
+			if err := d.syncCardSource(source); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// syncCardSource relinks a card's description in a transaction of its own.
+func (d *DB) syncCardSource(card *model.Card) error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := syncCardLinks(tx, card); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (d *DB) ListNoteAliases(noteID string) ([]string, error) {
+	rows, err := d.conn.Query(
+		"SELECT alias FROM note_aliases WHERE note_id = ? ORDER BY alias COLLATE NOCASE", noteID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("scanning alias: %w", err)
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, rows.Err()
+}
+
+func (d *DB) ListAllNoteAliases() (map[string][]string, error) {
+	rows, err := d.conn.Query(
+		"SELECT note_id, alias FROM note_aliases ORDER BY alias COLLATE NOCASE",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make(map[string][]string)
+	for rows.Next() {
+		var noteID, alias string
+		if err := rows.Scan(&noteID, &alias); err != nil {
+			return nil, fmt.Errorf("scanning alias: %w", err)
+		}
+		aliases[noteID] = append(aliases[noteID], alias)
+	}
+	return aliases, rows.Err()
+}
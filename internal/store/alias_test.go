@@ -0,0 +1,151 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+func TestNoteAliases(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	note, _ := db.CreateNote("Use SQLite for storage", "use-sqlite-for-storage", "", wsID)
+	other, _ := db.CreateNote("Other", "other", "", wsID)
+
+	if err := db.AddNoteAlias(note.ID, "ADR-7"); err != nil {
+		t.Fatalf("adding alias: %v", err)
+	}
+	db.AddNoteAlias(note.ID, "storage decision")
+
+	aliases, err := db.ListNoteAliases(note.ID)
+	if err != nil {
+		t.Fatalf("listing aliases: %v", err)
+	}
+	if strings.Join(aliases, ",") != "ADR-7,storage decision" {
+		t.Errorf("aliases = %v", aliases)
+	}
+
+	got, err := db.GetNoteByRef("adr-7")
+	if err != nil || got.ID != note.ID {
+		t.Errorf("expected alias lookup to be case-insensitive, got %v, %v", got, err)
+	}
+	if got, err := db.GetNoteByRef("other"); err != nil || got.ID != other.ID {
+		t.Errorf("expected slug lookup, got %v, %v", got, err)
+	}
+
+	if err := db.AddNoteAlias(other.ID, "adr-7"); err == nil {
+		t.Error("expected error when another note claims the alias")
+	}
+	if err := db.AddNoteAlias(note.ID, "Other"); err == nil {
+		t.Error("expected error when alias matches another note's slug")
+	}
+	if err := db.AddNoteAlias(note.ID, "a|b"); err == nil {
+		t.Error("expected validation error")
+	}
+
+	all, _ := db.ListAllNoteAliases()
+	if len(all[note.ID]) != 2 || len(all[other.ID]) != 0 {
+		t.Errorf("unexpected alias map: %v", all)
+	}
+
+	if err := db.RemoveNoteAlias(note.ID, "ADR-7"); err != nil {
+		t.Fatalf("removing alias: %v", err)
+	}
+	if err := db.RemoveNoteAlias(note.ID, "ADR-7"); err == nil {
+		t.Error("expected error removing a missing alias")
+	}
+	if _, err := db.GetNoteByRef("ADR-7"); err == nil {
+		t.Error("expected removed alias to stop resolving")
+	}
+}
+
+func TestNoteAliasLinks(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	target, _ := db.CreateNote("Use SQLite", "use-sqlite", "", wsID)
+	source, _ := db.CreateNote("Source", "source", "per [[ADR-7]]", wsID)
+	db.SyncNoteLinks(source)
+
+	if links, _ := db.GetBacklinks("note", target.ID); len(links) != 0 {
+		t.Fatalf("expected no backlinks before alias, got %d", len(links))
+	}
+
+	db.AddNoteAlias(target.ID, "ADR-7")
+	if links, _ := db.GetBacklinks("note", target.ID); len(links) != 1 {
+		t.Errorf("expected existing link to resolve through new alias, got %d", len(links))
+	}
+
+	later, _ := db.CreateNote("Later", "later", "see [[adr-7|the ADR]]", wsID)
+	db.SyncNoteLinks(later)
+	if links, _ := db.GetBacklinks("note", target.ID); len(links) != 2 {
+		t.Errorf("expected synced link to resolve through alias, got %d", len(links))
+	}
+
+	db.RemoveNoteAlias(target.ID, "ADR-7")
+	if links, _ := db.GetBacklinks("note", target.ID); len(links) != 0 {
+		t.Errorf("expected links to detach when alias is removed, got %d", len(links))
+	}
+
+	db.AddNoteAlias(target.ID, "sqlite")
+	db.PurgeNote(target.ID)
+	if all, _ := db.ListAllNoteAliases(); len(all) != 0 {
+		t.Errorf("expected aliases removed with note, got %v", all)
+	}
+}
+
+func TestRemoveNoteAliasDetachesCardLinks(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+	_, col := createTestBoardWithColumn(t, db)
+
+	target, _ := db.CreateNote("Use SQLite", "use-sqlite", "", wsID)
+	db.AddNoteAlias(target.ID, "ADR-7")
+
+	card, _ := db.CreateCard(col.ID, "Migrate", model.PriorityMedium)
+	card.Description = "per [[ADR-7]]"
+	if err := db.UpdateCard(card); err != nil {
+		t.Fatalf("UpdateCard: %v", err)
+	}
+	if links, _ := db.GetBacklinks("note", target.ID); len(links) != 1 {
+		t.Fatalf("expected card link to resolve through alias, got %d", len(links))
+	}
+
+	if err := db.RemoveNoteAlias(target.ID, "ADR-7"); err != nil {
+		t.Fatalf("RemoveNoteAlias: %v", err)
+	}
+	if links, _ := db.GetBacklinks("note", target.ID); len(links) != 0 {
+		t.Errorf("expected card link to detach when alias is removed, got %d", len(links))
+	}
+	links, _ := db.GetForwardLinks("card", card.ID)
+	if len(links) != 1 || links[0].TargetID != "ADR-7" {
+		t.Errorf("expected card link kept as unresolved ADR-7, got %+v", links)
+	}
+}
+
+func TestSlugConflictsWithAlias(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	note, _ := db.CreateNote("Use SQLite", "use-sqlite", "", wsID)
+	if err := db.AddNoteAlias(note.ID, "adr-7"); err != nil {
+		t.Fatalf("adding alias: %v", err)
+	}
+
+	if _, err := db.CreateNote("ADR 7", "adr-7", "", wsID); err == nil || !strings.Contains(err.Error(), "alias") {
+		t.Errorf("expected create to reject an alias as slug, got %v", err)
+	}
+
+	other, _ := db.CreateNote("Other", "other", "", wsID)
+	other.Slug = "adr-7"
+	if err := db.UpdateNote(other); err == nil {
+		t.Error("expected rename to reject an alias as slug")
+	}
+
+	note.Slug = "adr-7"
+	if err := db.UpdateNote(note); err != nil {
+		t.Errorf("a note may take its own alias as slug: %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package store
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// SetAttachmentDir sets where attachment files are stored.
+func (d *DB) SetAttachmentDir(dir string) {
+	d.attachDir = dir
+}
+
+// AttachmentDir returns the content-addressed directory holding attachment
+// files, or "" when none is configured.
+func (d *DB) AttachmentDir() string {
+	return d.attachDir
+}
+
+// AttachmentPath returns where the file of an attachment is stored. Files are
+// named by the SHA-256 of their content, so identical files share storage.
+func (d *DB) AttachmentPath(a *model.Attachment) string {
+	return filepath.Join(d.attachDir, a.Hash[:2], a.Hash)
+}
+
+// AttachFile copies the file at src into the attachment store and records it
+// against a note or card. ownerType is "note" or "card".
+func (d *DB) AttachFile(ownerType, ownerID, src string) (*model.Attachment, error) {
+	if d.attachDir == "" {
+		return nil, fmt.Errorf("no attachment directory configured")
+	}
+	var column string
+	switch ownerType {
+	case "note":
+		column = "note_id"
+	case "card":
+		column = "card_id"
+	default:
+		return nil, fmt.Errorf("cannot attach files to %q", ownerType)
+	}
+
+	name := filepath.Base(src)
+	if err := model.ValidateAttachmentName(name); err != nil {
+		return nil, err
+	}
+
+	var exists int
+	err := d.conn.QueryRow(
+		"SELECT 1 FROM attachments WHERE "+column+" = ? AND name = ? COLLATE NOCASE", ownerID, name,
+	).Scan(&exists)
+	if err == nil {
+		return nil, fmt.Errorf("%q is already attached to this %s", name, ownerType)
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("checking attachments: %w", err)
+	}
+
+	hash, size, err := d.storeBlob(src)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))
+	if i := strings.Index(mimeType, ";"); i != -1 {
+		mimeType = mimeType[:i]
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	a := &model.Attachment{
+		ID:        uuid.New().String(),
+		Hash:      hash,
+		Name:      name,
+		MimeType:  mimeType,
+		Size:      size,
+		CreatedAt: time.Now().UTC(),
+	}
+	if ownerType == "card" {
+		a.CardID = ownerID
+	} else {
+		a.NoteID = ownerID
+	}
+
+	if _, err := d.conn.Exec(
+		"INSERT INTO attachments (id, hash, name, mime_type, size, "+column+", created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		a.ID, a.Hash, a.Name, a.MimeType, a.Size, ownerID, a.CreatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("inserting attachment: %w", err)
+	}
+	return a, nil
+}
+
+// storeBlob copies src into the attachment directory under its content hash.
+func (d *DB) storeBlob(src string) (string, int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", 0, fmt.Errorf("opening %s: %w", src, err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(d.attachDir, 0o755); err != nil {
+		return "", 0, fmt.Errorf("creating attachment directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(d.attachDir, "upload-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), in)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("copying %s: %w", src, err)
+	}
+
+	hash := hex.EncodeToString(h.Sum(nil))
+	dest := filepath.Join(d.attachDir, hash[:2], hash)
+	if _, err := os.Stat(dest); err == nil {
+		return hash, size, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", 0, fmt.Errorf("creating attachment directory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return "", 0, fmt.Errorf("storing attachment: %w", err)
+	}
+	return hash, size, nil
+}
+
+// ListAttachments lists the attachments of a note or card, or every
+// attachment when ownerType is empty.
+func (d *DB) ListAttachments(ownerType, ownerID string) ([]*model.Attachment, error) {
+	query := "SELECT id, hash, name, mime_type, size, note_id, card_id, created_at FROM attachments"
+	var args []any
+	switch ownerType {
+	case "":
+	case "note":
+		query += " WHERE note_id = ?"
+		args = append(args, ownerID)
+	case "card":
+		query += " WHERE card_id = ?"
+		args = append(args, ownerID)
+	default:
+		return nil, fmt.Errorf("unknown attachment owner %q", ownerType)
+	}
+	query += " ORDER BY created_at, name"
+
+	rows, err := d.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing attachments: %w", err)
+	}
+	defer rows.Close()
+	return scanAttachments(rows)
+}
+
+// GetAttachmentByName returns the attachment with the given file name, as
+// used by ![[file.png]] embeds. The owner's own attachments win; otherwise
+// the most recently added one anywhere is used. An empty ownerType skips
+// the owner preference.
+func (d *DB) GetAttachmentByName(ownerType, ownerID, name string) (*model.Attachment, error) {
+	order := "created_at DESC"
+	args := []any{strings.TrimSpace(name)}
+	switch ownerType {
+	case "":
+	case "note":
+		order = "COALESCE(note_id = ?, 0) DESC, " + order
+		args = append(args, ownerID)
+	case "card":
+		order = "COALESCE(card_id = ?, 0) DESC, " + order
+		args = append(args, ownerID)
+	default:
+		return nil, fmt.Errorf("unknown attachment owner %q", ownerType)
+	}
+	rows, err := d.conn.Query(
+		`SELECT id, hash, name, mime_type, size, note_id, card_id, created_at
+		 FROM attachments WHERE name = ? COLLATE NOCASE
+		 ORDER BY `+order+` LIMIT 1`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting attachment: %w", err)
+	}
+	defer rows.Close()
+	attachments, err := scanAttachments(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("attachment %q not found", name)
+	}
+	return attachments[0], nil
+}
+
+// ResolveAttachment finds an attachment by ID, ID prefix or file name.
+func (d *DB) ResolveAttachment(ref string) (*model.Attachment, error) {
+	all, err := d.ListAttachments("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*model.Attachment
+	for _, a := range all {
+		if a.ID == ref {
+			return a, nil
+		}
+		if (len(ref) >= 4 && strings.HasPrefix(a.ID, ref)) || strings.EqualFold(a.Name, ref) {
+			matches = append(matches, a)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no attachment found matching %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("ambiguous attachment %q matches %d attachments; use the ID", ref, len(matches))
+	}
+}
+
+// RemoveAttachment deletes an attachment, and its file once no other
+// attachment shares the same content.
+func (d *DB) RemoveAttachment(id string) error {
+	var hash string
+	if err := d.conn.QueryRow("SELECT hash FROM attachments WHERE id = ?", id).Scan(&hash); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("attachment not found")
+		}
+		return fmt.Errorf("getting attachment: %w", err)
+	}
+
+	if _, err := d.conn.Exec("DELETE FROM attachments WHERE id = ?", id); err != nil {
+		return fmt.Errorf("deleting attachment: %w", err)
+	}
+
+	var remaining int
+	if err := d.conn.QueryRow("SELECT COUNT(*) FROM attachments WHERE hash = ?", hash).Scan(&remaining); err != nil {
+		return fmt.Errorf("counting attachments: %w", err)
+	}
+	if remaining == 0 && d.attachDir != "" {
+		path := filepath.Join(d.attachDir, hash[:2], hash)
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing attachment file: %w", err)
+		}
+	}
+	return nil
+}
+
+func scanAttachments(rows *sql.Rows) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	for rows.Next() {
+		a := &model.Attachment{}
+		var noteID, cardID *string
+		if err := rows.Scan(&a.ID, &a.Hash, &a.Name, &a.MimeType, &a.Size, &noteID, &cardID, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning attachment: %w", err)
+		}
+		if noteID != nil {
+			a.NoteID = *noteID
+		}
+		if cardID != nil {
+			a.CardID = *cardID
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
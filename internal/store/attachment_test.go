@@ -0,0 +1,143 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+	return path
+}
+
+func TestAttachFile(t *testing.T) {
+	db := testDB(t)
+	db.SetAttachmentDir(t.TempDir())
+	wsID := testDefaultWSID(t, db)
+	note, _ := db.CreateNote("Design", "design", "body", wsID)
+
+	src := writeTestFile(t, "diagram.png", "png-bytes")
+	a, err := db.AttachFile("note", note.ID, src)
+	if err != nil {
+		t.Fatalf("attaching file: %v", err)
+	}
+	if a.Name != "diagram.png" || a.MimeType != "image/png" || a.Size != 9 || a.NoteID != note.ID {
+		t.Errorf("unexpected attachment: %+v", a)
+	}
+	data, err := os.ReadFile(db.AttachmentPath(a))
+	if err != nil || string(data) != "png-bytes" {
+		t.Fatalf("stored file = %q, %v", data, err)
+	}
+
+	if _, err := db.AttachFile("note", note.ID, src); err == nil {
+		t.Error("expected error attaching the same name twice")
+	}
+
+	list, err := db.ListAttachments("note", note.ID)
+	if err != nil || len(list) != 1 || list[0].ID != a.ID {
+		t.Fatalf("ListAttachments = %v, %v", list, err)
+	}
+
+	got, err := db.GetAttachmentByName("", "", "DIAGRAM.png")
+	if err != nil || got.ID != a.ID {
+		t.Errorf("GetAttachmentByName = %v, %v", got, err)
+	}
+	if _, err := db.GetAttachmentByName("note", note.ID, "missing.png"); err == nil {
+		t.Error("expected error for missing attachment")
+	}
+}
+
+func TestGetAttachmentByNamePrefersOwner(t *testing.T) {
+	db := testDB(t)
+	db.SetAttachmentDir(t.TempDir())
+	wsID := testDefaultWSID(t, db)
+	first, _ := db.CreateNote("First", "first", "", wsID)
+	second, _ := db.CreateNote("Second", "second", "", wsID)
+
+	mine, _ := db.AttachFile("note", first.ID, writeTestFile(t, "chart.png", "first"))
+	theirs, _ := db.AttachFile("note", second.ID, writeTestFile(t, "chart.png", "second"))
+
+	if got, err := db.GetAttachmentByName("note", first.ID, "chart.png"); err != nil || got.ID != mine.ID {
+		t.Errorf("expected the note's own attachment, got %v, %v", got, err)
+	}
+	if got, err := db.GetAttachmentByName("note", second.ID, "chart.png"); err != nil || got.ID != theirs.ID {
+		t.Errorf("expected the note's own attachment, got %v, %v", got, err)
+	}
+	other, _ := db.CreateNote("Other", "other", "", wsID)
+	if got, err := db.GetAttachmentByName("note", other.ID, "chart.png"); err != nil || got.ID != theirs.ID {
+		t.Errorf("expected the newest attachment as a fallback, got %v, %v", got, err)
+	}
+}
+
+func TestAttachFileToCard(t *testing.T) {
+	db := testDB(t)
+	db.SetAttachmentDir(t.TempDir())
+	wsID := testDefaultWSID(t, db)
+	board, _ := db.CreateBoard("Work", "", wsID)
+	cols, _ := db.ListColumns(board.ID)
+	card, _ := db.CreateCard(cols[0].ID, "Crash", "high")
+
+	a, err := db.AttachFile("card", card.ID, writeTestFile(t, "crash.log", "panic"))
+	if err != nil {
+		t.Fatalf("attaching file: %v", err)
+	}
+	if kind, id := a.Owner(); kind != "card" || id != card.ID {
+		t.Errorf("Owner() = %s %s", kind, id)
+	}
+	if list, _ := db.ListAttachments("card", card.ID); len(list) != 1 {
+		t.Errorf("expected 1 card attachment, got %d", len(list))
+	}
+	if _, err := db.AttachFile("board", board.ID, writeTestFile(t, "x.txt", "x")); err == nil {
+		t.Error("expected error for unsupported owner")
+	}
+}
+
+func TestAttachFileWithoutDir(t *testing.T) {
+	db := testDB(t)
+	note, _ := db.CreateNote("Design", "design", "body", testDefaultWSID(t, db))
+	if _, err := db.AttachFile("note", note.ID, writeTestFile(t, "a.txt", "a")); err == nil {
+		t.Error("expected error without an attachment directory")
+	}
+}
+
+func TestRemoveAttachmentSharedContent(t *testing.T) {
+	db := testDB(t)
+	db.SetAttachmentDir(t.TempDir())
+	wsID := testDefaultWSID(t, db)
+	n1, _ := db.CreateNote("One", "one", "", wsID)
+	n2, _ := db.CreateNote("Two", "two", "", wsID)
+
+	a1, _ := db.AttachFile("note", n1.ID, writeTestFile(t, "same.txt", "shared"))
+	a2, _ := db.AttachFile("note", n2.ID, writeTestFile(t, "copy.txt", "shared"))
+	if a1.Hash != a2.Hash {
+		t.Fatal("expected identical content to share a hash")
+	}
+
+	if _, err := db.ResolveAttachment("same.txt"); err != nil {
+		t.Fatalf("resolving by name: %v", err)
+	}
+	if got, err := db.ResolveAttachment(a2.ID[:8]); err != nil || got.ID != a2.ID {
+		t.Fatalf("resolving by ID prefix: %v, %v", got, err)
+	}
+
+	if err := db.RemoveAttachment(a1.ID); err != nil {
+		t.Fatalf("removing attachment: %v", err)
+	}
+	if _, err := os.Stat(db.AttachmentPath(a2)); err != nil {
+		t.Errorf("shared file removed while still referenced: %v", err)
+	}
+	if err := db.RemoveAttachment(a2.ID); err != nil {
+		t.Fatalf("removing attachment: %v", err)
+	}
+	if _, err := os.Stat(db.AttachmentPath(a2)); !os.IsNotExist(err) {
+		t.Errorf("expected file removed, got %v", err)
+	}
+	if all, _ := db.ListAttachments("", ""); len(all) != 0 {
+		t.Errorf("expected no attachments, got %d", len(all))
+	}
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -55,6 +56,75 @@ func (d *DB) CreateBoard(name, description, workspaceID string) (*model.Board, e
 	return board, nil
 }
 
+// ImportBoard creates board with the given columns and cards in one
+// transaction. Columns must already have IDs, which cards refer to through
+// ColumnID; cards with ArchivedAt set are stored archived.
+func (d *DB) ImportBoard(board *model.Board, columns []*model.Column, cards []*model.Card) error {
+	if err := model.ValidateBoardName(board.Name); err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("board needs at least one column")
+	}
+
+	now := time.Now().UTC()
+	board.ID = uuid.New().String()
+	board.CreatedAt, board.UpdatedAt = now, now
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"INSERT INTO boards (id, name, description, workspace_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		board.ID, board.Name, board.Description, board.WorkspaceID, board.CreatedAt, board.UpdatedAt,
+	); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return fmt.Errorf("board %q already exists", board.Name)
+		}
+		return fmt.Errorf("inserting board: %w", err)
+	}
+
+	for i, col := range columns {
+		if err := model.ValidateColumnName(col.Name); err != nil {
+			return err
+		}
+		col.BoardID, col.Position = board.ID, i
+		if _, err := tx.Exec(
+			"INSERT INTO columns (id, board_id, name, position, wip_limit) VALUES (?, ?, ?, ?, ?)",
+			col.ID, col.BoardID, col.Name, col.Position, col.WIPLimit,
+		); err != nil {
+			return fmt.Errorf("inserting column %q: %w", col.Name, err)
+		}
+	}
+
+	for _, card := range cards {
+		if err := model.ValidateCardTitle(card.Title); err != nil {
+			return fmt.Errorf("card %q: %w", truncateTitle(card.Title, 40), err)
+		}
+		card.ID = uuid.New().String()
+		card.CreatedAt, card.UpdatedAt = now, now
+		if err := insertCard(tx, card); err != nil {
+			return err
+		}
+		if err := syncCardLinks(tx, card); err != nil {
+			return err
+		}
+		if card.ArchivedAt != nil {
+			if _, err := tx.Exec("UPDATE cards SET archived_at = ? WHERE id = ?", card.ArchivedAt, card.ID); err != nil {
+				return fmt.Errorf("archiving card: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing import: %w", err)
+	}
+	return nil
+}
+
 func (d *DB) GetBoard(id string) (*model.Board, error) {
 	board := &model.Board{}
 	var wsID *string
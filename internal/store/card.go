@@ -26,37 +26,48 @@ func (d *DB) CreateCard(columnID, title string, priority model.Priority) (*model
 		return nil, err
 	}
 
-	var maxPos int
-	err := d.conn.QueryRow(
-		"SELECT COALESCE(MAX(position), -1) FROM cards WHERE column_id = ? AND deleted_at IS NULL",
-		columnID,
-	).Scan(&maxPos)
-	if err != nil {
-		return nil, fmt.Errorf("getting max position: %w", err)
-	}
-
 	now := time.Now().UTC()
 	card := &model.Card{
 		ID:        uuid.New().String(),
 		ColumnID:  columnID,
 		Title:     title,
 		Priority:  priority,
-		Position:  maxPos + 1,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	if err := insertCard(d.conn, card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+type execQueryRower interface {
+	queryRower
+	Exec(query string, args ...any) (sql.Result, error)
+}
 
-	_, err = d.conn.Exec(
+// insertCard adds card at the end of its column.
+func insertCard(q execQueryRower, card *model.Card) error {
+	var maxPos int
+	err := q.QueryRow(
+		"SELECT COALESCE(MAX(position), -1) FROM cards WHERE column_id = ? AND deleted_at IS NULL",
+		card.ColumnID,
+	).Scan(&maxPos)
+	if err != nil {
+		return fmt.Errorf("getting max position: %w", err)
+	}
+	card.Position = maxPos + 1
+
+	_, err = q.Exec(
 		`INSERT INTO cards (id, column_id, title, description, priority, position, labels, external_id, created_at, updated_at)
 		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		card.ID, card.ColumnID, card.Title, card.Description, string(card.Priority),
 		card.Position, card.Labels, card.ExternalID, card.CreatedAt, card.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("inserting card: %w", err)
+		return fmt.Errorf("inserting card: %w", err)
 	}
-
-	return card, nil
+	return nil
 }
 
 func (d *DB) GetCard(id string) (*model.Card, error) {
@@ -82,6 +93,75 @@ func (d *DB) GetCard(id string) (*model.Card, error) {
 	return card, nil
 }
 
+// ResolveCardRef finds the active card a [[card:...]] link refers to, by ID,
+// case-insensitive title or unique ID prefix, and returns it with the ID of
+// its board.
+func (d *DB) ResolveCardRef(ref string) (*model.Card, string, error) {
+	var id, boardID string
+	err := d.conn.QueryRow(
+		`SELECT c.id, col.board_id FROM cards c
+		 JOIN columns col ON col.id = c.column_id
+		 WHERE c.deleted_at IS NULL AND c.archived_at IS NULL
+		   AND (c.id = ? OR c.title = ? COLLATE NOCASE)
+		 ORDER BY c.id = ? DESC, c.updated_at DESC
+		 LIMIT 1`,
+		ref, ref, ref,
+	).Scan(&id, &boardID)
+	if err == sql.ErrNoRows && len(ref) >= model.MinCardIDPrefix {
+		id, boardID, err = d.resolveCardIDPrefix(ref)
+	}
+	if err == sql.ErrNoRows {
+		return nil, "", fmt.Errorf("card %q not found", ref)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("resolving card: %w", err)
+	}
+	card, err := d.GetCard(id)
+	if err != nil {
+		return nil, "", err
+	}
+	return card, boardID, nil
+}
+
+// resolveCardIDPrefix finds the one active card whose ID starts with
+// prefix, returning sql.ErrNoRows if there is none.
+func (d *DB) resolveCardIDPrefix(prefix string) (string, string, error) {
+	rows, err := d.conn.Query(
+		`SELECT c.id, col.board_id FROM cards c
+		 JOIN columns col ON col.id = c.column_id
+		 WHERE c.deleted_at IS NULL AND c.archived_at IS NULL
+		   AND substr(c.id, 1, ?) = ?
+		 LIMIT 2`,
+		len(prefix), prefix,
+	)
+	if err != nil {
+		return "", "", err
+	}
+	defer rows.Close()
+
+	var ids, boardIDs []string
+	for rows.Next() {
+		var id, boardID string
+		if err := rows.Scan(&id, &boardID); err != nil {
+			return "", "", err
+		}
+		ids = append(ids, id)
+		boardIDs = append(boardIDs, boardID)
+	}
+	if err := rows.Err(); err != nil {
+		return "", "", err
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", "", sql.ErrNoRows
+	case 1:
+		return ids[0], boardIDs[0], nil
+	default:
+		return "", "", fmt.Errorf("ambiguous card ID %q; use more characters", prefix)
+	}
+}
+
 func (d *DB) ListCards(columnID string) ([]*model.Card, error) {
 	rows, err := d.conn.Query(
 		`SELECT id, column_id, title, description, priority, position, labels, external_id,
@@ -118,8 +198,33 @@ func (d *DB) UpdateCard(card *model.Card) error {
 		return err
 	}
 
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var columnID string
+	err = tx.QueryRow("SELECT column_id FROM cards WHERE id = ? AND deleted_at IS NULL", card.ID).Scan(&columnID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("card not found or deleted")
+	}
+	if err != nil {
+		return fmt.Errorf("querying card: %w", err)
+	}
+	// A new column is a move: it goes to the end of that column and shows
+	// up in the board activity.
+	if columnID != card.ColumnID {
+		if err := moveCard(tx, card.ID, card.ColumnID); err != nil {
+			return err
+		}
+		if err := tx.QueryRow("SELECT position FROM cards WHERE id = ?", card.ID).Scan(&card.Position); err != nil {
+			return fmt.Errorf("querying card position: %w", err)
+		}
+	}
+
 	card.UpdatedAt = time.Now().UTC()
-	result, err := d.conn.Exec(
+	result, err := tx.Exec(
 		`UPDATE cards SET column_id = ?, title = ?, description = ?, priority = ?,
 		 position = ?, labels = ?, external_id = ?, updated_at = ?
 		 WHERE id = ? AND deleted_at IS NULL`,
@@ -137,7 +242,13 @@ func (d *DB) UpdateCard(card *model.Card) error {
 	if rows == 0 {
 		return fmt.Errorf("card not found or deleted")
 	}
-	return nil
+	if err := syncPromotedNote(tx, card.ID); err != nil {
+		return err
+	}
+	if err := syncCardLinks(tx, card); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (d *DB) MoveCard(cardID, targetColumnID string) error {
@@ -151,9 +262,7 @@ func (d *DB) MoveCard(cardID, targetColumnID string) error {
 	if err != nil {
 		return fmt.Errorf("finding card's board: %w", err)
 	}
-	err = d.conn.QueryRow(
-		"SELECT board_id FROM columns WHERE id = ?", targetColumnID,
-	).Scan(&colBoardID)
+	err = d.conn.QueryRow("SELECT board_id FROM columns WHERE id = ?", targetColumnID).Scan(&colBoardID)
 	if err != nil {
 		return fmt.Errorf("finding target column's board: %w", err)
 	}
@@ -161,8 +270,45 @@ func (d *DB) MoveCard(cardID, targetColumnID string) error {
 		return fmt.Errorf("cannot move card across boards")
 	}
 
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := moveCard(tx, cardID, targetColumnID); err != nil {
+		return err
+	}
+	if err := syncPromotedNote(tx, cardID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// moveCard puts a card at the end of a column on the same board and records
+// the move. Moving into the board's last column counts as done.
+func moveCard(tx *sql.Tx, cardID, targetColumnID string) error {
+	var fromColumnID, fromName, toName string
+	err := tx.QueryRow(
+		`SELECT c.id, c.name FROM columns c
+		 JOIN cards ca ON ca.column_id = c.id
+		 WHERE ca.id = ? AND ca.deleted_at IS NULL`, cardID,
+	).Scan(&fromColumnID, &fromName)
+	if err != nil {
+		return fmt.Errorf("finding card's column: %w", err)
+	}
+	var toPos, lastPos int
+	err = tx.QueryRow(
+		`SELECT c.name, c.position,
+		        (SELECT MAX(position) FROM columns WHERE board_id = c.board_id)
+		 FROM columns c WHERE c.id = ?`, targetColumnID,
+	).Scan(&toName, &toPos, &lastPos)
+	if err != nil {
+		return fmt.Errorf("finding target column: %w", err)
+	}
+
 	var maxPos int
-	err = d.conn.QueryRow(
+	err = tx.QueryRow(
 		"SELECT COALESCE(MAX(position), -1) FROM cards WHERE column_id = ? AND deleted_at IS NULL",
 		targetColumnID,
 	).Scan(&maxPos)
@@ -171,16 +317,57 @@ func (d *DB) MoveCard(cardID, targetColumnID string) error {
 	}
 
 	now := time.Now().UTC()
-	_, err = d.conn.Exec(
+	_, err = tx.Exec(
 		"UPDATE cards SET column_id = ?, position = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL",
 		targetColumnID, maxPos+1, now, cardID,
 	)
 	if err != nil {
 		return fmt.Errorf("moving card: %w", err)
 	}
+
+	if fromColumnID != targetColumnID {
+		_, err = tx.Exec(
+			`INSERT INTO card_moves (id, card_id, from_column, to_column, done, moved_at)
+			 VALUES (?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), cardID, fromName, toName, boolToInt(toPos == lastPos), now,
+		)
+		if err != nil {
+			return fmt.Errorf("recording card move: %w", err)
+		}
+	}
 	return nil
 }
 
+func (d *DB) ListCardMoves(since, until time.Time) ([]*model.CardMove, error) {
+	rows, err := d.conn.Query(
+		`SELECT m.id, m.card_id, ca.title, b.name, m.from_column, m.to_column, m.done, m.moved_at
+		 FROM card_moves m
+		 JOIN cards ca ON ca.id = m.card_id
+		 JOIN columns col ON col.id = ca.column_id
+		 JOIN boards b ON b.id = col.board_id
+		 WHERE m.moved_at >= ? AND m.moved_at < ? AND ca.deleted_at IS NULL
+		 ORDER BY m.moved_at`,
+		since.UTC(), until.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing card moves: %w", err)
+	}
+	defer rows.Close()
+
+	var moves []*model.CardMove
+	for rows.Next() {
+		m := &model.CardMove{}
+		var done int
+		if err := rows.Scan(&m.ID, &m.CardID, &m.CardTitle, &m.BoardName,
+			&m.FromColumn, &m.ToColumn, &done, &m.MovedAt); err != nil {
+			return nil, fmt.Errorf("scanning card move: %w", err)
+		}
+		m.Done = done != 0
+		moves = append(moves, m)
+	}
+	return moves, rows.Err()
+}
+
 func (d *DB) ArchiveCard(id string) error {
 	now := time.Now().UTC()
 	result, err := d.conn.Exec(
@@ -271,6 +458,40 @@ func (d *DB) ListBoardCards(boardID string) ([]*model.Card, error) {
 	return cards, rows.Err()
 }
 
+// ListArchivedBoardCards lists the archived cards of a board, most recently
+// archived last.
+func (d *DB) ListArchivedBoardCards(boardID string) ([]*model.Card, error) {
+	rows, err := d.conn.Query(
+		`SELECT c.id, c.column_id, c.title, c.description, c.priority, c.position, c.labels,
+		        c.external_id, c.archived_at, c.deleted_at, c.created_at, c.updated_at
+		 FROM cards c
+		 JOIN columns col ON c.column_id = col.id
+		 WHERE col.board_id = ? AND c.deleted_at IS NULL AND c.archived_at IS NOT NULL
+		 ORDER BY c.archived_at, c.position`,
+		boardID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing archived cards: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []*model.Card
+	for rows.Next() {
+		card := &model.Card{}
+		var priority string
+		if err := rows.Scan(
+			&card.ID, &card.ColumnID, &card.Title, &card.Description, &priority,
+			&card.Position, &card.Labels, &card.ExternalID,
+			&card.ArchivedAt, &card.DeletedAt, &card.CreatedAt, &card.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning card: %w", err)
+		}
+		card.Priority = model.Priority(priority)
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
 func (d *DB) ListBoardCardsFiltered(boardID string, filter CardFilter) ([]*model.Card, error) {
 	if filter.IsEmpty() {
 		return d.ListBoardCards(boardID)
@@ -2,6 +2,7 @@ package store
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jeryldev/kb/internal/model"
 )
@@ -211,6 +212,72 @@ func TestMoveCard(t *testing.T) {
 	}
 }
 
+func TestMoveCardRecordsHistory(t *testing.T) {
+	db := testDB(t)
+	board, col := createTestBoardWithColumn(t, db)
+
+	columns, _ := db.ListColumns(board.ID)
+	last := columns[len(columns)-1]
+
+	card, _ := db.CreateCard(col.ID, "Ship it", model.PriorityHigh)
+	db.MoveCard(card.ID, columns[1].ID)
+	db.MoveCard(card.ID, columns[1].ID)
+	db.MoveCard(card.ID, last.ID)
+
+	now := time.Now()
+	moves, err := db.ListCardMoves(now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListCardMoves failed: %v", err)
+	}
+	if len(moves) != 2 {
+		t.Fatalf("expected 2 moves (same-column move skipped), got %d", len(moves))
+	}
+	if moves[0].FromColumn != col.Name || moves[0].ToColumn != columns[1].Name || moves[0].Done {
+		t.Errorf("first move = %+v", moves[0])
+	}
+	if moves[1].ToColumn != last.Name || !moves[1].Done {
+		t.Errorf("expected final move into %q to be done, got %+v", last.Name, moves[1])
+	}
+	if moves[1].CardTitle != "Ship it" || moves[1].BoardName != board.Name {
+		t.Errorf("unexpected card or board: %+v", moves[1])
+	}
+
+	earlier, _ := db.ListCardMoves(now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+	if len(earlier) != 0 {
+		t.Errorf("expected no moves in an earlier window, got %d", len(earlier))
+	}
+}
+
+func TestUpdateCardRecordsColumnChange(t *testing.T) {
+	db := testDB(t)
+	board, col := createTestBoardWithColumn(t, db)
+	columns, _ := db.ListColumns(board.ID)
+	last := columns[len(columns)-1]
+
+	db.CreateCard(last.ID, "Already done", model.PriorityLow)
+	card, _ := db.CreateCard(col.ID, "Ship it", model.PriorityHigh)
+	card.ColumnID = last.ID
+	card.Title = "Ship it now"
+	if err := db.UpdateCard(card); err != nil {
+		t.Fatalf("updating card: %v", err)
+	}
+
+	now := time.Now()
+	moves, _ := db.ListCardMoves(now.Add(-time.Hour), now.Add(time.Hour))
+	if len(moves) != 1 || moves[0].FromColumn != col.Name || moves[0].ToColumn != last.Name || !moves[0].Done {
+		t.Fatalf("expected the column change in the activity, got %+v", moves)
+	}
+	got, _ := db.GetCard(card.ID)
+	if got.ColumnID != last.ID || got.Position != 1 || got.Title != "Ship it now" {
+		t.Errorf("expected the card at the end of %q, got %+v", last.Name, got)
+	}
+
+	db.UpdateCard(got)
+	if moves, _ := db.ListCardMoves(now.Add(-time.Hour), now.Add(time.Hour)); len(moves) != 1 {
+		t.Errorf("an update in place should not record a move, got %d", len(moves))
+	}
+}
+
 func TestArchiveCard(t *testing.T) {
 	db := testDB(t)
 	_, col := createTestBoardWithColumn(t, db)
@@ -522,3 +589,66 @@ func TestMoveCardCrossBoardReturnsError(t *testing.T) {
 		t.Error("MoveCard across boards should return error")
 	}
 }
+
+func TestResolveCardRef(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+	board, _ := db.CreateBoard("sprint", "", wsID)
+	columns, _ := db.ListColumns(board.ID)
+	card, _ := db.CreateCard(columns[0].ID, "Fix login bug", model.PriorityMedium)
+
+	for _, ref := range []string{card.ID, "fix login bug", card.ID[:model.MinCardIDPrefix]} {
+		got, boardID, err := db.ResolveCardRef(ref)
+		if err != nil {
+			t.Fatalf("ResolveCardRef(%q): %v", ref, err)
+		}
+		if got.ID != card.ID || boardID != board.ID {
+			t.Errorf("ResolveCardRef(%q) = %s on %s", ref, got.ID, boardID)
+		}
+	}
+
+	if _, _, err := db.ResolveCardRef(card.ID[:model.MinCardIDPrefix-1]); err == nil {
+		t.Error("expected a prefix shorter than the minimum not to resolve")
+	}
+
+	db.ArchiveCard(card.ID)
+	if _, _, err := db.ResolveCardRef(card.ID); err == nil {
+		t.Error("expected archived card not to resolve")
+	}
+}
+
+func TestUpdateCardSyncsDescriptionLinks(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+	board, _ := db.CreateBoard("Sprint", "", wsID)
+	cols, _ := db.ListColumns(board.ID)
+	note, _ := db.CreateNote("Standup", "standup", "- [ ] Send agenda", wsID)
+	design, _ := db.CreateNote("Design", "design", "", wsID)
+
+	promoted, _ := db.PromoteCheckboxes(note.ID, cols[0].ID)
+	card := promoted[0]
+	card.Description += "\nSee [[design]] and [[board:Sprint]]"
+	if err := db.UpdateCard(card); err != nil {
+		t.Fatalf("updating card: %v", err)
+	}
+
+	links, _ := db.GetForwardLinks("card", card.ID)
+	if len(links) != 3 {
+		t.Fatalf("expected 3 links from the card, got %+v", links)
+	}
+	back, _ := db.GetBacklinks("note", design.ID)
+	if len(back) != 1 || back[0].SourceID != card.ID {
+		t.Errorf("expected a backlink from the card, got %+v", back)
+	}
+	back, _ = db.GetBacklinks("note", note.ID)
+	if len(back) != 1 || back[0].Fragment != "^card-"+card.ID[:8] || back[0].Context != "- [ ] Send agenda" {
+		t.Errorf("the promoted checkbox link should keep its context, got %+v", back)
+	}
+
+	card.Description = "Nothing linked"
+	db.UpdateCard(card)
+	links, _ = db.GetForwardLinks("card", card.ID)
+	if len(links) != 1 || links[0].TargetID != note.ID {
+		t.Errorf("only the promoted link should remain, got %+v", links)
+	}
+}
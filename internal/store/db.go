@@ -12,7 +12,8 @@ import (
 )
 
 type DB struct {
-	conn *sql.DB
+	conn      *sql.DB
+	attachDir string
 }
 
 func Open() (*DB, error) {
@@ -30,7 +31,7 @@ func Open() (*DB, error) {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
 
-	db := &DB{conn: conn}
+	db := &DB{conn: conn, attachDir: filepath.Join(filepath.Dir(dbPath), "attachments")}
 	if err := db.migrate(); err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("running migrations: %w", err)
@@ -39,6 +40,8 @@ func Open() (*DB, error) {
 	return db, nil
 }
 
+// OpenWithPath opens the database at path. Attachments are stored next to
+// it, except for in-memory databases, which need SetAttachmentDir.
 func OpenWithPath(path string) (*DB, error) {
 	conn, err := sql.Open("sqlite", path+"?_pragma=journal_mode(wal)&_pragma=foreign_keys(on)")
 	if err != nil {
@@ -46,6 +49,9 @@ func OpenWithPath(path string) (*DB, error) {
 	}
 
 	db := &DB{conn: conn}
+	if path != ":memory:" {
+		db.attachDir = filepath.Join(filepath.Dir(path), "attachments")
+	}
 	if err := db.migrate(); err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("running migrations: %w", err)
@@ -58,6 +64,24 @@ func (d *DB) Close() error {
 	return d.conn.Close()
 }
 
+// ChangeStamp summarizes the notes, cards, boards, workspaces and links so
+// that a caller polling it can tell when any of them changed, including
+// from another process.
+func (d *DB) ChangeStamp() (string, error) {
+	var stamp string
+	err := d.conn.QueryRow(`SELECT
+		(SELECT COUNT(*) || '/' || IFNULL(MAX(updated_at), '') || '/' || COUNT(archived_at) || '/' || COUNT(deleted_at) FROM notes) || ' ' ||
+		(SELECT COUNT(*) || '/' || IFNULL(MAX(updated_at), '') || '/' || COUNT(archived_at) || '/' || COUNT(deleted_at) FROM cards) || ' ' ||
+		(SELECT COUNT(*) || '/' || IFNULL(MAX(updated_at), '') FROM boards) || ' ' ||
+		(SELECT COUNT(*) || '/' || IFNULL(MAX(updated_at), '') FROM workspaces) || ' ' ||
+		(SELECT COUNT(*) || '/' || IFNULL(MAX(created_at), '') FROM links)`,
+	).Scan(&stamp)
+	if err != nil {
+		return "", fmt.Errorf("reading change stamp: %w", err)
+	}
+	return stamp, nil
+}
+
 func (d *DB) migrate() error {
 	_, err := d.conn.Exec(`
 		CREATE TABLE IF NOT EXISTS schema_migrations (
@@ -118,6 +142,72 @@ func (d *DB) migrate() error {
 			return err
 		}
 	}
+	if version < 6 {
+		if err := d.migrate006(); err != nil {
+			return err
+		}
+	}
+	if version < 7 {
+		if err := d.migrate007(); err != nil {
+			return err
+		}
+	}
+	if version < 8 {
+		if err := d.migrate008(); err != nil {
+			return err
+		}
+	}
+	if version < 9 {
+		if err := d.migrate009(); err != nil {
+			return err
+		}
+	}
+	if version < 10 {
+		if err := d.migrate010(); err != nil {
+			return err
+		}
+	}
+	if version < 11 {
+		if err := d.migrate011(); err != nil {
+			return err
+		}
+	}
+	if version < 12 {
+		if err := d.migrate012(); err != nil {
+			return err
+		}
+	}
+	if version < 13 {
+		if err := d.migrate013(); err != nil {
+			return err
+		}
+	}
+	if version < 14 {
+		if err := d.migrate014(); err != nil {
+			return err
+		}
+	}
+	if version < 15 {
+		if err := d.migrate015(); err != nil {
+			return err
+		}
+	}
+	if version < 16 {
+		if err := d.migrate016(); err != nil {
+			return err
+		}
+	}
+	if version < 17 {
+		if err := d.migrate017(); err != nil {
+			return err
+		}
+	}
+
+	if version < 18 {
+		if err := d.migrate018(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -330,6 +420,586 @@ func (d *DB) migrate005() error {
 	return tx.Commit()
 }
 
+func (d *DB) migrate006() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// SQLite cannot alter a UNIQUE constraint in place, so the links table
+	// is rebuilt to allow one row per heading or block fragment.
+	schema := `
+		CREATE TABLE links_new (
+			id TEXT PRIMARY KEY,
+			source_type TEXT NOT NULL,
+			source_id TEXT NOT NULL,
+			target_type TEXT NOT NULL,
+			target_id TEXT NOT NULL,
+			fragment TEXT NOT NULL DEFAULT '',
+			context TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(source_type, source_id, target_type, target_id, fragment)
+		);
+
+		INSERT INTO links_new (id, source_type, source_id, target_type, target_id, fragment, context, created_at)
+		SELECT id, source_type, source_id, target_type, target_id, '', context, created_at FROM links;
+
+		DROP TABLE links;
+		ALTER TABLE links_new RENAME TO links;
+
+		CREATE INDEX IF NOT EXISTS idx_links_source ON links(source_type, source_id);
+		CREATE INDEX IF NOT EXISTS idx_links_target ON links(target_type, target_id);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 006: %w", err)
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (6)"); err != nil {
+		return fmt.Errorf("recording migration 006: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate007() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		ALTER TABLE notes ADD COLUMN deleted_at TIMESTAMP;
+		CREATE INDEX IF NOT EXISTS idx_notes_deleted_at ON notes(deleted_at);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 007: %w", err)
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (7)"); err != nil {
+		return fmt.Errorf("recording migration 007: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate008() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS card_moves (
+			id TEXT PRIMARY KEY,
+			card_id TEXT NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+			from_column TEXT NOT NULL,
+			to_column TEXT NOT NULL,
+			done INTEGER NOT NULL DEFAULT 0,
+			moved_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_card_moves_moved_at ON card_moves(moved_at);
+		CREATE INDEX IF NOT EXISTS idx_card_moves_card_id ON card_moves(card_id);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 008: %w", err)
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (8)"); err != nil {
+		return fmt.Errorf("recording migration 008: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate009() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS note_aliases (
+			alias TEXT NOT NULL COLLATE NOCASE PRIMARY KEY,
+			note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_note_aliases_note_id ON note_aliases(note_id);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 009: %w", err)
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (9)"); err != nil {
+		return fmt.Errorf("recording migration 009: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate010() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS note_properties (
+			note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			key TEXT NOT NULL COLLATE NOCASE,
+			position INTEGER NOT NULL DEFAULT 0,
+			type TEXT NOT NULL,
+			value TEXT NOT NULL,
+			num REAL,
+			PRIMARY KEY (note_id, key, position)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_note_properties_key ON note_properties(key, value);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 010: %w", err)
+	}
+
+	rows, err := tx.Query("SELECT id, body FROM notes")
+	if err != nil {
+		return fmt.Errorf("reading notes for migration 010: %w", err)
+	}
+	bodies := make(map[string]string)
+	for rows.Next() {
+		var id, body string
+		if err := rows.Scan(&id, &body); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning note for migration 010: %w", err)
+		}
+		bodies[id] = body
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading notes for migration 010: %w", err)
+	}
+	for id, body := range bodies {
+		if err := syncNoteProperties(tx, id, body); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (10)"); err != nil {
+		return fmt.Errorf("recording migration 010: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate011() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS note_tags (
+			note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			tag TEXT NOT NULL COLLATE NOCASE,
+			PRIMARY KEY (note_id, tag)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_note_tags_tag ON note_tags(tag);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 011: %w", err)
+	}
+
+	rows, err := tx.Query("SELECT id, body, tags FROM notes")
+	if err != nil {
+		return fmt.Errorf("reading notes for migration 011: %w", err)
+	}
+	var notes []*model.Note
+	for rows.Next() {
+		n := &model.Note{}
+		if err := rows.Scan(&n.ID, &n.Body, &n.Tags); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning note for migration 011: %w", err)
+		}
+		notes = append(notes, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading notes for migration 011: %w", err)
+	}
+	for _, n := range notes {
+		if err := syncNoteTags(tx, n); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (11)"); err != nil {
+		return fmt.Errorf("recording migration 011: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate012() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS attachments (
+			id TEXT PRIMARY KEY,
+			hash TEXT NOT NULL,
+			name TEXT NOT NULL,
+			mime_type TEXT NOT NULL DEFAULT '',
+			size INTEGER NOT NULL DEFAULT 0,
+			note_id TEXT REFERENCES notes(id) ON DELETE CASCADE,
+			card_id TEXT REFERENCES cards(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			CHECK ((note_id IS NULL) <> (card_id IS NULL))
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_attachments_note_id ON attachments(note_id);
+		CREATE INDEX IF NOT EXISTS idx_attachments_card_id ON attachments(card_id);
+		CREATE INDEX IF NOT EXISTS idx_attachments_name ON attachments(name COLLATE NOCASE);
+		CREATE INDEX IF NOT EXISTS idx_attachments_hash ON attachments(hash);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 012: %w", err)
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (12)"); err != nil {
+		return fmt.Errorf("recording migration 012: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate013() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS promoted_cards (
+			note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			marker TEXT NOT NULL,
+			card_id TEXT NOT NULL UNIQUE REFERENCES cards(id) ON DELETE CASCADE,
+			column_id TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (note_id, marker)
+		);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 013: %w", err)
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (13)"); err != nil {
+		return fmt.Errorf("recording migration 013: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate014() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS note_tasks (
+			id TEXT PRIMARY KEY,
+			note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			line INTEGER NOT NULL,
+			text TEXT NOT NULL,
+			checked INTEGER NOT NULL DEFAULT 0,
+			due TEXT NOT NULL DEFAULT '',
+			priority TEXT NOT NULL DEFAULT '',
+			context TEXT NOT NULL DEFAULT ''
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_note_tasks_note_id ON note_tasks(note_id, line);
+		CREATE INDEX IF NOT EXISTS idx_note_tasks_due ON note_tasks(due);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 014: %w", err)
+	}
+
+	rows, err := tx.Query("SELECT id, body FROM notes")
+	if err != nil {
+		return fmt.Errorf("reading notes for migration 014: %w", err)
+	}
+	bodies := make(map[string]string)
+	for rows.Next() {
+		var id, body string
+		if err := rows.Scan(&id, &body); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning note for migration 014: %w", err)
+		}
+		bodies[id] = body
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading notes for migration 014: %w", err)
+	}
+	for id, body := range bodies {
+		if err := syncNoteTasks(tx, id, body); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (14)"); err != nil {
+		return fmt.Errorf("recording migration 014: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate015() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS flashcards (
+			id TEXT PRIMARY KEY,
+			note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			line INTEGER NOT NULL,
+			front TEXT NOT NULL,
+			back TEXT NOT NULL,
+			reps INTEGER NOT NULL DEFAULT 0,
+			interval INTEGER NOT NULL DEFAULT 0,
+			ease REAL NOT NULL DEFAULT 2.5,
+			due TEXT NOT NULL,
+			last_reviewed TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_flashcards_note_id ON flashcards(note_id, line);
+		CREATE INDEX IF NOT EXISTS idx_flashcards_due ON flashcards(due);
+
+		CREATE TABLE IF NOT EXISTS flashcard_reviews (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			flashcard_id TEXT NOT NULL,
+			grade INTEGER NOT NULL,
+			reviewed_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_flashcard_reviews_reviewed_at ON flashcard_reviews(reviewed_at);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 015: %w", err)
+	}
+
+	rows, err := tx.Query("SELECT id, body FROM notes")
+	if err != nil {
+		return fmt.Errorf("reading notes for migration 015: %w", err)
+	}
+	bodies := make(map[string]string)
+	for rows.Next() {
+		var id, body string
+		if err := rows.Scan(&id, &body); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning note for migration 015: %w", err)
+		}
+		bodies[id] = body
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading notes for migration 015: %w", err)
+	}
+	for id, body := range bodies {
+		if err := syncNoteFlashcards(tx, id, body); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (15)"); err != nil {
+		return fmt.Errorf("recording migration 015: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate016() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id, description FROM cards WHERE deleted_at IS NULL AND description LIKE '%[[%'")
+	if err != nil {
+		return fmt.Errorf("reading cards for migration 016: %w", err)
+	}
+	var cards []*model.Card
+	for rows.Next() {
+		card := &model.Card{}
+		if err := rows.Scan(&card.ID, &card.Description); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning card for migration 016: %w", err)
+		}
+		cards = append(cards, card)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading cards for migration 016: %w", err)
+	}
+	for _, card := range cards {
+		if err := syncCardLinks(tx, card); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (16)"); err != nil {
+		return fmt.Errorf("recording migration 016: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate017() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS note_terms (
+			note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			term TEXT NOT NULL,
+			count INTEGER NOT NULL,
+			PRIMARY KEY (note_id, term)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_note_terms_term ON note_terms(term);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 017: %w", err)
+	}
+
+	rows, err := tx.Query("SELECT id, title, body FROM notes")
+	if err != nil {
+		return fmt.Errorf("reading notes for migration 017: %w", err)
+	}
+	var notes []*model.Note
+	for rows.Next() {
+		note := &model.Note{}
+		if err := rows.Scan(&note.ID, &note.Title, &note.Body); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning note for migration 017: %w", err)
+		}
+		notes = append(notes, note)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading notes for migration 017: %w", err)
+	}
+	for _, note := range notes {
+		if err := syncNoteTerms(tx, note.ID, note.Title, note.Body); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (17)"); err != nil {
+		return fmt.Errorf("recording migration 017: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) migrate018() error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The relation joins the UNIQUE constraint so the same note can be
+	// linked both plainly and with a relation, which needs another rebuild.
+	schema := `
+		CREATE TABLE links_new (
+			id TEXT PRIMARY KEY,
+			source_type TEXT NOT NULL,
+			source_id TEXT NOT NULL,
+			target_type TEXT NOT NULL,
+			target_id TEXT NOT NULL,
+			fragment TEXT NOT NULL DEFAULT '',
+			relation TEXT NOT NULL DEFAULT '',
+			context TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(source_type, source_id, target_type, target_id, fragment, relation)
+		);
+
+		INSERT INTO links_new (id, source_type, source_id, target_type, target_id, fragment, context, created_at)
+		SELECT id, source_type, source_id, target_type, target_id, fragment, context, created_at FROM links;
+
+		DROP TABLE links;
+		ALTER TABLE links_new RENAME TO links;
+
+		CREATE INDEX IF NOT EXISTS idx_links_source ON links(source_type, source_id);
+		CREATE INDEX IF NOT EXISTS idx_links_target ON links(target_type, target_id);
+		CREATE INDEX IF NOT EXISTS idx_links_relation ON links(relation);
+	`
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("applying migration 018: %w", err)
+	}
+
+	// Re-read the links of notes and cards already written with relations.
+	rows, err := tx.Query(
+		`SELECT 'note', id, body FROM notes WHERE deleted_at IS NULL
+		 UNION ALL SELECT 'card', id, description FROM cards WHERE deleted_at IS NULL`,
+	)
+	if err != nil {
+		return fmt.Errorf("reading links for migration 018: %w", err)
+	}
+	type source struct{ kind, id, text string }
+	var sources []source
+	for rows.Next() {
+		var s source
+		if err := rows.Scan(&s.kind, &s.id, &s.text); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning link source for migration 018: %w", err)
+		}
+		for _, pl := range model.ParseWikilinks(s.text) {
+			if pl.Relation != "" {
+				sources = append(sources, s)
+				break
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading links for migration 018: %w", err)
+	}
+	for _, s := range sources {
+		if s.kind == "card" {
+			err = syncCardLinks(tx, &model.Card{ID: s.id, Description: s.text})
+		} else if _, err = tx.Exec("DELETE FROM links WHERE source_type = 'note' AND source_id = ?", s.id); err == nil {
+			err = insertLinks(tx, "note", s.id, s.text)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (18)"); err != nil {
+		return fmt.Errorf("recording migration 018: %w", err)
+	}
+
+	return tx.Commit()
+}
+
 func dbPath() (string, error) {
 	dataDir := os.Getenv("XDG_DATA_HOME")
 	if dataDir == "" {
@@ -0,0 +1,240 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+type FlashcardFilter struct {
+	WorkspaceID string
+	Tag         string
+}
+
+// ReviewStats summarizes the flashcards of active notes and the review
+// history. A streak counts consecutive days with at least one review,
+// ending today or yesterday.
+type ReviewStats struct {
+	Total         int
+	New           int
+	Due           int
+	DueTomorrow   int
+	ReviewedToday int
+	Streak        int
+	LongestStreak int
+}
+
+// syncNoteFlashcards brings the flashcards of a note in line with body.
+// Cards still present keep their review state; removed cards are dropped.
+func syncNoteFlashcards(tx *sql.Tx, noteID, body string) error {
+	rows, err := tx.Query("SELECT id FROM flashcards WHERE note_id = ?", noteID)
+	if err != nil {
+		return fmt.Errorf("reading note flashcards: %w", err)
+	}
+	stale := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning note flashcard: %w", err)
+		}
+		stale[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading note flashcards: %w", err)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	for _, f := range model.ParseFlashcards(noteID, body) {
+		delete(stale, f.ID)
+		if _, err := tx.Exec(
+			`INSERT INTO flashcards (id, note_id, line, front, back, ease, due) VALUES (?, ?, ?, ?, ?, ?, ?)
+			 ON CONFLICT(id) DO UPDATE SET line = excluded.line, front = excluded.front, back = excluded.back`,
+			f.ID, noteID, f.Line, f.Front, f.Back, model.DefaultEase, today,
+		); err != nil {
+			return fmt.Errorf("saving flashcard: %w", err)
+		}
+	}
+	for id := range stale {
+		if _, err := tx.Exec("DELETE FROM flashcards WHERE id = ?", id); err != nil {
+			return fmt.Errorf("removing flashcard: %w", err)
+		}
+	}
+	return nil
+}
+
+const flashcardColumns = `SELECT f.id, f.note_id, n.title, n.slug, f.line, f.front, f.back,
+		f.reps, f.interval, f.ease, f.due, f.last_reviewed
+	 FROM flashcards f JOIN notes n ON n.id = f.note_id
+	 WHERE n.archived_at IS NULL AND n.deleted_at IS NULL`
+
+func flashcardQuery(filter FlashcardFilter) (string, []any) {
+	query := flashcardColumns
+	var args []any
+	if filter.WorkspaceID != "" {
+		query += " AND n.workspace_id = ?"
+		args = append(args, filter.WorkspaceID)
+	}
+	if filter.Tag != "" {
+		query += ` AND EXISTS (SELECT 1 FROM note_tags nt WHERE nt.note_id = n.id AND (nt.tag = ? OR nt.tag LIKE ? ESCAPE '\'))`
+		args = append(args, model.NormalizeTag(filter.Tag), tagPrefixPattern(filter.Tag))
+	}
+	return query, args
+}
+
+// ListFlashcards lists the flashcards of active notes, grouped by note.
+func (d *DB) ListFlashcards(filter FlashcardFilter) ([]*model.Flashcard, error) {
+	query, args := flashcardQuery(filter)
+	rows, err := d.conn.Query(query+" ORDER BY n.title COLLATE NOCASE, n.id, f.line", args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing flashcards: %w", err)
+	}
+	defer rows.Close()
+	return scanFlashcards(rows)
+}
+
+// ListDueFlashcards lists the flashcards due on the day of now, most
+// overdue first.
+func (d *DB) ListDueFlashcards(filter FlashcardFilter, now time.Time) ([]*model.Flashcard, error) {
+	query, args := flashcardQuery(filter)
+	query += " AND f.due <= ? ORDER BY f.due, n.title COLLATE NOCASE, n.id, f.line"
+	rows, err := d.conn.Query(query, append(args, now.Format("2006-01-02"))...)
+	if err != nil {
+		return nil, fmt.Errorf("listing due flashcards: %w", err)
+	}
+	defer rows.Close()
+	return scanFlashcards(rows)
+}
+
+// ReviewFlashcard grades a recall of the flashcard id, reschedules it with
+// SM-2 and records the review.
+func (d *DB) ReviewFlashcard(id string, grade int, now time.Time) (*model.Flashcard, error) {
+	rows, err := d.conn.Query(flashcardColumns+" AND f.id = ?", id)
+	if err != nil {
+		return nil, fmt.Errorf("getting flashcard: %w", err)
+	}
+	cards, err := scanFlashcards(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("flashcard not found")
+	}
+
+	card := cards[0]
+	if err := card.Review(grade, now); err != nil {
+		return nil, err
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"UPDATE flashcards SET reps = ?, interval = ?, ease = ?, due = ?, last_reviewed = ? WHERE id = ?",
+		card.Reps, card.Interval, card.Ease, card.Due, now.UTC(), card.ID,
+	); err != nil {
+		return nil, fmt.Errorf("updating flashcard: %w", err)
+	}
+	if _, err := tx.Exec(
+		"INSERT INTO flashcard_reviews (flashcard_id, grade, reviewed_at) VALUES (?, ?, ?)",
+		card.ID, grade, now.UTC(),
+	); err != nil {
+		return nil, fmt.Errorf("recording review: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return card, nil
+}
+
+// FlashcardStats counts due and new flashcards and works out the review
+// streaks as of now.
+func (d *DB) FlashcardStats(now time.Time) (*ReviewStats, error) {
+	cards, err := d.ListFlashcards(FlashcardFilter{})
+	if err != nil {
+		return nil, err
+	}
+	stats := &ReviewStats{Total: len(cards)}
+	tomorrow := now.AddDate(0, 0, 1)
+	for _, c := range cards {
+		switch {
+		case c.IsDue(now):
+			stats.Due++
+		case c.IsDue(tomorrow):
+			stats.DueTomorrow++
+		}
+		if c.IsNew() {
+			stats.New++
+		}
+	}
+
+	rows, err := d.conn.Query("SELECT reviewed_at FROM flashcard_reviews ORDER BY reviewed_at")
+	if err != nil {
+		return nil, fmt.Errorf("reading reviews: %w", err)
+	}
+	defer rows.Close()
+
+	today := now.Format("2006-01-02")
+	var days []string
+	for rows.Next() {
+		var at time.Time
+		if err := rows.Scan(&at); err != nil {
+			return nil, fmt.Errorf("scanning review: %w", err)
+		}
+		day := at.In(now.Location()).Format("2006-01-02")
+		if day == today {
+			stats.ReviewedToday++
+		}
+		if len(days) == 0 || days[len(days)-1] != day {
+			days = append(days, day)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	run := 0
+	for i, day := range days {
+		if i > 0 && nextDay(days[i-1]) == day {
+			run++
+		} else {
+			run = 1
+		}
+		stats.LongestStreak = max(stats.LongestStreak, run)
+	}
+	if len(days) > 0 {
+		last := days[len(days)-1]
+		if last == today || nextDay(last) == today {
+			stats.Streak = run
+		}
+	}
+	return stats, nil
+}
+
+func nextDay(day string) string {
+	t, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return ""
+	}
+	return t.AddDate(0, 0, 1).Format("2006-01-02")
+}
+
+func scanFlashcards(rows *sql.Rows) ([]*model.Flashcard, error) {
+	var cards []*model.Flashcard
+	for rows.Next() {
+		f := &model.Flashcard{}
+		if err := rows.Scan(&f.ID, &f.NoteID, &f.NoteTitle, &f.NoteSlug, &f.Line, &f.Front, &f.Back,
+			&f.Reps, &f.Interval, &f.Ease, &f.Due, &f.LastReviewed); err != nil {
+			return nil, fmt.Errorf("scanning flashcard: %w", err)
+		}
+		cards = append(cards, f)
+	}
+	return cards, rows.Err()
+}
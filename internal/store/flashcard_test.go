@@ -0,0 +1,67 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlashcardSync(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	note, _ := db.CreateNote("Go", "go", "Goroutine? :: Lightweight thread\nChannel? :: Typed pipe", wsID)
+	cards, err := db.ListFlashcards(FlashcardFilter{})
+	if err != nil {
+		t.Fatalf("listing flashcards: %v", err)
+	}
+	if len(cards) != 2 || cards[0].NoteSlug != "go" || !cards[0].IsNew() {
+		t.Fatalf("unexpected flashcards: %+v", cards)
+	}
+
+	now := time.Now()
+	if _, err := db.ReviewFlashcard(cards[0].ID, 4, now); err != nil {
+		t.Fatalf("reviewing: %v", err)
+	}
+
+	note.Body = "Intro\n\nGoroutine? :: A lightweight thread"
+	db.UpdateNote(note)
+	cards, _ = db.ListFlashcards(FlashcardFilter{})
+	if len(cards) != 1 || cards[0].Back != "A lightweight thread" || cards[0].Reps != 1 || cards[0].Line != 2 {
+		t.Errorf("edited card should keep its schedule: %+v", cards)
+	}
+
+	due, _ := db.ListDueFlashcards(FlashcardFilter{}, now)
+	if len(due) != 0 {
+		t.Errorf("reviewed card should not be due today: %+v", due)
+	}
+	due, _ = db.ListDueFlashcards(FlashcardFilter{}, now.AddDate(0, 0, 1))
+	if len(due) != 1 {
+		t.Errorf("reviewed card should be due tomorrow: %+v", due)
+	}
+
+	if _, err := db.ReviewFlashcard("missing", 3, now); err == nil {
+		t.Error("expected error for unknown flashcard")
+	}
+}
+
+func TestFlashcardStats(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+	db.CreateNote("Deck", "deck", "A :: 1\nB :: 2\nC :: 3", wsID)
+	cards, _ := db.ListFlashcards(FlashcardFilter{})
+
+	now := time.Now()
+	for _, daysAgo := range []int{6, 5, 2, 1} {
+		db.ReviewFlashcard(cards[0].ID, 5, now.AddDate(0, 0, -daysAgo))
+	}
+	db.ReviewFlashcard(cards[1].ID, 3, now)
+
+	stats, err := db.FlashcardStats(now)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.Total != 3 || stats.New != 1 || stats.Due != 1 || stats.DueTomorrow != 1 ||
+		stats.ReviewedToday != 1 || stats.Streak != 3 || stats.LongestStreak != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -21,34 +21,55 @@ func (d *DB) SyncNoteLinks(note *model.Note) error {
 		return fmt.Errorf("clearing old links: %w", err)
 	}
 
-	parsed := model.ParseWikilinks(note.Body)
-	for _, pl := range parsed {
+	if err := insertLinks(tx, "note", note.ID, note.Body); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// syncCardLinks replaces the links made by a card's description. The link
+// a promoted card keeps to its checkbox is left alone.
+func syncCardLinks(tx *sql.Tx, card *model.Card) error {
+	if _, err := tx.Exec(
+		`DELETE FROM links WHERE source_type = 'card' AND source_id = ?
+		 AND NOT EXISTS (SELECT 1 FROM promoted_cards p
+		                 WHERE p.card_id = links.source_id AND p.note_id = links.target_id AND '^' || p.marker = links.fragment)`,
+		card.ID,
+	); err != nil {
+		return fmt.Errorf("clearing old card links: %w", err)
+	}
+	return insertLinks(tx, "card", card.ID, card.Description)
+}
+
+// insertLinks records the wikilinks in text as links from the given source.
+// Note references are resolved to IDs; other targets keep their reference.
+func insertLinks(tx *sql.Tx, sourceType, sourceID, text string) error {
+	for _, pl := range model.ParseWikilinks(text) {
+		if pl.TargetRef == "" {
+			continue
+		}
 		targetID := pl.TargetRef
 		if pl.TargetType == "note" {
-			var id string
-			err := tx.QueryRow(
-				"SELECT id FROM notes WHERE slug = ? AND archived_at IS NULL", pl.TargetRef,
-			).Scan(&id)
-			if err == nil {
+			if id, err := resolveNoteRef(tx, pl.TargetRef); err == nil {
 				targetID = id
 			}
 		}
 
 		if _, err := tx.Exec(
-			`INSERT OR IGNORE INTO links (id, source_type, source_id, target_type, target_id, context)
-			 VALUES (?, 'note', ?, ?, ?, ?)`,
-			uuid.New().String(), note.ID, pl.TargetType, targetID, pl.Context,
+			`INSERT OR IGNORE INTO links (id, source_type, source_id, target_type, target_id, fragment, relation, context)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), sourceType, sourceID, pl.TargetType, targetID, pl.Fragment(), pl.Relation, pl.Context,
 		); err != nil {
 			return fmt.Errorf("inserting link: %w", err)
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 func (d *DB) GetForwardLinks(sourceType, sourceID string) ([]*model.Link, error) {
 	rows, err := d.conn.Query(
-		`SELECT id, source_type, source_id, target_type, target_id, context, created_at
+		`SELECT id, source_type, source_id, target_type, target_id, fragment, relation, context, created_at
 		 FROM links WHERE source_type = ? AND source_id = ?
 		 ORDER BY created_at`,
 		sourceType, sourceID,
@@ -62,7 +83,7 @@ func (d *DB) GetForwardLinks(sourceType, sourceID string) ([]*model.Link, error)
 
 func (d *DB) GetBacklinks(targetType, targetID string) ([]*model.Link, error) {
 	rows, err := d.conn.Query(
-		`SELECT id, source_type, source_id, target_type, target_id, context, created_at
+		`SELECT id, source_type, source_id, target_type, target_id, fragment, relation, context, created_at
 		 FROM links WHERE target_type = ? AND target_id = ?
 		 ORDER BY created_at`,
 		targetType, targetID,
@@ -76,7 +97,7 @@ func (d *DB) GetBacklinks(targetType, targetID string) ([]*model.Link, error) {
 
 func (d *DB) ListAllLinks() ([]*model.Link, error) {
 	rows, err := d.conn.Query(
-		`SELECT id, source_type, source_id, target_type, target_id, context, created_at
+		`SELECT id, source_type, source_id, target_type, target_id, fragment, relation, context, created_at
 		 FROM links ORDER BY created_at`,
 	)
 	if err != nil {
@@ -92,7 +113,7 @@ func scanLinks(rows *sql.Rows) ([]*model.Link, error) {
 		link := &model.Link{}
 		if err := rows.Scan(
 			&link.ID, &link.SourceType, &link.SourceID,
-			&link.TargetType, &link.TargetID, &link.Context, &link.CreatedAt,
+			&link.TargetType, &link.TargetID, &link.Fragment, &link.Relation, &link.Context, &link.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scanning link: %w", err)
 		}
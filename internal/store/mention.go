@@ -0,0 +1,147 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// Mention is an unlinked mention of a note in another note or in a card's
+// description.
+type Mention struct {
+	model.Mention
+	SourceType string // "note" or "card"
+	SourceID   string
+	Source     string // the note's slug or the card's title
+	Board      string // the card's board
+}
+
+// mentionNames returns the names a note can be mentioned by: its title and
+// aliases.
+func (d *DB) mentionNames(note *model.Note) ([]string, error) {
+	aliases, err := d.ListNoteAliases(note.ID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{note.Title}, aliases...), nil
+}
+
+// FindMentions lists the plain-text mentions of a note's title or aliases in
+// the other active notes, ordered by title, and in card descriptions,
+// ordered by board.
+func (d *DB) FindMentions(note *model.Note) ([]*Mention, error) {
+	names, err := d.mentionNames(note)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := d.ListNotes()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return strings.ToLower(notes[i].Title) < strings.ToLower(notes[j].Title)
+	})
+	mentions := []*Mention{}
+	for _, n := range notes {
+		if n.ID == note.ID {
+			continue
+		}
+		for _, m := range model.FindMentions(n.Body, names) {
+			mentions = append(mentions, &Mention{Mention: m, SourceType: "note", SourceID: n.ID, Source: n.Slug})
+		}
+	}
+
+	boards, err := d.ListBoards()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range boards {
+		cards, err := d.ListBoardCards(b.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cards {
+			for _, m := range model.FindMentions(c.Description, names) {
+				mentions = append(mentions, &Mention{Mention: m, SourceType: "card", SourceID: c.ID, Source: c.Title, Board: b.Name})
+			}
+		}
+	}
+	return mentions, nil
+}
+
+// LinkMentions turns the given mentions of a note into [[slug|text]] links
+// and updates the links of every note and card it changes. Mentions that
+// are no longer in their source are skipped. It returns how many mentions
+// were linked.
+func (d *DB) LinkMentions(note *model.Note, mentions []*Mention) (int, error) {
+	names, err := d.mentionNames(note)
+	if err != nil {
+		return 0, err
+	}
+
+	type source struct{ kind, id string }
+	wanted := make(map[source]map[int]string)
+	var order []source
+	for _, m := range mentions {
+		s := source{m.SourceType, m.SourceID}
+		if wanted[s] == nil {
+			wanted[s] = make(map[int]string)
+			order = append(order, s)
+		}
+		wanted[s][m.Start] = m.Text
+	}
+
+	// current keeps the mentions still found at the same place.
+	current := func(s source, text string) []model.Mention {
+		var keep []model.Mention
+		for _, m := range model.FindMentions(text, names) {
+			if t, ok := wanted[s][m.Start]; ok && t == m.Text {
+				keep = append(keep, m)
+			}
+		}
+		return keep
+	}
+
+	linked := 0
+	for _, s := range order {
+		switch s.kind {
+		case "note":
+			n, err := d.GetNote(s.id)
+			if err != nil {
+				return linked, err
+			}
+			found := current(s, n.Body)
+			if len(found) == 0 {
+				continue
+			}
+			n.Body = model.LinkMentions(n.Body, note.Slug, found)
+			if err := d.UpdateNote(n); err != nil {
+				return linked, err
+			}
+			if err := d.SyncNoteLinks(n); err != nil {
+				return linked, err
+			}
+			linked += len(found)
+		case "card":
+			c, err := d.GetCard(s.id)
+			if err != nil {
+				return linked, err
+			}
+			found := current(s, c.Description)
+			if len(found) == 0 {
+				continue
+			}
+			c.Description = model.LinkMentions(c.Description, note.Slug, found)
+			if err := d.UpdateCard(c); err != nil {
+				return linked, err
+			}
+			linked += len(found)
+		default:
+			return linked, fmt.Errorf("unknown mention source %q", s.kind)
+		}
+	}
+	return linked, nil
+}
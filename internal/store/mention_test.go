@@ -0,0 +1,63 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+func TestFindAndLinkMentions(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	target, _ := db.CreateNote("Query Planning", "query-planning", "How the planner works.", wsID)
+	db.AddNoteAlias(target.ID, "QP")
+	meeting, _ := db.CreateNote("Meeting", "meeting", "Talked about query planning.\nQP again, and [[query-planning]].", wsID)
+	db.CreateNote("Other", "other", "Nothing relevant.", wsID)
+
+	_, col := createTestBoardWithColumn(t, db)
+	card, _ := db.CreateCard(col.ID, "Speed up search", model.PriorityMedium)
+	card.Description = "Check the query planning output."
+	db.UpdateCard(card)
+
+	mentions, err := db.FindMentions(target)
+	if err != nil {
+		t.Fatalf("FindMentions: %v", err)
+	}
+	if len(mentions) != 3 {
+		t.Fatalf("expected 3 mentions, got %+v", mentions)
+	}
+	if mentions[0].Source != "meeting" || mentions[1].Text != "QP" || mentions[2].SourceType != "card" {
+		t.Errorf("unexpected mentions: %+v %+v %+v", mentions[0], mentions[1], mentions[2])
+	}
+
+	linked, err := db.LinkMentions(target, []*Mention{mentions[0], mentions[2]})
+	if err != nil || linked != 2 {
+		t.Fatalf("LinkMentions = %d, %v", linked, err)
+	}
+
+	meeting, _ = db.GetNote(meeting.ID)
+	if meeting.Body != "Talked about [[query-planning|query planning]].\nQP again, and [[query-planning]]." {
+		t.Errorf("note body = %q", meeting.Body)
+	}
+	card, _ = db.GetCard(card.ID)
+	if card.Description != "Check the [[query-planning|query planning]] output." {
+		t.Errorf("card description = %q", card.Description)
+	}
+	backlinks, _ := db.GetBacklinks("note", target.ID)
+	if len(backlinks) != 2 {
+		t.Errorf("expected links from the note and the card, got %d", len(backlinks))
+	}
+
+	remaining, _ := db.FindMentions(target)
+	if len(remaining) != 1 || remaining[0].Text != "QP" {
+		t.Errorf("expected only the QP mention left, got %+v", remaining)
+	}
+
+	// A stale mention is skipped rather than corrupting the text.
+	stale := *remaining[0]
+	stale.Start += 2
+	if linked, _ := db.LinkMentions(target, []*Mention{&stale}); linked != 0 {
+		t.Errorf("stale mention should not be linked, got %d", linked)
+	}
+}
@@ -29,31 +29,76 @@ func (d *DB) CreateNote(title, slug, body, workspaceID string) (*model.Note, err
 		UpdatedAt:   now,
 	}
 
-	_, err := d.conn.Exec(
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSlugNotAlias(tx, note.ID, slug); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(
 		`INSERT INTO notes (id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at)
 		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		note.ID, note.Title, note.Slug, note.Body, note.Tags, 0, note.WorkspaceID, note.CreatedAt, note.UpdatedAt,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			var archivedAt, deletedAt *time.Time
+			if tx.QueryRow(
+				"SELECT archived_at, deleted_at FROM notes WHERE slug = ?", slug,
+			).Scan(&archivedAt, &deletedAt) == nil {
+				switch {
+				case deletedAt != nil:
+					return nil, fmt.Errorf("note with slug %q already exists in the trash", slug)
+				case archivedAt != nil:
+					return nil, fmt.Errorf("note with slug %q already exists and is archived", slug)
+				}
+			}
 			return nil, fmt.Errorf("note with slug %q already exists", slug)
 		}
 		return nil, fmt.Errorf("inserting note: %w", err)
 	}
+	if err := syncNoteIndexes(tx, note); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing note: %w", err)
+	}
 
 	return note, nil
 }
 
+// syncNoteIndexes rebuilds everything derived from a note's body: its
+// properties, tags, tasks, flashcards and indexed terms. Every write to
+// notes.body goes through it.
+func syncNoteIndexes(tx *sql.Tx, note *model.Note) error {
+	if err := syncNoteProperties(tx, note.ID, note.Body); err != nil {
+		return err
+	}
+	if err := syncNoteTags(tx, note); err != nil {
+		return err
+	}
+	if err := syncNoteTasks(tx, note.ID, note.Body); err != nil {
+		return err
+	}
+	if err := syncNoteFlashcards(tx, note.ID, note.Body); err != nil {
+		return err
+	}
+	return syncNoteTerms(tx, note.ID, note.Title, note.Body)
+}
+
 func (d *DB) GetNote(id string) (*model.Note, error) {
 	note := &model.Note{}
 	var pinned int
 	var wsID *string
 	err := d.conn.QueryRow(
-		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at
-		 FROM notes WHERE id = ? AND archived_at IS NULL`,
+		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE id = ? AND archived_at IS NULL AND deleted_at IS NULL`,
 		id,
 	).Scan(&note.ID, &note.Title, &note.Slug, &note.Body, &note.Tags,
-		&pinned, &wsID, &note.CreatedAt, &note.UpdatedAt, &note.ArchivedAt)
+		&pinned, &wsID, &note.CreatedAt, &note.UpdatedAt, &note.ArchivedAt, &note.DeletedAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("note not found")
 	}
@@ -72,11 +117,11 @@ func (d *DB) GetNoteBySlug(slug string) (*model.Note, error) {
 	var pinned int
 	var wsID *string
 	err := d.conn.QueryRow(
-		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at
-		 FROM notes WHERE slug = ? AND archived_at IS NULL`,
+		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE slug = ? AND archived_at IS NULL AND deleted_at IS NULL`,
 		slug,
 	).Scan(&note.ID, &note.Title, &note.Slug, &note.Body, &note.Tags,
-		&pinned, &wsID, &note.CreatedAt, &note.UpdatedAt, &note.ArchivedAt)
+		&pinned, &wsID, &note.CreatedAt, &note.UpdatedAt, &note.ArchivedAt, &note.DeletedAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("note %q not found", slug)
 	}
@@ -92,8 +137,8 @@ func (d *DB) GetNoteBySlug(slug string) (*model.Note, error) {
 
 func (d *DB) ListNotes() ([]*model.Note, error) {
 	rows, err := d.conn.Query(
-		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at
-		 FROM notes WHERE archived_at IS NULL
+		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE archived_at IS NULL AND deleted_at IS NULL
 		 ORDER BY pinned DESC, updated_at DESC`,
 	)
 	if err != nil {
@@ -106,11 +151,12 @@ func (d *DB) ListNotes() ([]*model.Note, error) {
 func (d *DB) SearchNotes(query string) ([]*model.Note, error) {
 	search := "%" + strings.ToLower(query) + "%"
 	rows, err := d.conn.Query(
-		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at
-		 FROM notes WHERE archived_at IS NULL
-		 AND (LOWER(title) LIKE ? OR LOWER(body) LIKE ? OR LOWER(tags) LIKE ?)
+		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE archived_at IS NULL AND deleted_at IS NULL
+		 AND (LOWER(title) LIKE ? OR LOWER(body) LIKE ? OR LOWER(tags) LIKE ?
+		   OR EXISTS (SELECT 1 FROM note_aliases a WHERE a.note_id = notes.id AND LOWER(a.alias) LIKE ?))
 		 ORDER BY updated_at DESC`,
-		search, search, search,
+		search, search, search, search,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("searching notes: %w", err)
@@ -121,8 +167,8 @@ func (d *DB) SearchNotes(query string) ([]*model.Note, error) {
 
 func (d *DB) ListNotesByWorkspace(workspaceID string) ([]*model.Note, error) {
 	rows, err := d.conn.Query(
-		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at
-		 FROM notes WHERE workspace_id = ? AND archived_at IS NULL
+		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE workspace_id = ? AND archived_at IS NULL AND deleted_at IS NULL
 		 ORDER BY pinned DESC, updated_at DESC`,
 		workspaceID,
 	)
@@ -135,7 +181,7 @@ func (d *DB) ListNotesByWorkspace(workspaceID string) ([]*model.Note, error) {
 
 func (d *DB) SetNoteWorkspace(noteID, workspaceID string) error {
 	result, err := d.conn.Exec(
-		"UPDATE notes SET workspace_id = ?, updated_at = ? WHERE id = ? AND archived_at IS NULL",
+		"UPDATE notes SET workspace_id = ?, updated_at = ? WHERE id = ? AND archived_at IS NULL AND deleted_at IS NULL",
 		workspaceID, time.Now().UTC(), noteID,
 	)
 	if err != nil {
@@ -151,18 +197,21 @@ func (d *DB) SetNoteWorkspace(noteID, workspaceID string) error {
 	return nil
 }
 
+// ListNotesByTag lists active notes tagged with tag or any tag nested below
+// it, from either the tags column or inline #tags.
 func (d *DB) ListNotesByTag(tag string) ([]*model.Note, error) {
-	notes, err := d.ListNotes()
+	rows, err := d.conn.Query(
+		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE archived_at IS NULL AND deleted_at IS NULL
+		 AND EXISTS (SELECT 1 FROM note_tags t WHERE t.note_id = notes.id AND (t.tag = ? OR t.tag LIKE ? ESCAPE '\'))
+		 ORDER BY pinned DESC, updated_at DESC`,
+		model.NormalizeTag(tag), tagPrefixPattern(tag),
+	)
 	if err != nil {
-		return nil, err
-	}
-	var filtered []*model.Note
-	for _, n := range notes {
-		if n.HasTag(tag) {
-			filtered = append(filtered, n)
-		}
+		return nil, fmt.Errorf("listing notes by tag: %w", err)
 	}
-	return filtered, nil
+	defer rows.Close()
+	return scanNotes(rows)
 }
 
 func (d *DB) UpdateNote(note *model.Note) error {
@@ -170,22 +219,37 @@ func (d *DB) UpdateNote(note *model.Note) error {
 		return err
 	}
 
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSlugNotAlias(tx, note.ID, note.Slug); err != nil {
+		return err
+	}
 	note.UpdatedAt = time.Now().UTC()
-	_, err := d.conn.Exec(
+	_, err = tx.Exec(
 		`UPDATE notes SET title = ?, slug = ?, body = ?, tags = ?, pinned = ?, workspace_id = ?, updated_at = ?
-		 WHERE id = ? AND archived_at IS NULL`,
+		 WHERE id = ? AND archived_at IS NULL AND deleted_at IS NULL`,
 		note.Title, note.Slug, note.Body, note.Tags, boolToInt(note.Pinned), note.WorkspaceID, note.UpdatedAt, note.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("updating note: %w", err)
 	}
-	return nil
+	if err := syncNoteIndexes(tx, note); err != nil {
+		return err
+	}
+	if err := syncPromotedCards(tx, note.ID, note.Body); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (d *DB) ArchiveNote(id string) error {
 	now := time.Now().UTC()
 	result, err := d.conn.Exec(
-		"UPDATE notes SET archived_at = ?, updated_at = ? WHERE id = ? AND archived_at IS NULL",
+		"UPDATE notes SET archived_at = ?, updated_at = ? WHERE id = ? AND archived_at IS NULL AND deleted_at IS NULL",
 		now, now, id,
 	)
 	if err != nil {
@@ -203,7 +267,7 @@ func (d *DB) ArchiveNote(id string) error {
 
 func (d *DB) ResolveNoteID(prefix string) (string, error) {
 	rows, err := d.conn.Query(
-		"SELECT id FROM notes WHERE archived_at IS NULL",
+		"SELECT id FROM notes WHERE archived_at IS NULL AND deleted_at IS NULL",
 	)
 	if err != nil {
 		return "", fmt.Errorf("listing notes: %w", err)
@@ -231,18 +295,181 @@ func (d *DB) ResolveNoteID(prefix string) (string, error) {
 	}
 }
 
+func (d *DB) UnarchiveNote(id string) error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var slug string
+	err = tx.QueryRow(
+		"SELECT slug FROM notes WHERE id = ? AND archived_at IS NOT NULL AND deleted_at IS NULL", id,
+	).Scan(&slug)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("note not found or not archived")
+	}
+	if err != nil {
+		return fmt.Errorf("querying note: %w", err)
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE notes SET archived_at = NULL, updated_at = ? WHERE id = ?", time.Now().UTC(), id,
+	); err != nil {
+		return fmt.Errorf("unarchiving note: %w", err)
+	}
+	// Links written while the note was archived point at its slug; reclaim them.
+	if _, err := tx.Exec(
+		"UPDATE OR IGNORE links SET target_id = ? WHERE target_type = 'note' AND target_id = ?",
+		id, slug,
+	); err != nil {
+		return fmt.Errorf("reattaching backlinks: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) ListArchivedNotes() ([]*model.Note, error) {
+	rows, err := d.conn.Query(
+		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE archived_at IS NOT NULL AND deleted_at IS NULL
+		 ORDER BY archived_at DESC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing archived notes: %w", err)
+	}
+	defer rows.Close()
+	return scanNotes(rows)
+}
+
+func (d *DB) ListTrashedNotes() ([]*model.Note, error) {
+	rows, err := d.conn.Query(
+		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE deleted_at IS NOT NULL
+		 ORDER BY deleted_at DESC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing trashed notes: %w", err)
+	}
+	defer rows.Close()
+	return scanNotes(rows)
+}
+
 func (d *DB) DeleteNote(id string) error {
-	result, err := d.conn.Exec("DELETE FROM notes WHERE id = ?", id)
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var slug string
+	err = tx.QueryRow("SELECT slug FROM notes WHERE id = ? AND deleted_at IS NULL", id).Scan(&slug)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("note not found")
+	}
 	if err != nil {
+		return fmt.Errorf("querying note: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if _, err := tx.Exec(
+		"UPDATE notes SET deleted_at = ?, updated_at = ? WHERE id = ?", now, now, id,
+	); err != nil {
 		return fmt.Errorf("deleting note: %w", err)
 	}
-	rows, err := result.RowsAffected()
+	if err := detachNoteLinks(tx, id, slug); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (d *DB) RestoreNote(id string) error {
+	tx, err := d.conn.Begin()
 	if err != nil {
-		return fmt.Errorf("checking rows affected: %w", err)
+		return fmt.Errorf("beginning transaction: %w", err)
 	}
-	if rows == 0 {
+	defer tx.Rollback()
+
+	note := &model.Note{ID: id}
+	err = tx.QueryRow(
+		"SELECT slug, body FROM notes WHERE id = ? AND deleted_at IS NOT NULL", id,
+	).Scan(&note.Slug, &note.Body)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("note not found in trash")
+	}
+	if err != nil {
+		return fmt.Errorf("querying note: %w", err)
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE notes SET deleted_at = NULL, updated_at = ? WHERE id = ?", time.Now().UTC(), id,
+	); err != nil {
+		return fmt.Errorf("restoring note: %w", err)
+	}
+	// Links written while the note was gone point at its slug; reclaim them.
+	if _, err := tx.Exec(
+		"UPDATE OR IGNORE links SET target_id = ? WHERE target_type = 'note' AND target_id = ?",
+		id, note.Slug,
+	); err != nil {
+		return fmt.Errorf("reattaching backlinks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing restore: %w", err)
+	}
+	return d.SyncNoteLinks(note)
+}
+
+func (d *DB) PurgeNote(id string) error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var slug string
+	err = tx.QueryRow("SELECT slug FROM notes WHERE id = ?", id).Scan(&slug)
+	if err == sql.ErrNoRows {
 		return fmt.Errorf("note not found")
 	}
+	if err != nil {
+		return fmt.Errorf("querying note: %w", err)
+	}
+
+	if err := detachNoteLinks(tx, id, slug); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM publish_log WHERE note_id = ?", id); err != nil {
+		return fmt.Errorf("clearing publish history: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM notes WHERE id = ?", id); err != nil {
+		return fmt.Errorf("purging note: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// detachNoteLinks drops the note's outgoing links and turns links pointing
+// at it back into unresolved slug references, the same shape SyncNoteLinks
+// writes for a target that does not exist.
+func detachNoteLinks(tx *sql.Tx, id, slug string) error {
+	if _, err := tx.Exec(
+		"DELETE FROM links WHERE source_type = 'note' AND source_id = ?", id,
+	); err != nil {
+		return fmt.Errorf("clearing outgoing links: %w", err)
+	}
+	if _, err := tx.Exec(
+		"UPDATE OR IGNORE links SET target_id = ? WHERE target_type = 'note' AND target_id = ?",
+		slug, id,
+	); err != nil {
+		return fmt.Errorf("detaching backlinks: %w", err)
+	}
+	if _, err := tx.Exec(
+		"DELETE FROM links WHERE target_type = 'note' AND target_id = ?", id,
+	); err != nil {
+		return fmt.Errorf("clearing backlinks: %w", err)
+	}
 	return nil
 }
 
@@ -254,7 +481,7 @@ func scanNotes(rows *sql.Rows) ([]*model.Note, error) {
 		var wsID *string
 		if err := rows.Scan(
 			&note.ID, &note.Title, &note.Slug, &note.Body, &note.Tags,
-			&pinned, &wsID, &note.CreatedAt, &note.UpdatedAt, &note.ArchivedAt,
+			&pinned, &wsID, &note.CreatedAt, &note.UpdatedAt, &note.ArchivedAt, &note.DeletedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scanning note: %w", err)
 		}
@@ -1,7 +1,10 @@
 package store
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/jeryldev/kb/internal/model"
 )
 
 func TestMigrate002CreatesNotesTable(t *testing.T) {
@@ -202,6 +205,156 @@ func TestDeleteNote(t *testing.T) {
 	}
 }
 
+func TestUnarchiveNote(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	note, _ := db.CreateNote("Archive Me", "archive-me", "body", wsID)
+	db.ArchiveNote(note.ID)
+
+	archived, err := db.ListArchivedNotes()
+	if err != nil {
+		t.Fatalf("listing archived notes: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != note.ID {
+		t.Fatalf("expected archived note to be listed, got %d notes", len(archived))
+	}
+
+	if err := db.UnarchiveNote(note.ID); err != nil {
+		t.Fatalf("unarchiving note: %v", err)
+	}
+	if _, err := db.GetNote(note.ID); err != nil {
+		t.Errorf("expected unarchived note to be found: %v", err)
+	}
+	if err := db.UnarchiveNote(note.ID); err == nil {
+		t.Error("expected error unarchiving an active note")
+	}
+}
+
+func TestUnarchiveNoteReattachesLinks(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	target, _ := db.CreateNote("Target", "target", "", wsID)
+	db.ArchiveNote(target.ID)
+	source, _ := db.CreateNote("Source", "source", "see [[target]]", wsID)
+	db.SyncNoteLinks(source)
+
+	links, _ := db.GetForwardLinks("note", source.ID)
+	if len(links) != 1 || links[0].TargetID != "target" {
+		t.Fatalf("expected a link to the archived note's slug, got %+v", links)
+	}
+	if err := db.UnarchiveNote(target.ID); err != nil {
+		t.Fatalf("unarchiving note: %v", err)
+	}
+	if links, _ := db.GetBacklinks("note", target.ID); len(links) != 1 {
+		t.Errorf("expected backlink to be reattached, got %d", len(links))
+	}
+}
+
+func TestDeleteNoteDetachesLinks(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	target, _ := db.CreateNote("Target", "target", "see [[other]]", wsID)
+	db.CreateNote("Other", "other", "", wsID)
+	source, _ := db.CreateNote("Source", "source", "see [[target]]", wsID)
+	db.SyncNoteLinks(target)
+	db.SyncNoteLinks(source)
+
+	if err := db.DeleteNote(target.ID); err != nil {
+		t.Fatalf("deleting note: %v", err)
+	}
+
+	if links, _ := db.GetForwardLinks("note", target.ID); len(links) != 0 {
+		t.Errorf("expected outgoing links to be removed, got %d", len(links))
+	}
+	if links, _ := db.GetBacklinks("note", target.ID); len(links) != 0 {
+		t.Errorf("expected backlinks to be detached, got %d", len(links))
+	}
+	links, _ := db.GetForwardLinks("note", source.ID)
+	if len(links) != 1 || links[0].TargetID != "target" {
+		t.Fatalf("expected source link to fall back to slug, got %+v", links)
+	}
+
+	trashed, _ := db.ListTrashedNotes()
+	if len(trashed) != 1 || trashed[0].DeletedAt == nil {
+		t.Fatalf("expected note in trash, got %d notes", len(trashed))
+	}
+}
+
+func TestRestoreNoteReattachesLinks(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	target, _ := db.CreateNote("Target", "target", "see [[other]]", wsID)
+	other, _ := db.CreateNote("Other", "other", "", wsID)
+	source, _ := db.CreateNote("Source", "source", "see [[target]]", wsID)
+	db.SyncNoteLinks(target)
+	db.SyncNoteLinks(source)
+	db.DeleteNote(target.ID)
+
+	if err := db.RestoreNote(target.ID); err != nil {
+		t.Fatalf("restoring note: %v", err)
+	}
+	if _, err := db.GetNote(target.ID); err != nil {
+		t.Fatalf("expected restored note to be found: %v", err)
+	}
+	if links, _ := db.GetBacklinks("note", target.ID); len(links) != 1 {
+		t.Errorf("expected backlink to be reattached, got %d", len(links))
+	}
+	if links, _ := db.GetBacklinks("note", other.ID); len(links) != 1 {
+		t.Errorf("expected outgoing links to be re-synced, got %d", len(links))
+	}
+	if err := db.RestoreNote(target.ID); err == nil {
+		t.Error("expected error restoring a note that is not in the trash")
+	}
+}
+
+func TestPurgeNoteRemovesPublishHistory(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	note, _ := db.CreateNote("Published", "published", "body", wsID)
+	target, err := db.CreatePublishTarget("blog", model.EngineJekyll, t.TempDir(), "_posts", nil)
+	if err != nil {
+		t.Fatalf("creating target: %v", err)
+	}
+	if _, err := db.CreatePublishLog(note.ID, target.ID, "_posts/published.md", ""); err != nil {
+		t.Fatalf("creating publish log: %v", err)
+	}
+
+	if err := db.DeleteNote(note.ID); err != nil {
+		t.Fatalf("deleting note: %v", err)
+	}
+	if err := db.PurgeNote(note.ID); err != nil {
+		t.Fatalf("purging note: %v", err)
+	}
+
+	if trashed, _ := db.ListTrashedNotes(); len(trashed) != 0 {
+		t.Errorf("expected empty trash, got %d notes", len(trashed))
+	}
+	if logs, _ := db.ListPublishLogs(target.ID); len(logs) != 0 {
+		t.Errorf("expected publish history to be removed, got %d rows", len(logs))
+	}
+	if _, err := db.CreateNote("Published", "published", "", wsID); err != nil {
+		t.Errorf("expected slug to be free after purge: %v", err)
+	}
+}
+
+func TestCreateNoteSlugInTrash(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	note, _ := db.CreateNote("Gone", "gone", "", wsID)
+	db.DeleteNote(note.ID)
+
+	_, err := db.CreateNote("Gone", "gone", "", wsID)
+	if err == nil || !strings.Contains(err.Error(), "trash") {
+		t.Errorf("expected trash hint in error, got %v", err)
+	}
+}
+
 func TestSearchNotes(t *testing.T) {
 	db := testDB(t)
 	wsID := testDefaultWSID(t, db)
@@ -285,6 +438,30 @@ func TestGetBacklinks(t *testing.T) {
 	}
 }
 
+func TestSyncNoteLinksRelations(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	adr3, _ := db.CreateNote("ADR 3", "adr-3", "Old decision", wsID)
+	source, _ := db.CreateNote("ADR 4", "adr-4", "supersedes:: [[adr-3]]\nBackground in [[adr-3]].", wsID)
+	db.SyncNoteLinks(source)
+
+	links, err := db.GetBacklinks("note", adr3.ID)
+	if err != nil {
+		t.Fatalf("getting backlinks: %v", err)
+	}
+	if len(links) != 2 {
+		t.Fatalf("expected a plain and a typed link, got %d", len(links))
+	}
+	relations := map[string]bool{}
+	for _, l := range links {
+		relations[l.Relation] = true
+	}
+	if !relations["supersedes"] || !relations[""] {
+		t.Errorf("expected relations supersedes and none, got %v", relations)
+	}
+}
+
 func TestSyncNoteLinksUpdatesOnChange(t *testing.T) {
 	db := testDB(t)
 	wsID := testDefaultWSID(t, db)
@@ -333,3 +510,72 @@ func TestSyncNoteLinksHandlesBrokenLinks(t *testing.T) {
 		t.Errorf("expected target_id to be the slug for broken links, got %q", links[0].TargetID)
 	}
 }
+
+func TestSyncNoteLinksRecordsFragments(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	target, _ := db.CreateNote("Design", "design", "## Storage\n\nSQLite ^db", wsID)
+	source, _ := db.CreateNote("Source", "source",
+		"See [[design#Storage]] and [[design^db]] and ![[design]]", wsID)
+
+	if err := db.SyncNoteLinks(source); err != nil {
+		t.Fatalf("syncing links: %v", err)
+	}
+
+	links, err := db.GetBacklinks("note", target.ID)
+	if err != nil {
+		t.Fatalf("getting backlinks: %v", err)
+	}
+	if len(links) != 3 {
+		t.Fatalf("expected 3 backlinks (one per fragment), got %d", len(links))
+	}
+	fragments := map[string]bool{}
+	for _, l := range links {
+		fragments[l.Fragment] = true
+	}
+	for _, want := range []string{"#Storage", "^db", ""} {
+		if !fragments[want] {
+			t.Errorf("missing backlink with fragment %q", want)
+		}
+	}
+}
+
+func TestSyncNoteLinksSkipsSameNoteHeadings(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	source, _ := db.CreateNote("Source", "source", "Jump to [[#Summary]]", wsID)
+	if err := db.SyncNoteLinks(source); err != nil {
+		t.Fatalf("syncing links: %v", err)
+	}
+
+	links, _ := db.GetForwardLinks("note", source.ID)
+	if len(links) != 0 {
+		t.Errorf("expected no links for same-note heading, got %d", len(links))
+	}
+}
+
+func TestListNotesByTagIncludesChildren(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	db.CreateNote("Inline", "inline", "Indexing in #area/backend/db", wsID)
+	n, _ := db.CreateNote("Column", "column", "body", wsID)
+	n.Tags = "area/frontend"
+	db.UpdateNote(n)
+	db.CreateNote("Other", "other", "About #areas and `#area`", wsID)
+
+	notes, err := db.ListNotesByTag("area")
+	if err != nil {
+		t.Fatalf("listing by tag: %v", err)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 notes under 'area', got %d", len(notes))
+	}
+
+	notes, _ = db.ListNotesByTag("#Area/Backend")
+	if len(notes) != 1 || notes[0].Slug != "inline" {
+		t.Fatalf("expected only 'inline' under area/backend, got %v", notes)
+	}
+}
@@ -0,0 +1,245 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// PromoteCheckboxes turns the unchecked, not yet promoted checkboxes of a
+// note into cards in columnID. Each checkbox gets a block ID marker linking
+// it to its card, so ticking the box and finishing the card stay in sync.
+func (d *DB) PromoteCheckboxes(noteID, columnID string) ([]*model.Card, error) {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var slug, body string
+	err = tx.QueryRow(
+		"SELECT slug, body FROM notes WHERE id = ? AND archived_at IS NULL AND deleted_at IS NULL", noteID,
+	).Scan(&slug, &body)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("note not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting note: %w", err)
+	}
+
+	promoted := make(map[string]bool)
+	rows, err := tx.Query("SELECT marker FROM promoted_cards WHERE note_id = ?", noteID)
+	if err != nil {
+		return nil, fmt.Errorf("listing promoted checkboxes: %w", err)
+	}
+	for rows.Next() {
+		var marker string
+		if err := rows.Scan(&marker); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning promoted checkbox: %w", err)
+		}
+		promoted[marker] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing promoted checkboxes: %w", err)
+	}
+
+	now := time.Now().UTC()
+	lines := strings.Split(body, "\n")
+	var cards []*model.Card
+	for _, box := range model.ParseCheckboxes(body) {
+		if box.Checked || box.Text == "" || promoted[box.Marker] {
+			continue
+		}
+
+		card := &model.Card{
+			ID:        uuid.New().String(),
+			ColumnID:  columnID,
+			Title:     truncateTitle(box.Text, 200),
+			Priority:  model.PriorityMedium,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		marker := box.Marker
+		if marker == "" {
+			marker = "card-" + card.ID[:8]
+			body = model.MarkCheckbox(body, box.Line, marker)
+		}
+		card.Description = fmt.Sprintf("From [[%s#^%s]]", slug, marker)
+
+		if err := insertCard(tx, card); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(
+			"INSERT INTO promoted_cards (note_id, marker, card_id, column_id) VALUES (?, ?, ?, ?)",
+			noteID, marker, card.ID, columnID,
+		); err != nil {
+			return nil, fmt.Errorf("recording promoted checkbox: %w", err)
+		}
+		if _, err := tx.Exec(
+			`INSERT OR IGNORE INTO links (id, source_type, source_id, target_type, target_id, fragment, context)
+			 VALUES (?, 'card', ?, 'note', ?, ?, ?)`,
+			uuid.New().String(), card.ID, noteID, "^"+marker, strings.TrimSpace(lines[box.Line]),
+		); err != nil {
+			return nil, fmt.Errorf("linking card to note: %w", err)
+		}
+		cards = append(cards, card)
+	}
+
+	if len(cards) == 0 {
+		return nil, nil
+	}
+	if err := setNoteBody(tx, noteID, body, now); err != nil {
+		return nil, fmt.Errorf("marking promoted checkboxes: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing promotion: %w", err)
+	}
+	return cards, nil
+}
+
+// syncPromotedCards moves the cards promoted from a note to match its
+// checkboxes: ticked boxes move their card to the board's last column, and
+// unticked boxes move a finished card back to the column it started in.
+func syncPromotedCards(tx *sql.Tx, noteID, body string) error {
+	type promotedCard struct {
+		marker, cardID, startColumn, column, boardID string
+	}
+	rows, err := tx.Query(
+		`SELECT p.marker, p.card_id, p.column_id, c.column_id, col.board_id
+		 FROM promoted_cards p
+		 JOIN cards c ON c.id = p.card_id
+		 JOIN columns col ON col.id = c.column_id
+		 WHERE p.note_id = ? AND c.deleted_at IS NULL AND c.archived_at IS NULL`,
+		noteID,
+	)
+	if err != nil {
+		return fmt.Errorf("listing promoted cards: %w", err)
+	}
+	var cards []promotedCard
+	for rows.Next() {
+		var pc promotedCard
+		if err := rows.Scan(&pc.marker, &pc.cardID, &pc.startColumn, &pc.column, &pc.boardID); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning promoted card: %w", err)
+		}
+		cards = append(cards, pc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("listing promoted cards: %w", err)
+	}
+	if len(cards) == 0 {
+		return nil
+	}
+
+	checked := make(map[string]bool)
+	for _, box := range model.ParseCheckboxes(body) {
+		if box.Marker != "" {
+			checked[box.Marker] = box.Checked
+		}
+	}
+
+	for _, pc := range cards {
+		done, ok := checked[pc.marker]
+		if !ok {
+			continue
+		}
+		var firstColumn, lastColumn string
+		if err := tx.QueryRow(
+			`SELECT (SELECT id FROM columns WHERE board_id = ? ORDER BY position LIMIT 1),
+			        (SELECT id FROM columns WHERE board_id = ? ORDER BY position DESC LIMIT 1)`,
+			pc.boardID, pc.boardID,
+		).Scan(&firstColumn, &lastColumn); err != nil {
+			return fmt.Errorf("finding board columns: %w", err)
+		}
+
+		switch {
+		case done && pc.column != lastColumn:
+			if err := moveCard(tx, pc.cardID, lastColumn); err != nil {
+				return err
+			}
+		case !done && pc.column == lastColumn:
+			target := firstColumn
+			var exists int
+			if pc.startColumn != lastColumn && tx.QueryRow(
+				"SELECT 1 FROM columns WHERE id = ? AND board_id = ?", pc.startColumn, pc.boardID,
+			).Scan(&exists) == nil {
+				target = pc.startColumn
+			}
+			if target == lastColumn {
+				continue
+			}
+			if err := moveCard(tx, pc.cardID, target); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// syncPromotedNote ticks or clears the note checkbox a card was promoted
+// from, depending on whether the card sits in its board's last column.
+func syncPromotedNote(tx *sql.Tx, cardID string) error {
+	var noteID, marker, body string
+	err := tx.QueryRow(
+		`SELECT p.note_id, p.marker, n.body FROM promoted_cards p
+		 JOIN notes n ON n.id = p.note_id
+		 WHERE p.card_id = ?`, cardID,
+	).Scan(&noteID, &marker, &body)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("finding promoted checkbox: %w", err)
+	}
+
+	var done bool
+	err = tx.QueryRow(
+		`SELECT col.position = (SELECT MAX(position) FROM columns WHERE board_id = col.board_id)
+		 FROM cards c JOIN columns col ON col.id = c.column_id
+		 WHERE c.id = ?`, cardID,
+	).Scan(&done)
+	if err != nil {
+		return fmt.Errorf("finding card column: %w", err)
+	}
+
+	body, changed := model.SetCheckbox(body, marker, done)
+	if !changed {
+		return nil
+	}
+	if err := setNoteBody(tx, noteID, body, time.Now().UTC()); err != nil {
+		return fmt.Errorf("updating note checkbox: %w", err)
+	}
+	return nil
+}
+
+// setNoteBody replaces the body of a note and resyncs its indexes.
+func setNoteBody(tx *sql.Tx, noteID, body string, now time.Time) error {
+	if _, err := tx.Exec("UPDATE notes SET body = ?, updated_at = ? WHERE id = ?", body, now, noteID); err != nil {
+		return err
+	}
+	note := &model.Note{ID: noteID, Body: body}
+	if err := tx.QueryRow("SELECT title, tags FROM notes WHERE id = ?", noteID).Scan(&note.Title, &note.Tags); err != nil {
+		return err
+	}
+	return syncNoteIndexes(tx, note)
+}
+
+// truncateTitle shortens s to at most max bytes without splitting a rune.
+func truncateTitle(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max - len("…")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
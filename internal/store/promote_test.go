@@ -0,0 +1,118 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPromoteCheckboxes(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+	board, _ := db.CreateBoard("Sprint", "", wsID)
+	cols, _ := db.ListColumns(board.ID)
+	todo, done := cols[1], cols[len(cols)-1]
+
+	note, _ := db.CreateNote("Standup", "standup", "# Actions\n\n- [ ] Send agenda\n- [x] Book room\n- [ ] Review PR ^pr", wsID)
+
+	cards, err := db.PromoteCheckboxes(note.ID, todo.ID)
+	if err != nil {
+		t.Fatalf("promoting: %v", err)
+	}
+	if len(cards) != 2 || cards[0].Title != "Send agenda" || cards[1].Title != "Review PR" {
+		t.Fatalf("unexpected cards: %+v", cards)
+	}
+	if cards[0].ColumnID != todo.ID || !strings.Contains(cards[0].Description, "[[standup#^card-") {
+		t.Errorf("unexpected card: %+v", cards[0])
+	}
+
+	got, _ := db.GetNote(note.ID)
+	marker := "card-" + cards[0].ID[:8]
+	if !strings.Contains(got.Body, "- [ ] Send agenda ^"+marker) || !strings.HasSuffix(got.Body, "- [ ] Review PR ^pr") {
+		t.Errorf("unexpected markers: %q", got.Body)
+	}
+
+	backlinks, _ := db.GetBacklinks("note", note.ID)
+	if len(backlinks) != 2 || backlinks[0].SourceType != "card" {
+		t.Errorf("expected card backlinks, got %+v", backlinks)
+	}
+
+	again, err := db.PromoteCheckboxes(note.ID, todo.ID)
+	if err != nil || len(again) != 0 {
+		t.Errorf("expected nothing new to promote, got %d, %v", len(again), err)
+	}
+
+	// Checking the box moves the card to done.
+	got.Body = strings.Replace(got.Body, "- [ ] Send agenda", "- [x] Send agenda", 1)
+	if err := db.UpdateNote(got); err != nil {
+		t.Fatalf("updating note: %v", err)
+	}
+	card, _ := db.GetCard(cards[0].ID)
+	if card.ColumnID != done.ID {
+		t.Errorf("expected card in done column, got %s", card.ColumnID)
+	}
+
+	// Unchecking moves it back to where it was promoted.
+	got.Body = strings.Replace(got.Body, "- [x] Send agenda", "- [ ] Send agenda", 1)
+	db.UpdateNote(got)
+	card, _ = db.GetCard(cards[0].ID)
+	if card.ColumnID != todo.ID {
+		t.Errorf("expected card back in todo, got %s", card.ColumnID)
+	}
+
+	// Moving the card to done ticks the box, even after the line is edited.
+	got.Body = strings.Replace(got.Body, "Review PR", "Review the big PR", 1)
+	db.UpdateNote(got)
+	if err := db.MoveCard(cards[1].ID, done.ID); err != nil {
+		t.Fatalf("moving card: %v", err)
+	}
+	got, _ = db.GetNote(note.ID)
+	if !strings.Contains(got.Body, "- [x] Review the big PR ^pr") {
+		t.Errorf("expected box ticked, got %q", got.Body)
+	}
+
+	// Moving it back out clears the box again.
+	card, _ = db.GetCard(cards[1].ID)
+	card.ColumnID = cols[2].ID
+	if err := db.UpdateCard(card); err != nil {
+		t.Fatalf("updating card: %v", err)
+	}
+	got, _ = db.GetNote(note.ID)
+	if !strings.Contains(got.Body, "- [ ] Review the big PR ^pr") {
+		t.Errorf("expected box cleared, got %q", got.Body)
+	}
+}
+
+func TestSetNoteBodySyncsIndexes(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+	note, _ := db.CreateNote("Standup", "standup", "- [ ] Send agenda", wsID)
+
+	tx, _ := db.conn.Begin()
+	if err := setNoteBody(tx, note.ID, "---\nstatus: done\n---\n- [x] Send agenda #ops\nPlan the deployment", time.Now().UTC()); err != nil {
+		t.Fatalf("setting body: %v", err)
+	}
+	tx.Commit()
+
+	if notes, _ := db.ListNotesByTag("ops"); len(notes) != 1 {
+		t.Errorf("expected the inline tag to be indexed, got %d notes", len(notes))
+	}
+	if props, _ := db.ListNoteProperties(note.ID); len(props) != 1 || props[0].Value != "done" {
+		t.Errorf("expected the property to be indexed, got %+v", props)
+	}
+	var terms int
+	db.conn.QueryRow("SELECT COUNT(*) FROM note_terms WHERE note_id = ? AND term = 'deployment'", note.ID).Scan(&terms)
+	if terms != 1 {
+		t.Error("expected the new words to be indexed")
+	}
+}
+
+func TestTruncateTitle(t *testing.T) {
+	if got := truncateTitle("short", 10); got != "short" {
+		t.Errorf("truncateTitle = %q", got)
+	}
+	got := truncateTitle(strings.Repeat("é", 10), 8)
+	if len(got) > 8 || got != "éé…" {
+		t.Errorf("truncateTitle = %q", got)
+	}
+}
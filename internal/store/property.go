@@ -0,0 +1,118 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// syncNoteProperties replaces the stored properties of a note with the ones
+// parsed from the front matter of body. List items get one row each.
+func syncNoteProperties(tx *sql.Tx, noteID, body string) error {
+	if _, err := tx.Exec("DELETE FROM note_properties WHERE note_id = ?", noteID); err != nil {
+		return fmt.Errorf("clearing note properties: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, p := range model.ParseFrontMatter(body) {
+		if seen[strings.ToLower(p.Key)] {
+			continue
+		}
+		seen[strings.ToLower(p.Key)] = true
+
+		values := p.Items
+		if p.Type != model.PropertyList {
+			values = []string{p.Value}
+		}
+		for i, v := range values {
+			var num *float64
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				num = &f
+			}
+			if _, err := tx.Exec(
+				"INSERT INTO note_properties (note_id, key, position, type, value, num) VALUES (?, ?, ?, ?, ?, ?)",
+				noteID, p.Key, i, string(p.Type), v, num,
+			); err != nil {
+				return fmt.Errorf("inserting note property: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (d *DB) ListNoteProperties(noteID string) ([]model.Property, error) {
+	rows, err := d.conn.Query(
+		"SELECT key, type, value FROM note_properties WHERE note_id = ? ORDER BY rowid", noteID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing note properties: %w", err)
+	}
+	defer rows.Close()
+
+	var props []model.Property
+	for rows.Next() {
+		var key, typ, value string
+		if err := rows.Scan(&key, &typ, &value); err != nil {
+			return nil, fmt.Errorf("scanning note property: %w", err)
+		}
+		if model.PropertyType(typ) == model.PropertyList {
+			if n := len(props); n > 0 && props[n-1].Key == key {
+				props[n-1].Items = append(props[n-1].Items, value)
+				continue
+			}
+			props = append(props, model.Property{Key: key, Type: model.PropertyList, Items: []string{value}})
+			continue
+		}
+		props = append(props, model.Property{Key: key, Type: model.PropertyType(typ), Value: value})
+	}
+	return props, rows.Err()
+}
+
+// QueryNotes lists active notes matching every filter. Numeric filter values
+// compare numerically, anything else compares as text, which orders ISO
+// dates correctly. sortKey orders by a property, descending with a leading
+// "-"; notes without the property sort last.
+func (d *DB) QueryNotes(filters []model.PropertyFilter, sortKey string) ([]*model.Note, error) {
+	query := `SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE archived_at IS NULL AND deleted_at IS NULL`
+	var args []any
+
+	for _, f := range filters {
+		cond := "p.value " + f.Op + " ? COLLATE NOCASE"
+		var arg any = f.Value
+		if n, err := strconv.ParseFloat(f.Value, 64); err == nil {
+			cond = "p.num " + f.Op + " ?"
+			arg = n
+		}
+		if f.Op == "!=" {
+			query += " AND NOT EXISTS (SELECT 1 FROM note_properties p WHERE p.note_id = notes.id AND p.key = ? AND " +
+				strings.Replace(cond, "!=", "=", 1) + ")"
+		} else {
+			query += " AND EXISTS (SELECT 1 FROM note_properties p WHERE p.note_id = notes.id AND p.key = ? AND " + cond + ")"
+		}
+		args = append(args, f.Key, arg)
+	}
+
+	if sortKey != "" {
+		dir := "ASC"
+		if strings.HasPrefix(sortKey, "-") {
+			dir = "DESC"
+			sortKey = sortKey[1:]
+		}
+		sub := "(SELECT p.%s FROM note_properties p WHERE p.note_id = notes.id AND p.key = ? AND p.position = 0)"
+		query += fmt.Sprintf(" ORDER BY "+sub+" IS NULL, "+sub+" %s, "+sub+" COLLATE NOCASE %s, updated_at DESC",
+			"value", "num", dir, "value", dir)
+		args = append(args, sortKey, sortKey, sortKey)
+	} else {
+		query += " ORDER BY pinned DESC, updated_at DESC"
+	}
+
+	rows, err := d.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying notes: %w", err)
+	}
+	defer rows.Close()
+	return scanNotes(rows)
+}
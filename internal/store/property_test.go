@@ -0,0 +1,91 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+func noteSlugs(notes []*model.Note) string {
+	slugs := make([]string, len(notes))
+	for i, n := range notes {
+		slugs[i] = n.Slug
+	}
+	return strings.Join(slugs, ",")
+}
+
+func TestNotePropertiesSyncOnSave(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	note, _ := db.CreateNote("Plan", "plan", "---\nstatus: draft\npoints: 3\nowners: [ana, ben]\n---\nbody", wsID)
+	props, err := db.ListNoteProperties(note.ID)
+	if err != nil {
+		t.Fatalf("listing properties: %v", err)
+	}
+	if len(props) != 3 || props[0].Key != "status" || props[1].Type != model.PropertyNumber {
+		t.Fatalf("unexpected properties: %+v", props)
+	}
+	if props[2].Type != model.PropertyList || strings.Join(props[2].Items, ",") != "ana,ben" {
+		t.Errorf("unexpected list property: %+v", props[2])
+	}
+
+	note.Body = "---\nstatus: done\n---\nbody"
+	db.UpdateNote(note)
+	props, _ = db.ListNoteProperties(note.ID)
+	if len(props) != 1 || props[0].Value != "done" {
+		t.Errorf("expected properties replaced on update, got %+v", props)
+	}
+
+	note.Body = "no front matter"
+	db.UpdateNote(note)
+	if props, _ := db.ListNoteProperties(note.ID); len(props) != 0 {
+		t.Errorf("expected properties cleared, got %+v", props)
+	}
+}
+
+func TestQueryNotes(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	db.CreateNote("A", "a", "---\nstatus: draft\ndue: 2026-11-15\npoints: 10\ntags: [go]\n---\n", wsID)
+	db.CreateNote("B", "b", "---\nstatus: Draft\ndue: 2026-12-20\npoints: 2\n---\n", wsID)
+	db.CreateNote("C", "c", "---\nstatus: done\ndue: 2026-10-01\n---\n", wsID)
+	db.CreateNote("D", "d", "plain", wsID)
+
+	filter := func(exprs ...string) []model.PropertyFilter {
+		var filters []model.PropertyFilter
+		for _, e := range exprs {
+			f, err := model.ParsePropertyFilter(e)
+			if err != nil {
+				t.Fatalf("parsing filter: %v", err)
+			}
+			filters = append(filters, f)
+		}
+		return filters
+	}
+
+	tests := []struct {
+		filters []model.PropertyFilter
+		sort    string
+		want    string
+	}{
+		{filter("status=draft"), "due", "a,b"},
+		{filter("status=draft", "due<2026-12-01"), "", "a"},
+		{filter("points>5"), "", "a"},
+		{filter("points>=2"), "points", "b,a"},
+		{filter("status!=draft"), "due", "c,d"},
+		{filter("tags=go"), "", "a"},
+		{nil, "-due", "b,a,c,d"},
+	}
+	for _, tt := range tests {
+		notes, err := db.QueryNotes(tt.filters, tt.sort)
+		if err != nil {
+			t.Fatalf("QueryNotes(%v, %q): %v", tt.filters, tt.sort, err)
+		}
+		if got := noteSlugs(notes); got != tt.want {
+			t.Errorf("QueryNotes(%v, %q) = %s, want %s", tt.filters, tt.sort, got, tt.want)
+		}
+	}
+}
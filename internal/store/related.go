@@ -0,0 +1,147 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// RelatedNote is a note similar to another, with its cosine similarity
+// between 0 and 1.
+type RelatedNote struct {
+	Note  *model.Note
+	Score float64
+}
+
+// syncNoteTerms replaces the indexed word counts of a note.
+func syncNoteTerms(tx *sql.Tx, noteID, title, body string) error {
+	if _, err := tx.Exec("DELETE FROM note_terms WHERE note_id = ?", noteID); err != nil {
+		return fmt.Errorf("clearing note terms: %w", err)
+	}
+	for term, count := range model.NoteTerms(title, body) {
+		if _, err := tx.Exec(
+			"INSERT INTO note_terms (note_id, term, count) VALUES (?, ?, ?)", noteID, term, count,
+		); err != nil {
+			return fmt.Errorf("inserting note term: %w", err)
+		}
+	}
+	return nil
+}
+
+// RelatedNotes ranks the active notes most similar to the note id by the
+// TF-IDF cosine similarity of their words, leaving out notes that already
+// link to it or that it links to.
+func (d *DB) RelatedNotes(id string, limit int) ([]*RelatedNote, error) {
+	rows, err := d.conn.Query(
+		`SELECT t.note_id, t.term, t.count FROM note_terms t JOIN notes n ON n.id = t.note_id
+		 WHERE n.archived_at IS NULL AND n.deleted_at IS NULL`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reading note terms: %w", err)
+	}
+	vectors := make(map[string]map[string]float64)
+	df := make(map[string]int)
+	for rows.Next() {
+		var noteID, term string
+		var count int
+		if err := rows.Scan(&noteID, &term, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning note term: %w", err)
+		}
+		if vectors[noteID] == nil {
+			vectors[noteID] = make(map[string]float64)
+		}
+		vectors[noteID][term] = 1 + math.Log(float64(count))
+		df[term]++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading note terms: %w", err)
+	}
+
+	source, ok := vectors[id]
+	if !ok {
+		return []*RelatedNote{}, nil
+	}
+
+	linked, err := d.linkedNoteIDs(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Weight every term by its inverse document frequency, then compare
+	// the normalized vectors.
+	n := float64(len(vectors))
+	norms := make(map[string]float64, len(vectors))
+	for noteID, vec := range vectors {
+		sum := 0.0
+		for term, tf := range vec {
+			w := tf * math.Log(1+n/float64(df[term]))
+			vec[term] = w
+			sum += w * w
+		}
+		norms[noteID] = math.Sqrt(sum)
+	}
+
+	type scored struct {
+		id    string
+		score float64
+	}
+	var ranked []scored
+	for noteID, vec := range vectors {
+		if noteID == id || linked[noteID] {
+			continue
+		}
+		dot := 0.0
+		for term, w := range source {
+			dot += w * vec[term]
+		}
+		if dot > 0 {
+			ranked = append(ranked, scored{noteID, dot / (norms[id] * norms[noteID])})
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].id < ranked[j].id
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	related := []*RelatedNote{}
+	for _, r := range ranked {
+		note, err := d.GetNote(r.id)
+		if err != nil {
+			return nil, err
+		}
+		related = append(related, &RelatedNote{Note: note, Score: r.score})
+	}
+	return related, nil
+}
+
+// linkedNoteIDs collects the notes the note id links to or is linked from.
+func (d *DB) linkedNoteIDs(id string) (map[string]bool, error) {
+	rows, err := d.conn.Query(
+		`SELECT target_id FROM links WHERE source_type = 'note' AND source_id = ? AND target_type = 'note'
+		 UNION SELECT source_id FROM links WHERE source_type = 'note' AND target_type = 'note' AND target_id = ?`,
+		id, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reading note links: %w", err)
+	}
+	defer rows.Close()
+	linked := make(map[string]bool)
+	for rows.Next() {
+		var other string
+		if err := rows.Scan(&other); err != nil {
+			return nil, fmt.Errorf("scanning note link: %w", err)
+		}
+		linked[other] = true
+	}
+	return linked, rows.Err()
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestRelatedNotes(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	pg, _ := db.CreateNote("Postgres tuning", "postgres-tuning", "Vacuum settings, index bloat and the query planner.", wsID)
+	idx, _ := db.CreateNote("Index design", "index-design", "Choosing a Postgres index for the query planner.", wsID)
+	db.CreateNote("Vacation", "vacation", "Beaches and trains.", wsID)
+	linked, _ := db.CreateNote("Postgres links", "postgres-links", "Postgres index reading list. See [[postgres-tuning]]", wsID)
+	db.SyncNoteLinks(linked)
+
+	related, err := db.RelatedNotes(pg.ID, 10)
+	if err != nil {
+		t.Fatalf("RelatedNotes: %v", err)
+	}
+	if len(related) != 1 || related[0].Note.ID != idx.ID {
+		t.Fatalf("expected only the unlinked index note, got %+v", related)
+	}
+	if related[0].Score <= 0 || related[0].Score > 1 {
+		t.Errorf("score should be in (0, 1], got %f", related[0].Score)
+	}
+
+	idx.Body = "Beaches and trains for the summer."
+	idx.Title = "Summer plans"
+	db.UpdateNote(idx)
+	related, _ = db.RelatedNotes(pg.ID, 10)
+	if len(related) != 0 {
+		t.Errorf("expected the index to follow edits, got %+v", related)
+	}
+
+	db.ArchiveNote(linked.ID)
+	related, _ = db.RelatedNotes(idx.ID, 1)
+	if len(related) != 1 || related[0].Note.Slug != "vacation" {
+		t.Errorf("expected vacation as the closest note, got %+v", related)
+	}
+}
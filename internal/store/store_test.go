@@ -22,3 +22,38 @@ func testDefaultWSID(t *testing.T, db *DB) string {
 	}
 	return ws.ID
 }
+
+func TestChangeStamp(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	stamp := func() string {
+		t.Helper()
+		s, err := db.ChangeStamp()
+		if err != nil {
+			t.Fatalf("ChangeStamp: %v", err)
+		}
+		return s
+	}
+
+	before := stamp()
+	if again := stamp(); again != before {
+		t.Errorf("stamp changed without edits: %q → %q", before, again)
+	}
+
+	note, err := db.CreateNote("Stamp", "stamp", "body", wsID)
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	afterCreate := stamp()
+	if afterCreate == before {
+		t.Error("stamp unchanged after creating a note")
+	}
+
+	if err := db.DeleteNote(note.ID); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	if stamp() == afterCreate {
+		t.Error("stamp unchanged after deleting a note")
+	}
+}
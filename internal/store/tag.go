@@ -0,0 +1,124 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// syncNoteTags replaces the normalized tags of a note with those from its
+// tags column and inline #tags in its body.
+func syncNoteTags(tx *sql.Tx, note *model.Note) error {
+	if _, err := tx.Exec("DELETE FROM note_tags WHERE note_id = ?", note.ID); err != nil {
+		return fmt.Errorf("clearing note tags: %w", err)
+	}
+	for _, tag := range model.NoteTags(note) {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO note_tags (note_id, tag) VALUES (?, ?)", note.ID, tag); err != nil {
+			return fmt.Errorf("inserting note tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// tagPrefixPattern returns a LIKE pattern matching tags nested below tag.
+func tagPrefixPattern(tag string) string {
+	return likeEscape(model.NormalizeTag(tag)) + "/%"
+}
+
+// likeEscape escapes the LIKE wildcards in s for use with ESCAPE '\'.
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// ListNoteTags maps the IDs of active notes to their normalized tags.
+func (d *DB) ListNoteTags() (map[string][]string, error) {
+	rows, err := d.conn.Query(
+		`SELECT t.note_id, t.tag FROM note_tags t
+		 JOIN notes n ON n.id = t.note_id
+		 WHERE n.archived_at IS NULL AND n.deleted_at IS NULL
+		 ORDER BY t.tag`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing note tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make(map[string][]string)
+	for rows.Next() {
+		var noteID, tag string
+		if err := rows.Scan(&noteID, &tag); err != nil {
+			return nil, fmt.Errorf("scanning note tag: %w", err)
+		}
+		tags[noteID] = append(tags[noteID], tag)
+	}
+	return tags, rows.Err()
+}
+
+// RenameTag renames old, and every tag nested below it, in both the tags
+// column and inline #tags of all notes. It returns the number of notes
+// changed.
+func (d *DB) RenameTag(old, new string) (int, error) {
+	old, new = model.NormalizeTag(old), model.NormalizeTag(new)
+	if err := model.ValidateTag(old); err != nil {
+		return 0, err
+	}
+	if err := model.ValidateTag(new); err != nil {
+		return 0, err
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`SELECT id, title, slug, body, tags, pinned, workspace_id, created_at, updated_at, archived_at, deleted_at
+		 FROM notes WHERE id IN (
+		   SELECT note_id FROM note_tags WHERE tag = ? OR tag LIKE ? ESCAPE '\')`,
+		old, tagPrefixPattern(old),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("finding tagged notes: %w", err)
+	}
+	notes, err := scanNotes(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	for _, n := range notes {
+		seen := make(map[string]bool)
+		var tags []string
+		for _, t := range n.TagList() {
+			if model.TagMatches(t, old) {
+				t = new + model.NormalizeTag(t)[len(old):]
+			}
+			if !seen[strings.ToLower(t)] {
+				seen[strings.ToLower(t)] = true
+				tags = append(tags, t)
+			}
+		}
+		n.Tags = strings.Join(tags, ",")
+		n.Body = model.RenameInlineTag(n.Body, old, new)
+
+		if _, err := tx.Exec(
+			"UPDATE notes SET tags = ?, body = ?, updated_at = ? WHERE id = ?",
+			n.Tags, n.Body, now, n.ID,
+		); err != nil {
+			return 0, fmt.Errorf("renaming tag: %w", err)
+		}
+		if err := syncNoteIndexes(tx, n); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing tag rename: %w", err)
+	}
+	return len(notes), nil
+}
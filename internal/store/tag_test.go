@@ -0,0 +1,68 @@
+package store
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListNoteTags(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	n, _ := db.CreateNote("Tagged", "tagged", "Uses #Go and #area/backend", wsID)
+	n.Tags = "go,pkm"
+	db.UpdateNote(n)
+	archived, _ := db.CreateNote("Archived", "archived", "#hidden", wsID)
+	db.ArchiveNote(archived.ID)
+
+	tags, err := db.ListNoteTags()
+	if err != nil {
+		t.Fatalf("listing note tags: %v", err)
+	}
+	want := map[string][]string{n.ID: {"area/backend", "go", "pkm"}}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("ListNoteTags = %v, want %v", tags, want)
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+
+	a, _ := db.CreateNote("A", "a", "Working on #area/backend/db today", wsID)
+	a.Tags = "area/backend,go"
+	db.UpdateNote(a)
+	b, _ := db.CreateNote("B", "b", "Unrelated #area/frontend", wsID)
+
+	count, err := db.RenameTag("#area/backend", "server")
+	if err != nil {
+		t.Fatalf("renaming tag: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 note changed, got %d", count)
+	}
+
+	got, _ := db.GetNote(a.ID)
+	if got.Tags != "server,go" {
+		t.Errorf("tags = %q, want %q", got.Tags, "server,go")
+	}
+	if !strings.Contains(got.Body, "#server/db") {
+		t.Errorf("body not rewritten: %q", got.Body)
+	}
+	if other, _ := db.GetNote(b.ID); other.Body != "Unrelated #area/frontend" {
+		t.Errorf("unrelated note changed: %q", other.Body)
+	}
+
+	notes, _ := db.ListNotesByTag("server")
+	if len(notes) != 1 {
+		t.Errorf("expected 1 note under 'server', got %d", len(notes))
+	}
+	if notes, _ := db.ListNotesByTag("area/backend"); len(notes) != 0 {
+		t.Errorf("expected no notes under old tag, got %d", len(notes))
+	}
+
+	if _, err := db.RenameTag("go", "two words"); err == nil {
+		t.Error("expected error for invalid tag")
+	}
+}
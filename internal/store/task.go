@@ -0,0 +1,142 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+type TaskFilter struct {
+	Open        bool
+	WorkspaceID string
+	Tag         string
+}
+
+// syncNoteTasks replaces the indexed tasks of a note with the checkboxes in
+// body.
+func syncNoteTasks(tx *sql.Tx, noteID, body string) error {
+	if _, err := tx.Exec("DELETE FROM note_tasks WHERE note_id = ?", noteID); err != nil {
+		return fmt.Errorf("clearing note tasks: %w", err)
+	}
+	for _, t := range model.ParseTasks(noteID, body) {
+		if _, err := tx.Exec(
+			`INSERT OR IGNORE INTO note_tasks (id, note_id, line, text, checked, due, priority, context)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			t.ID, noteID, t.Line, t.Text, boolToInt(t.Checked), t.Due, string(t.Priority), t.Context,
+		); err != nil {
+			return fmt.Errorf("inserting note task: %w", err)
+		}
+	}
+	return nil
+}
+
+const taskColumns = `SELECT t.id, t.note_id, n.title, n.slug, t.line, t.text, t.checked, t.due, t.priority, t.context
+	 FROM note_tasks t JOIN notes n ON n.id = t.note_id
+	 WHERE n.archived_at IS NULL AND n.deleted_at IS NULL`
+
+// ListTasks lists the checkbox tasks of active notes, grouped by note and in
+// the order they appear in each note.
+func (d *DB) ListTasks(filter TaskFilter) ([]*model.Task, error) {
+	query := taskColumns
+	var args []any
+	if filter.Open {
+		query += " AND t.checked = 0"
+	}
+	if filter.WorkspaceID != "" {
+		query += " AND n.workspace_id = ?"
+		args = append(args, filter.WorkspaceID)
+	}
+	if filter.Tag != "" {
+		query += ` AND EXISTS (SELECT 1 FROM note_tags nt WHERE nt.note_id = n.id AND (nt.tag = ? OR nt.tag LIKE ? ESCAPE '\'))`
+		args = append(args, model.NormalizeTag(filter.Tag), tagPrefixPattern(filter.Tag))
+	}
+	query += " ORDER BY n.title COLLATE NOCASE, n.id, t.line"
+
+	rows, err := d.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing tasks: %w", err)
+	}
+	defer rows.Close()
+	return scanTasks(rows)
+}
+
+// ResolveTask finds a task by ID or unique ID prefix of at least 4
+// characters.
+func (d *DB) ResolveTask(ref string) (*model.Task, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if len(ref) < 4 {
+		return nil, fmt.Errorf("task ID %q is too short; use at least 4 characters", ref)
+	}
+	rows, err := d.conn.Query(taskColumns+` AND t.id LIKE ? ESCAPE '\' ORDER BY t.id`, likeEscape(ref)+"%")
+	if err != nil {
+		return nil, fmt.Errorf("resolving task: %w", err)
+	}
+	defer rows.Close()
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(tasks) {
+	case 0:
+		return nil, fmt.Errorf("no task found matching %q", ref)
+	case 1:
+		return tasks[0], nil
+	default:
+		return nil, fmt.Errorf("ambiguous task %q matches %d tasks; use more characters", ref, len(tasks))
+	}
+}
+
+// ToggleTask flips the checkbox of a task in its note. The note is saved
+// through UpdateNote, so cards promoted from the checkbox follow along.
+func (d *DB) ToggleTask(id string) (*model.Task, error) {
+	var noteID string
+	err := d.conn.QueryRow("SELECT note_id FROM note_tasks WHERE id = ?", id).Scan(&noteID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("task not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting task: %w", err)
+	}
+
+	note, err := d.GetNote(noteID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range model.ParseTasks(note.ID, note.Body) {
+		if t.ID != id {
+			continue
+		}
+		body, ok := model.ToggleCheckboxLine(note.Body, t.Line)
+		if !ok {
+			break
+		}
+		note.Body = body
+		if err := d.UpdateNote(note); err != nil {
+			return nil, err
+		}
+		t.Checked = !t.Checked
+		t.NoteTitle, t.NoteSlug = note.Title, note.Slug
+		return &t, nil
+	}
+	return nil, fmt.Errorf("task no longer exists in note %q", note.Slug)
+}
+
+func scanTasks(rows *sql.Rows) ([]*model.Task, error) {
+	var tasks []*model.Task
+	for rows.Next() {
+		t := &model.Task{}
+		var checked int
+		var priority string
+		if err := rows.Scan(&t.ID, &t.NoteID, &t.NoteTitle, &t.NoteSlug, &t.Line, &t.Text,
+			&checked, &t.Due, &priority, &t.Context); err != nil {
+			return nil, fmt.Errorf("scanning task: %w", err)
+		}
+		t.Checked = checked != 0
+		t.Priority = model.Priority(priority)
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeryldev/kb/internal/model"
+)
+
+func TestListTasks(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+	other, _ := db.CreateWorkspace("Side", model.KindArea, "", "")
+
+	db.CreateNote("Launch", "launch", "# Prep\n- [ ] Write post @due(2026-11-01) !high\n- [x] Book room\n\n#work/launch", wsID)
+	db.CreateNote("Errands", "errands", "- [ ] Buy milk", other.ID)
+	archived, _ := db.CreateNote("Old", "old", "- [ ] Forgotten", wsID)
+	db.ArchiveNote(archived.ID)
+
+	all, err := db.ListTasks(TaskFilter{})
+	if err != nil {
+		t.Fatalf("listing tasks: %v", err)
+	}
+	if len(all) != 3 || all[0].NoteSlug != "errands" || all[1].NoteSlug != "launch" {
+		t.Fatalf("unexpected tasks: %+v", all)
+	}
+	post := all[1]
+	if post.Text != "Write post" || post.Due != "2026-11-01" || post.Priority != model.PriorityHigh ||
+		post.Context != "Prep" || post.NoteTitle != "Launch" {
+		t.Errorf("unexpected task: %+v", post)
+	}
+
+	open, _ := db.ListTasks(TaskFilter{Open: true})
+	if len(open) != 2 {
+		t.Errorf("expected 2 open tasks, got %d", len(open))
+	}
+	inWS, _ := db.ListTasks(TaskFilter{WorkspaceID: other.ID})
+	if len(inWS) != 1 || inWS[0].Text != "Buy milk" {
+		t.Errorf("unexpected workspace tasks: %+v", inWS)
+	}
+	tagged, _ := db.ListTasks(TaskFilter{Tag: "work"})
+	if len(tagged) != 2 {
+		t.Errorf("expected nested tag match to find 2 tasks, got %d", len(tagged))
+	}
+}
+
+func TestToggleTask(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+	note, _ := db.CreateNote("Launch", "launch", "Intro\n- [ ] Write post !high\n- [ ] Ship", wsID)
+
+	tasks, _ := db.ListTasks(TaskFilter{})
+	task, err := db.ResolveTask(tasks[0].ID[:6])
+	if err != nil || task.ID != tasks[0].ID {
+		t.Fatalf("resolving task: %v, %+v", err, task)
+	}
+	if _, err := db.ResolveTask("ab"); err == nil {
+		t.Error("expected error for short prefix")
+	}
+
+	toggled, err := db.ToggleTask(task.ID)
+	if err != nil {
+		t.Fatalf("toggling task: %v", err)
+	}
+	if !toggled.Checked {
+		t.Error("expected task to be checked")
+	}
+	got, _ := db.GetNote(note.ID)
+	if !strings.Contains(got.Body, "- [x] Write post !high") {
+		t.Errorf("checkbox not toggled in body: %q", got.Body)
+	}
+	open, _ := db.ListTasks(TaskFilter{Open: true})
+	if len(open) != 1 || open[0].Text != "Ship" {
+		t.Errorf("unexpected open tasks after toggle: %+v", open)
+	}
+
+	got.Body = "New intro\n\n" + got.Body
+	db.UpdateNote(got)
+	if _, err := db.ToggleTask(task.ID); err != nil {
+		t.Fatalf("toggling after edit: %v", err)
+	}
+	got, _ = db.GetNote(note.ID)
+	if !strings.Contains(got.Body, "- [ ] Write post !high") {
+		t.Errorf("checkbox not cleared: %q", got.Body)
+	}
+}
+
+func TestToggleTaskSyncsPromotedCard(t *testing.T) {
+	db := testDB(t)
+	wsID := testDefaultWSID(t, db)
+	board, _ := db.CreateBoard("Sprint", "", wsID)
+	cols, _ := db.ListColumns(board.ID)
+	note, _ := db.CreateNote("Standup", "standup", "- [ ] Send agenda", wsID)
+	cards, _ := db.PromoteCheckboxes(note.ID, cols[0].ID)
+
+	tasks, _ := db.ListTasks(TaskFilter{})
+	if len(tasks) != 1 {
+		t.Fatalf("expected promotion to keep one task, got %+v", tasks)
+	}
+	if _, err := db.ToggleTask(tasks[0].ID); err != nil {
+		t.Fatalf("toggling task: %v", err)
+	}
+	card, _ := db.GetCard(cards[0].ID)
+	if card.ColumnID != cols[len(cols)-1].ID {
+		t.Errorf("expected promoted card to move to the last column")
+	}
+}
@@ -0,0 +1,183 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Template struct {
+	Name   string
+	Body   string
+	Source string
+}
+
+const builtinSource = "built-in"
+
+var builtins = map[string]string{
+	"meeting": `Date: {{date}}
+Attendees: {{prompt:Attendees}}
+
+## Agenda
+
+## Notes
+
+## Action items
+
+- [ ]
+`,
+	"adr": `Status: {{prompt:Status}}
+Date: {{date}}
+
+## Context
+
+## Decision
+
+## Consequences
+`,
+	"retro": `Date: {{date}}
+Workspace: {{workspace}}
+
+## What went well
+
+## What could be better
+
+## Action items
+
+- [ ]
+`,
+	"1on1": `Date: {{date}}
+With: {{prompt:With}}
+
+## Updates
+
+## Discussion
+
+## Follow-ups
+
+- [ ]
+`,
+}
+
+var aliases = map[string]string{
+	"1:1":        "1on1",
+	"one-on-one": "1on1",
+}
+
+var variableRe = regexp.MustCompile(`\{\{\s*([a-zA-Z]+)(?::([^}]*))?\s*\}\}`)
+
+// Dir returns the directory user templates are read from:
+// $KB_TEMPLATE_DIR, or $XDG_CONFIG_HOME/kb/templates.
+func Dir() (string, error) {
+	if dir := os.Getenv("KB_TEMPLATE_DIR"); dir != "" {
+		return dir, nil
+	}
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("finding home directory: %w", err)
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "kb", "templates"), nil
+}
+
+// List returns the built-in templates merged with the .md files in Dir,
+// where a file overrides the built-in template of the same name.
+func List() ([]Template, error) {
+	byName := make(map[string]Template, len(builtins))
+	for name, body := range builtins {
+		byName[name] = Template{Name: name, Body: body, Source: builtinSource}
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading template directory: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading template: %w", err)
+		}
+		name := strings.TrimSuffix(e.Name(), ".md")
+		byName[name] = Template{Name: name, Body: string(data), Source: path}
+	}
+
+	list := make([]Template, 0, len(byName))
+	for _, t := range byName {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func Get(name string) (Template, error) {
+	list, err := List()
+	if err != nil {
+		return Template{}, err
+	}
+	want := strings.ToLower(name)
+	if alias, ok := aliases[want]; ok {
+		want = alias
+	}
+	for _, t := range list {
+		if strings.ToLower(t.Name) == want {
+			return t, nil
+		}
+	}
+	return Template{}, fmt.Errorf("template %q not found", name)
+}
+
+func NoteVars(title, workspace string, now time.Time) map[string]string {
+	return map[string]string{
+		"title":     title,
+		"date":      now.Format("2006-01-02"),
+		"time":      now.Format("15:04"),
+		"workspace": workspace,
+	}
+}
+
+// Prompts returns the labels of the {{prompt:Label}} variables in body,
+// in order of first appearance.
+func Prompts(body string) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, m := range variableRe.FindAllStringSubmatch(body, -1) {
+		label := strings.TrimSpace(m[2])
+		if m[1] != "prompt" || label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// Expand replaces {{name}} with vars[name] and {{prompt:Label}} with
+// answers[Label]. Unknown variables are left untouched.
+func Expand(body string, vars, answers map[string]string) string {
+	return variableRe.ReplaceAllStringFunc(body, func(match string) string {
+		m := variableRe.FindStringSubmatch(match)
+		if m[1] == "prompt" {
+			return answers[strings.TrimSpace(m[2])]
+		}
+		if v, ok := vars[m[1]]; ok && m[2] == "" {
+			return v
+		}
+		return match
+	})
+}
@@ -0,0 +1,72 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListBuiltins(t *testing.T) {
+	t.Setenv("KB_TEMPLATE_DIR", t.TempDir())
+
+	list, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var names []string
+	for _, tmpl := range list {
+		names = append(names, tmpl.Name)
+		if tmpl.Source != builtinSource {
+			t.Errorf("%s source = %q, want built-in", tmpl.Name, tmpl.Source)
+		}
+	}
+	want := []string{"1on1", "adr", "meeting", "retro"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+}
+
+func TestFileTemplatesOverrideBuiltins(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("KB_TEMPLATE_DIR", dir)
+	os.WriteFile(filepath.Join(dir, "retro.md"), []byte("custom retro"), 0o644)
+	os.WriteFile(filepath.Join(dir, "standup.md"), []byte("Yesterday:\nToday:"), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644)
+
+	retro, err := Get("Retro")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if retro.Body != "custom retro" || retro.Source != filepath.Join(dir, "retro.md") {
+		t.Errorf("expected file to override built-in, got %+v", retro)
+	}
+	if _, err := Get("standup"); err != nil {
+		t.Errorf("expected user template: %v", err)
+	}
+	if _, err := Get("notes"); err == nil {
+		t.Error("expected non-markdown file to be ignored")
+	}
+	if oneOnOne, err := Get("1:1"); err != nil || oneOnOne.Name != "1on1" {
+		t.Errorf("expected 1:1 alias to resolve, got %+v, %v", oneOnOne, err)
+	}
+}
+
+func TestPromptsAndExpand(t *testing.T) {
+	body := "# {{title}}\n{{date}} in {{ workspace }}\nWith: {{prompt:Attendees}}\nAgain: {{prompt: Attendees }}\n{{prompt:Goal}} {{unknown}}"
+
+	prompts := Prompts(body)
+	if !reflect.DeepEqual(prompts, []string{"Attendees", "Goal"}) {
+		t.Errorf("prompts = %v", prompts)
+	}
+
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	got := Expand(body, NoteVars("Sprint 4 retro", "backend", now), map[string]string{
+		"Attendees": "Ana, Bo",
+	})
+	want := "# Sprint 4 retro\n2026-10-18 in backend\nWith: Ana, Bo\nAgain: Ana, Bo\n {{unknown}}"
+	if got != want {
+		t.Errorf("Expand = %q, want %q", got, want)
+	}
+}
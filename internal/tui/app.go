@@ -16,6 +16,10 @@ const (
 	modeCardEdit
 	modeNotes
 	modeNoteView
+	modeTasks
+	modeReview
+	modeGraph
+	modeMentions
 )
 
 type App struct {
@@ -30,6 +34,10 @@ type App struct {
 	card      cardModel
 	noteList noteListModel
 	noteView noteViewModel
+	tasks    tasksModel
+	review   reviewModel
+	graph    graphModel
+	mentions mentionsModel
 
 	width  int
 	height int
@@ -44,6 +52,9 @@ func NewApp(db *store.DB, boardName string) *App {
 }
 
 func (a *App) Init() tea.Cmd {
+	if a.mode == modeReview {
+		return a.loadReview()
+	}
 	return a.initPicker()
 }
 
@@ -74,6 +85,14 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a.updateNoteList(msg)
 	case modeNoteView:
 		return a.updateNoteView(msg)
+	case modeTasks:
+		return a.updateTasks(msg)
+	case modeReview:
+		return a.updateReview(msg)
+	case modeGraph:
+		return a.updateGraph(msg)
+	case modeMentions:
+		return a.updateMentions(msg)
 	}
 
 	return a, nil
@@ -95,6 +114,14 @@ func (a *App) View() string {
 		return a.viewNoteList()
 	case modeNoteView:
 		return a.viewNoteDetail()
+	case modeTasks:
+		return a.viewTasks()
+	case modeReview:
+		return a.viewReview()
+	case modeGraph:
+		return a.viewGraph()
+	case modeMentions:
+		return a.viewMentions()
 	}
 	return ""
 }
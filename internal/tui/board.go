@@ -27,6 +27,8 @@ type boardModel struct {
 	showHelp     bool
 	err          error
 	feedback     string
+	fromNote     bool
+	openCardID   string
 }
 
 type boardLoadedMsg struct {
@@ -66,6 +68,11 @@ func (a *App) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.board.err = nil
 		a.clampCardSelection()
 		a.adjustScroll()
+		if a.board.openCardID != "" {
+			a.focusCardByID(a.board.openCardID)
+			a.board.openCardID = ""
+			return a, a.viewSelectedCard()
+		}
 
 	case cardMovedMsg:
 		a.board.feedback = "Card moved"
@@ -76,6 +83,8 @@ func (a *App) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case cardDeletedMsg:
 		a.board.feedback = "Card deleted"
 		return a, a.loadBoard()
+	case boardSnapshotMsg:
+		a.board.feedback = fmt.Sprintf("Snapshot saved to note %q", msg.note.Slug)
 
 	case errMsg:
 		a.board.err = msg.err
@@ -140,6 +149,8 @@ func (a *App) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "/":
 			a.board.filtering = true
 			a.board.filterInput = ""
+		case "S":
+			return a, a.snapshotBoard()
 		case "1":
 			a.togglePriorityFilter("urgent")
 		case "2":
@@ -148,6 +159,10 @@ func (a *App) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.togglePriorityFilter("medium")
 		case "4":
 			a.togglePriorityFilter("low")
+		case "backspace":
+			if a.board.fromNote {
+				a.mode = modeNoteView
+			}
 		case "b":
 			if a.wsContent.workspace != nil {
 				return a, a.switchToWSContent(a.wsContent.workspace)
@@ -164,6 +179,19 @@ func (a *App) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+func (a *App) focusCardByID(id string) {
+	for i, col := range a.board.columns {
+		for j, card := range a.cardsForDisplay(col.ID) {
+			if card.ID == id {
+				a.board.focusCol = i
+				a.board.focusCard = j
+				a.adjustScroll()
+				return
+			}
+		}
+	}
+}
+
 func (a *App) startMoveMode(colDir, cardDir int) {
 	card := a.selectedCard()
 	if card == nil || len(a.board.columns) == 0 {
@@ -849,6 +877,7 @@ func (a *App) viewBoardHelp() string {
 		{"D", "Delete card"},
 		{"/", "Filter by label or priority"},
 		{"1-4", "Filter by priority"},
+		{"S", "Snapshot board into a note"},
 		{"b", "Switch board"},
 		{"?", "Toggle this help"},
 		{"q", "Quit"},
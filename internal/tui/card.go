@@ -66,6 +66,10 @@ func (a *App) updateCardView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc", "q":
 			a.mode = modeBoard
 			return a, nil
+		case "backspace":
+			if a.board.fromNote {
+				a.mode = modeNoteView
+			}
 		}
 	}
 	return a, nil
@@ -117,7 +121,11 @@ func (a *App) viewCardReadonly() string {
 	labelW := 14
 
 	titleBar := titleBarStyle.Width(w).Render(" View Card ")
-	statusBar := statusBarStyle.Width(w).Render(" e: edit   d: archive   D: delete   Esc: back")
+	statusText := " e: edit   d: archive   D: delete   Esc: back"
+	if a.board.fromNote {
+		statusText += "   Backspace: back to note"
+	}
+	statusBar := statusBarStyle.Width(w).Render(statusText)
 
 	fieldLabel := func(name string) string {
 		return formLabelStyle.Width(labelW).Align(lipgloss.Right).Render(name)
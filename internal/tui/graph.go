@@ -0,0 +1,237 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeryldev/kb/internal/graph"
+	"github.com/jeryldev/kb/internal/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Note neighborhood graph (modeGraph) ---
+
+type graphModel struct {
+	note   *model.Note
+	depth  int
+	lines  []graph.DiagramLine
+	types  map[string]string
+	cursor int
+	err    error
+}
+
+type graphLoadedMsg struct {
+	noteID string
+	lines  []graph.DiagramLine
+	types  map[string]string
+}
+
+func (a *App) switchToGraph(note *model.Note) tea.Cmd {
+	depth := a.graph.depth
+	if depth == 0 {
+		depth = 2
+	}
+	a.mode = modeGraph
+	a.graph = graphModel{note: note, depth: depth}
+	return a.loadGraph()
+}
+
+func (a *App) loadGraph() tea.Cmd {
+	note, depth := a.graph.note, a.graph.depth
+	return func() tea.Msg {
+		data, err := graph.BuildGraph(a.db, "")
+		if err != nil {
+			return errMsg{err}
+		}
+		sub, err := data.Neighborhood(note.ID, depth)
+		if err != nil {
+			return errMsg{err}
+		}
+		types := make(map[string]string, len(sub.Nodes))
+		for _, n := range sub.Nodes {
+			types[n.ID] = n.Type
+		}
+		return graphLoadedMsg{noteID: note.ID, lines: sub.Diagram(note.ID), types: types}
+	}
+}
+
+func (a *App) updateGraph(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case graphLoadedMsg:
+		if a.graph.note == nil || a.graph.note.ID != msg.noteID {
+			return a, nil
+		}
+		a.graph.lines = msg.lines
+		a.graph.types = msg.types
+		a.graph.err = nil
+		a.graph.cursor = 0
+		a.moveGraphCursor(1)
+
+	case linkTargetMsg:
+		return a, a.openLinkTarget(msg)
+
+	case noteFeedbackMsg:
+		a.graph.err = errors.New(msg.text)
+
+	case errMsg:
+		a.graph.err = msg.err
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down":
+			a.moveGraphCursor(1)
+		case "k", "up":
+			a.moveGraphCursor(-1)
+		case "h", "left":
+			a.graphToParent()
+		case "l", "right":
+			current, from := a.graph.cursor, a.graphSelected().NodeID
+			a.moveGraphCursor(1)
+			if a.graphSelected().Parent != from {
+				a.graph.cursor = current
+			}
+		case "+", "=":
+			if a.graph.depth < graph.MaxDepth {
+				a.graph.depth++
+				return a, a.loadGraph()
+			}
+		case "-":
+			if a.graph.depth > 1 {
+				a.graph.depth--
+				return a, a.loadGraph()
+			}
+		case "enter":
+			return a, a.openGraphNode()
+		case "b", "esc":
+			a.mode = modeNoteView
+		case "q":
+			return a, tea.Quit
+		}
+	}
+	return a, nil
+}
+
+func (a *App) graphSelected() graph.DiagramLine {
+	if a.graph.cursor < len(a.graph.lines) {
+		return a.graph.lines[a.graph.cursor]
+	}
+	return graph.DiagramLine{}
+}
+
+// moveGraphCursor steps to the next line holding a node, skipping the
+// frame around the center.
+func (a *App) moveGraphCursor(dir int) {
+	for i := a.graph.cursor + dir; i >= 0 && i < len(a.graph.lines); i += dir {
+		if a.graph.lines[i].NodeID != "" {
+			a.graph.cursor = i
+			return
+		}
+	}
+}
+
+func (a *App) graphToParent() {
+	parent := a.graphSelected().Parent
+	for i := a.graph.cursor - 1; i >= 0 && parent != ""; i-- {
+		if l := a.graph.lines[i]; l.NodeID == parent && l.Suffix != " ↺" {
+			a.graph.cursor = i
+			return
+		}
+	}
+}
+
+func (a *App) openGraphNode() tea.Cmd {
+	id := a.graphSelected().NodeID
+	if id == "" {
+		return nil
+	}
+	if id == a.graph.note.ID {
+		a.mode = modeNoteView
+		return nil
+	}
+	nodeType := a.graph.types[id]
+	return func() tea.Msg {
+		switch nodeType {
+		case graph.TypeCard:
+			card, boardID, err := a.db.ResolveCardRef(id)
+			if err != nil {
+				return noteFeedbackMsg{"Card not found"}
+			}
+			board, err := a.db.GetBoard(boardID)
+			if err != nil {
+				return errMsg{err}
+			}
+			return linkTargetMsg{card: card, board: board}
+		case graph.TypeBoard:
+			board, err := a.db.GetBoard(id)
+			if err != nil {
+				return errMsg{err}
+			}
+			return linkTargetMsg{board: board}
+		default:
+			note, err := a.db.GetNote(id)
+			if err != nil {
+				return errMsg{err}
+			}
+			return linkTargetMsg{note: note}
+		}
+	}
+}
+
+func (a *App) viewGraph() string {
+	w := a.width
+	if w == 0 {
+		w = 80
+	}
+	h := a.height
+	if h == 0 {
+		h = 24
+	}
+
+	title := ""
+	if a.graph.note != nil {
+		title = a.graph.note.Title
+	}
+	titleBar := titleBarStyle.Width(w).Render(fmt.Sprintf(" kb: graph — %s (depth %d) ", title, a.graph.depth))
+	statusBar := statusBarStyle.Width(w).Render(" ↑/↓: move   ←: parent   →: child   Enter: open   +/-: depth   b: back   q: quit")
+	contentH := h - lipgloss.Height(titleBar) - lipgloss.Height(statusBar) - 1
+
+	if a.graph.err != nil {
+		contentH -= 2
+	}
+
+	var rows []string
+	for i, l := range a.graph.lines {
+		label := l.Label
+		switch {
+		case i == a.graph.cursor:
+			label = mdLinkFocusedStyle.Render(label)
+		case a.graph.types[l.NodeID] == graph.TypeCard:
+			label = labelStyle.Render(label)
+		case a.graph.types[l.NodeID] == graph.TypeBoard:
+			label = columnHeaderStyle.Render(label)
+		}
+		rows = append(rows, helpStyle.Render(l.Prefix)+label+helpStyle.Render(l.Suffix))
+	}
+	if len(a.graph.lines) > 0 && len(a.graph.lines) <= 3 {
+		rows = append(rows, "", emptyColumnStyle.Render("No links to or from this note."))
+	}
+
+	// Keep the selected line in view.
+	start := 0
+	if a.graph.cursor >= contentH {
+		start = a.graph.cursor - contentH + 1
+	}
+	rows = rows[min(start, len(rows)):]
+	if len(rows) > contentH && contentH > 0 {
+		rows = rows[:contentH]
+	}
+	if a.graph.err != nil {
+		rows = append([]string{errorStyle.Render(fmt.Sprintf("Error: %v", a.graph.err)), ""}, rows...)
+	}
+
+	content := lipgloss.NewStyle().Padding(0, 1).Height(h - lipgloss.Height(titleBar) - lipgloss.Height(statusBar) - 1).Render(strings.Join(rows, "\n"))
+	return lipgloss.JoinVertical(lipgloss.Left, titleBar, content, statusBar)
+}
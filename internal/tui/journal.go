@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/jeryldev/kb/internal/journal"
+	"github.com/jeryldev/kb/internal/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type journalOpenedMsg struct {
+	note      *model.Note
+	workspace *model.Workspace
+}
+
+func (a *App) openJournalDay(day time.Time) tea.Cmd {
+	return func() tea.Msg {
+		note, _, err := journal.Open(a.db, day, journal.WorkspaceName())
+		if err != nil {
+			return errMsg{err}
+		}
+		ws, err := a.db.GetWorkspace(note.WorkspaceID)
+		if err != nil {
+			return errMsg{err}
+		}
+		return journalOpenedMsg{note: note, workspace: ws}
+	}
+}
+
+func (a *App) openToday() tea.Cmd {
+	day, _ := journal.ParseDay("today", time.Now())
+	return a.openJournalDay(day)
+}
+
+func (a *App) openAdjacentJournal(dir int) tea.Cmd {
+	note := a.noteView.note
+	if note == nil {
+		return nil
+	}
+	day, ok := journal.ParseSlug(note.Slug)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		next, err := journal.Adjacent(a.db, day, dir)
+		if err != nil {
+			return errMsg{err}
+		}
+		if next == nil {
+			if dir < 0 {
+				return noteFeedbackMsg{"No earlier journal note"}
+			}
+			return noteFeedbackMsg{"No later journal note"}
+		}
+		ws, err := a.db.GetWorkspace(next.WorkspaceID)
+		if err != nil {
+			return errMsg{err}
+		}
+		return journalOpenedMsg{note: next, workspace: ws}
+	}
+}
+
+func (a *App) showJournal(msg journalOpenedMsg) tea.Cmd {
+	a.wsContent = wsContentModel{workspace: msg.workspace}
+	return a.switchToNoteView(msg.note)
+}
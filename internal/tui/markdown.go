@@ -0,0 +1,328 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeryldev/kb/internal/model"
+)
+
+// mdLink is a wikilink found while rendering, with the rendered line its
+// block starts on so the viewer can scroll it into view.
+type mdLink struct {
+	link model.ParsedLink
+	line int
+}
+
+type mdRenderer struct {
+	width int
+	focus int // 1-based index of the focused link, 0 for none
+	out   []string
+	links []mdLink
+}
+
+var (
+	mdHeadingRe   = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdListRe      = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdTaskRe      = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdTableSepRe  = regexp.MustCompile(`^:?-+:?$`)
+	mdCodeSpanRe  = regexp.MustCompile("`[^`]+`")
+	mdLinkRe      = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mdBoldRe      = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalicRe    = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	mdPlaceholder = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// renderMarkdown renders a note body for the terminal at the given width.
+// focus is the 1-based index of the wikilink to highlight, or 0.
+func renderMarkdown(src string, width, focus int) ([]string, []mdLink) {
+	r := &mdRenderer{width: max(10, width), focus: focus}
+	r.render(strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n"), "")
+	return r.out, r.links
+}
+
+func (r *mdRenderer) render(lines []string, prefix string) {
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence := trimmed[:3]
+			lang := strings.TrimSpace(trimmed[3:])
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			r.codeBlock(code, lang, prefix)
+
+		case strings.HasPrefix(line, "┃"):
+			var inner []string
+			for ; i < len(lines) && strings.HasPrefix(lines[i], "┃"); i++ {
+				inner = append(inner, strings.TrimPrefix(strings.TrimPrefix(lines[i], "┃"), " "))
+			}
+			i--
+			r.render(inner, prefix+"┃ ")
+
+		case isTableRow(trimmed):
+			var rows []string
+			for ; i < len(lines) && isTableRow(strings.TrimSpace(lines[i])); i++ {
+				rows = append(rows, strings.TrimSpace(lines[i]))
+			}
+			i--
+			r.table(rows, prefix)
+
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			r.out = append(r.out, prefix+helpStyle.Render(strings.Repeat("─", r.width-lipgloss.Width(prefix))))
+
+		case strings.HasPrefix(trimmed, ">"):
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			r.block(mdQuoteStyle.Render("│ "), "  ", r.inline(text, mdQuoteStyle), prefix)
+
+		default:
+			if m := mdHeadingRe.FindStringSubmatch(trimmed); m != nil {
+				style := mdHeadingStyle
+				switch len(m[1]) {
+				case 1:
+					style = mdHeading1Style
+				case 2:
+					style = mdHeading2Style
+				}
+				r.block("", "", r.inline(m[2], style), prefix)
+				continue
+			}
+			if m := mdListRe.FindStringSubmatch(line); m != nil {
+				indent := strings.Repeat(" ", len(m[1]))
+				bullet := m[2]
+				text := m[3]
+				switch {
+				case mdTaskRe.MatchString(text):
+					t := mdTaskRe.FindStringSubmatch(text)
+					bullet, text = "☐", t[2]
+					if t[1] != " " {
+						bullet = "☑"
+					}
+				case bullet == "-" || bullet == "*" || bullet == "+":
+					bullet = "•"
+				}
+				bullet = mdBulletStyle.Render(bullet) + " "
+				r.block(indent+bullet, indent+strings.Repeat(" ", lipgloss.Width(bullet)), r.inline(text, lipgloss.NewStyle()), prefix)
+				continue
+			}
+			if trimmed == "" {
+				r.out = append(r.out, strings.TrimRight(prefix, " "))
+				continue
+			}
+			r.block("", "", r.inline(line, lipgloss.NewStyle()), prefix)
+		}
+	}
+}
+
+// block wraps text to the available width, starting with first and
+// indenting continuation lines with rest.
+func (r *mdRenderer) block(first, rest, text, prefix string) {
+	w := max(5, r.width-lipgloss.Width(prefix)-lipgloss.Width(first))
+	wrapped := strings.Split(lipgloss.NewStyle().Width(w).Render(text), "\n")
+	for i, l := range wrapped {
+		lead := rest
+		if i == 0 {
+			lead = first
+		}
+		r.out = append(r.out, prefix+lead+strings.TrimRight(l, " "))
+	}
+}
+
+func (r *mdRenderer) codeBlock(code []string, lang, prefix string) {
+	bar := helpStyle.Render("│ ")
+	if lang != "" {
+		r.out = append(r.out, prefix+helpStyle.Render("╭ "+lang))
+	}
+	for _, l := range code {
+		r.out = append(r.out, prefix+bar+highlightCode(l, lang))
+	}
+	if len(code) == 0 {
+		r.out = append(r.out, prefix+bar)
+	}
+}
+
+func isTableRow(s string) bool {
+	return len(s) > 1 && strings.HasPrefix(s, "|") && strings.HasSuffix(s, "|")
+}
+
+// splitTableRow splits a table row into cells, leaving escaped "\|" pipes
+// inside the cell they belong to.
+func splitTableRow(row string) []string {
+	parts := strings.Split(strings.ReplaceAll(strings.Trim(row, "|"), `\|`, "\x00"), "|")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(p, "\x00", "|")
+	}
+	return parts
+}
+
+func (r *mdRenderer) table(rows []string, prefix string) {
+	var cells [][]string
+	var widths []int
+	for _, row := range rows {
+		parts := splitTableRow(row)
+		sep := true
+		for _, p := range parts {
+			if !mdTableSepRe.MatchString(strings.TrimSpace(p)) {
+				sep = false
+			}
+		}
+		if sep {
+			continue
+		}
+		rendered := make([]string, len(parts))
+		for i, p := range parts {
+			style := lipgloss.NewStyle()
+			if len(cells) == 0 {
+				style = mdTableHeaderStyle
+			}
+			rendered[i] = r.inline(strings.TrimSpace(p), style)
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], lipgloss.Width(rendered[i]))
+		}
+		cells = append(cells, rendered)
+	}
+
+	sep := helpStyle.Render(" │ ")
+	for i, row := range cells {
+		var b strings.Builder
+		for j, w := range widths {
+			if j > 0 {
+				b.WriteString(sep)
+			}
+			cell := ""
+			if j < len(row) {
+				cell = row[j]
+			}
+			b.WriteString(cell + strings.Repeat(" ", w-lipgloss.Width(cell)))
+		}
+		r.out = append(r.out, prefix+strings.TrimRight(b.String(), " "))
+		if i == 0 && len(cells) > 1 {
+			rule := make([]string, len(widths))
+			for j, w := range widths {
+				rule[j] = strings.Repeat("─", w)
+			}
+			r.out = append(r.out, prefix+helpStyle.Render(strings.Join(rule, "─┼─")))
+		}
+	}
+}
+
+// inline styles emphasis, code spans and links in a single line of text.
+// Wikilinks are numbered in document order for focus and navigation.
+func (r *mdRenderer) inline(text string, base lipgloss.Style) string {
+	var held []string
+	hold := func(s string) string {
+		held = append(held, s)
+		return fmt.Sprintf("\x00%d\x00", len(held)-1)
+	}
+
+	text = mdCodeSpanRe.ReplaceAllStringFunc(text, func(m string) string {
+		return hold(mdInlineCodeStyle.Render(m[1 : len(m)-1]))
+	})
+	text = model.ReplaceWikilinks(text, func(link model.ParsedLink, _ string) string {
+		r.links = append(r.links, mdLink{link: link, line: len(r.out)})
+		style := mdLinkStyle
+		if len(r.links) == r.focus {
+			style = mdLinkFocusedStyle
+		}
+		display := strings.TrimSpace(link.Display)
+		switch {
+		case link.TargetType == "attachment":
+			display = "📎 " + display
+		case link.Embed:
+			display = "⤷ " + display
+		}
+		return hold(style.Render(display))
+	})
+	text = mdLinkRe.ReplaceAllStringFunc(text, func(m string) string {
+		return hold(mdURLStyle.Render(mdLinkRe.FindStringSubmatch(m)[1]))
+	})
+	text = mdBoldRe.ReplaceAllStringFunc(text, func(m string) string {
+		g := mdBoldRe.FindStringSubmatch(m)
+		return hold(base.Bold(true).Render(g[1] + g[2]))
+	})
+	text = mdItalicRe.ReplaceAllStringFunc(text, func(m string) string {
+		return hold(base.Italic(true).Render(m[1 : len(m)-1]))
+	})
+
+	parts := mdPlaceholder.Split(text, -1)
+	ids := mdPlaceholder.FindAllStringSubmatch(text, -1)
+	var b strings.Builder
+	for i, p := range parts {
+		if p != "" {
+			b.WriteString(base.Render(p))
+		}
+		if i < len(ids) {
+			var n int
+			fmt.Sscanf(ids[i][1], "%d", &n)
+			b.WriteString(held[n])
+		}
+	}
+	return b.String()
+}
+
+var (
+	codeTokenRe = regexp.MustCompile(
+		`(//.*$|--.*$)|(#.*$)|("(?:\\.|[^"\\])*"|'(?:\\.|[^'\\])*'|` + "`[^`]*`" + `)|\b(\d+(?:\.\d+)?)\b|\b([A-Za-z_]+)\b`)
+	hashComments = map[string]bool{
+		"sh": true, "bash": true, "zsh": true, "shell": true, "python": true, "py": true,
+		"ruby": true, "rb": true, "yaml": true, "yml": true, "toml": true, "elixir": true, "ex": true,
+	}
+	dashComments = map[string]bool{"sql": true, "lua": true, "haskell": true, "hs": true}
+	codeKeywords = map[string]bool{
+		"as": true, "async": true, "await": true, "break": true, "case": true, "catch": true,
+		"chan": true, "class": true, "const": true, "continue": true, "def": true, "default": true,
+		"defer": true, "do": true, "done": true, "elif": true, "else": true, "end": true,
+		"enum": true, "except": true, "export": true, "false": true, "fi": true, "fn": true,
+		"for": true, "from": true, "func": true, "function": true, "go": true, "if": true,
+		"impl": true, "import": true, "in": true, "interface": true, "let": true, "map": true,
+		"match": true, "mut": true, "new": true, "nil": true, "null": true, "package": true,
+		"pub": true, "raise": true, "range": true, "return": true, "select": true, "self": true,
+		"struct": true, "switch": true, "then": true, "this": true, "throw": true, "true": true,
+		"try": true, "type": true, "use": true, "var": true, "while": true, "with": true,
+		"yield": true, "None": true, "True": true, "False": true, "SELECT": true, "FROM": true,
+		"WHERE": true, "INSERT": true, "UPDATE": true, "DELETE": true, "JOIN": true,
+	}
+)
+
+// highlightCode applies a small, language-agnostic highlighter: comments,
+// strings, numbers and common keywords.
+func highlightCode(line, lang string) string {
+	lang = strings.ToLower(lang)
+	var b strings.Builder
+	last := 0
+	for _, m := range codeTokenRe.FindAllStringSubmatchIndex(line, -1) {
+		b.WriteString(line[last:m[0]])
+		tok := line[m[0]:m[1]]
+		switch {
+		case m[2] >= 0 && (!strings.HasPrefix(tok, "--") || dashComments[lang]):
+			b.WriteString(mdCommentStyle.Render(tok))
+		case m[2] >= 0:
+			// "--" outside SQL-like languages is an operator; only the
+			// dashes are consumed so the rest is highlighted normally.
+			b.WriteString("--" + highlightCode(tok[2:], lang))
+		case m[4] >= 0 && hashComments[lang]:
+			b.WriteString(mdCommentStyle.Render(tok))
+		case m[4] >= 0:
+			b.WriteString("#" + highlightCode(tok[1:], lang))
+		case m[6] >= 0:
+			b.WriteString(mdStringStyle.Render(tok))
+		case m[8] >= 0:
+			b.WriteString(mdNumberStyle.Render(tok))
+		case codeKeywords[tok]:
+			b.WriteString(mdKeywordStyle.Render(tok))
+		default:
+			b.WriteString(tok)
+		}
+		last = m[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownBlocks(t *testing.T) {
+	src := strings.Join([]string{
+		"# Title",
+		"Some **bold** and `code` text.",
+		"- item one",
+		"  - nested",
+		"- [ ] todo",
+		"- [x] done",
+		"1. first",
+		"> quoted",
+		"---",
+		"| Name | Value |",
+		"|------|-------|",
+		"| a | longer value |",
+		"```go",
+		"func main() { // entry",
+		"```",
+	}, "\n")
+
+	lines, _ := renderMarkdown(src, 40, 0)
+	got := strings.Join(lines, "\n")
+
+	for _, want := range []string{
+		"Title",
+		"Some bold and code text.",
+		"• item one",
+		"  • nested",
+		"☐ todo",
+		"☑ done",
+		"1. first",
+		"│ quoted",
+		strings.Repeat("─", 40),
+		"Name │ Value",
+		"─────┼─",
+		"a    │ longer value",
+		"╭ go",
+		"│ func main() { // entry",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in rendered markdown:\n%s", want, got)
+		}
+	}
+	for _, raw := range []string{"# Title", "**bold**", "`code`", "```", "|---"} {
+		if strings.Contains(got, raw) {
+			t.Errorf("raw markdown %q should not be shown:\n%s", raw, got)
+		}
+	}
+}
+
+func TestRenderMarkdownWraps(t *testing.T) {
+	lines, _ := renderMarkdown("- "+strings.Repeat("word ", 10), 20, 0)
+	if len(lines) < 3 {
+		t.Fatalf("expected list item to wrap, got %q", lines)
+	}
+	if !strings.HasPrefix(lines[1], "  ") {
+		t.Errorf("expected continuation lines to hang under the bullet, got %q", lines[1])
+	}
+}
+
+func TestRenderMarkdownLinks(t *testing.T) {
+	src := "See [[design|the design]] and [[card:Fix login]].\n\n```\n[[not-a-link]]\n```\n\n## [[board:sprint]]"
+	lines, links := renderMarkdown(src, 60, 2)
+
+	if len(links) != 3 {
+		t.Fatalf("expected 3 links outside code blocks, got %d", len(links))
+	}
+	if links[0].link.TargetRef != "design" || links[1].link.TargetType != "card" || links[2].link.TargetType != "board" {
+		t.Errorf("unexpected links: %+v", links)
+	}
+	if links[2].line != len(lines)-1 {
+		t.Errorf("link line = %d, want %d", links[2].line, len(lines)-1)
+	}
+	if got := strings.Join(lines, "\n"); !strings.Contains(got, "See the design and Fix login.") {
+		t.Errorf("expected link display text, got:\n%s", got)
+	}
+}
+
+func TestRenderMarkdownTableLinks(t *testing.T) {
+	lines, links := renderMarkdown("| Card | Column |\n| --- | --- |\n| [[card:abc\\|Fix login]] | Todo |", 60, 0)
+	if len(links) != 1 || links[0].link.TargetRef != "abc" {
+		t.Fatalf("expected an escaped link inside the table, got %+v", links)
+	}
+	if got := strings.Join(lines, "\n"); !strings.Contains(got, "Fix login") || strings.Contains(got, `\`) {
+		t.Errorf("expected one cell per column, got:\n%s", got)
+	}
+}
+
+func TestRenderMarkdownEmbedBlock(t *testing.T) {
+	lines, links := renderMarkdown("┃ ⤷ design\n┃ ## API\n┃ see [[other]]", 40, 0)
+	got := strings.Join(lines, "\n")
+	if !strings.Contains(got, "┃ API") || strings.Contains(got, "##") {
+		t.Errorf("expected embedded markdown to render inside the bar:\n%s", got)
+	}
+	if len(links) != 1 {
+		t.Errorf("expected links inside embeds to be focusable, got %d", len(links))
+	}
+}
+
+func TestHighlightCode(t *testing.T) {
+	if got := highlightCode(`x := "a // b" // note`, "go"); got != `x := "a // b" // note` {
+		t.Errorf("highlighting should not change text, got %q", got)
+	}
+	if got := highlightCode("a -- b # c", "python"); got != "a -- b # c" {
+		t.Errorf("highlighting should not change text, got %q", got)
+	}
+}
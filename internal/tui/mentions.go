@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Unlinked mentions of a note (modeMentions) ---
+
+type mentionsModel struct {
+	note     *model.Note
+	mentions []*store.Mention
+	selected map[int]bool
+	cursor   int
+	loaded   bool
+	feedback string
+	err      error
+}
+
+type mentionsLoadedMsg struct {
+	noteID   string
+	mentions []*store.Mention
+	feedback string
+}
+
+func (a *App) switchToMentions(note *model.Note) tea.Cmd {
+	a.mode = modeMentions
+	a.mentions = mentionsModel{note: note, selected: make(map[int]bool)}
+	return a.loadMentions("")
+}
+
+func (a *App) loadMentions(feedback string) tea.Cmd {
+	note := a.mentions.note
+	return func() tea.Msg {
+		mentions, err := a.db.FindMentions(note)
+		if err != nil {
+			return errMsg{err}
+		}
+		return mentionsLoadedMsg{noteID: note.ID, mentions: mentions, feedback: feedback}
+	}
+}
+
+// linkMentions links the selected mentions, or the one under the cursor
+// when none are selected.
+func (a *App) linkMentions() tea.Cmd {
+	var picked []*store.Mention
+	for i, m := range a.mentions.mentions {
+		if a.mentions.selected[i] {
+			picked = append(picked, m)
+		}
+	}
+	if len(picked) == 0 && a.mentions.cursor < len(a.mentions.mentions) {
+		picked = append(picked, a.mentions.mentions[a.mentions.cursor])
+	}
+	if len(picked) == 0 {
+		return nil
+	}
+	note := a.mentions.note
+	return func() tea.Msg {
+		linked, err := a.db.LinkMentions(note, picked)
+		if err != nil {
+			return errMsg{err}
+		}
+		return a.loadMentions(fmt.Sprintf("Linked %d mention(s)", linked))()
+	}
+}
+
+func (a *App) updateMentions(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case mentionsLoadedMsg:
+		if a.mentions.note == nil || a.mentions.note.ID != msg.noteID {
+			return a, nil
+		}
+		a.mentions.mentions = msg.mentions
+		a.mentions.selected = make(map[int]bool)
+		a.mentions.cursor = min(a.mentions.cursor, max(0, len(msg.mentions)-1))
+		a.mentions.loaded = true
+		a.mentions.feedback = msg.feedback
+		a.mentions.err = nil
+
+	case linkTargetMsg:
+		return a, a.openLinkTarget(msg)
+
+	case errMsg:
+		a.mentions.err = msg.err
+
+	case tea.KeyMsg:
+		a.mentions.feedback = ""
+		switch msg.String() {
+		case "j", "down":
+			if a.mentions.cursor < len(a.mentions.mentions)-1 {
+				a.mentions.cursor++
+			}
+		case "k", "up":
+			if a.mentions.cursor > 0 {
+				a.mentions.cursor--
+			}
+		case " ", "x":
+			if c := a.mentions.cursor; a.mentions.selected[c] {
+				delete(a.mentions.selected, c)
+			} else if c < len(a.mentions.mentions) {
+				a.mentions.selected[c] = true
+			}
+		case "A":
+			all := len(a.mentions.selected) < len(a.mentions.mentions)
+			a.mentions.selected = make(map[int]bool)
+			for i := range a.mentions.mentions {
+				if all {
+					a.mentions.selected[i] = true
+				}
+			}
+		case "l":
+			return a, a.linkMentions()
+		case "enter":
+			return a, a.openMentionSource()
+		case "b", "esc":
+			a.mode = modeNoteView
+			return a, a.loadNoteBacklinks(a.mentions.note)
+		case "q":
+			return a, tea.Quit
+		}
+	}
+	return a, nil
+}
+
+func (a *App) openMentionSource() tea.Cmd {
+	if a.mentions.cursor >= len(a.mentions.mentions) {
+		return nil
+	}
+	m := a.mentions.mentions[a.mentions.cursor]
+	return func() tea.Msg {
+		if m.SourceType == "card" {
+			card, boardID, err := a.db.ResolveCardRef(m.SourceID)
+			if err != nil {
+				return errMsg{err}
+			}
+			board, err := a.db.GetBoard(boardID)
+			if err != nil {
+				return errMsg{err}
+			}
+			return linkTargetMsg{card: card, board: board}
+		}
+		note, err := a.db.GetNote(m.SourceID)
+		if err != nil {
+			return errMsg{err}
+		}
+		return linkTargetMsg{note: note}
+	}
+}
+
+func (a *App) viewMentions() string {
+	w := a.width
+	if w == 0 {
+		w = 80
+	}
+	h := a.height
+	if h == 0 {
+		h = 24
+	}
+
+	title := ""
+	if a.mentions.note != nil {
+		title = a.mentions.note.Title
+	}
+	titleBar := titleBarStyle.Width(w).Render(fmt.Sprintf(" kb: unlinked mentions — %s (%d) ", title, len(a.mentions.mentions)))
+	statusBar := statusBarStyle.Width(w).Render(" ↑/↓: move   space: select   A: all   l: link   Enter: open   b: back   q: quit")
+	contentH := h - lipgloss.Height(titleBar) - lipgloss.Height(statusBar) - 1
+
+	var rows []string
+	if a.mentions.err != nil {
+		rows = append(rows, errorStyle.Render(fmt.Sprintf("Error: %v", a.mentions.err)), "")
+	} else if a.mentions.feedback != "" {
+		rows = append(rows, helpStyle.Render(a.mentions.feedback), "")
+	}
+	header := len(rows)
+
+	if a.mentions.loaded && len(a.mentions.mentions) == 0 {
+		rows = append(rows, emptyColumnStyle.Render("No unlinked mentions of this note."))
+	}
+	for i, m := range a.mentions.mentions {
+		check := "[ ]"
+		if a.mentions.selected[i] {
+			check = "[x]"
+		}
+		source := fmt.Sprintf("[[%s]]:%d", m.Source, m.Line)
+		style := lipgloss.NewStyle()
+		if m.SourceType == "card" {
+			source = fmt.Sprintf("[[card:%s]] (%s)", m.Source, m.Board)
+			style = labelStyle
+		}
+		if i == a.mentions.cursor {
+			style = mdLinkFocusedStyle
+		}
+		source = style.Render(source)
+		rows = append(rows,
+			fmt.Sprintf("%s %s", check, source),
+			"    "+helpStyle.Render(truncate(strings.TrimSpace(m.Context), max(10, w-8))),
+		)
+	}
+
+	// Keep the selected mention in view.
+	start := 0
+	if cursorRow := header + a.mentions.cursor*2 + 1; cursorRow >= contentH {
+		start = cursorRow - contentH + 1
+	}
+	rows = rows[min(start, len(rows)):]
+	if len(rows) > contentH && contentH > 0 {
+		rows = rows[:contentH]
+	}
+
+	content := lipgloss.NewStyle().Padding(0, 1).Height(contentH).Render(strings.Join(rows, "\n"))
+	return lipgloss.JoinVertical(lipgloss.Left, titleBar, content, statusBar)
+}
@@ -9,7 +9,10 @@ import (
 	"sync"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jeryldev/kb/internal/journal"
 	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/query"
+	"github.com/jeryldev/kb/internal/store"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -21,6 +24,7 @@ var (
 
 type noteListModel struct {
 	notes       []*model.Note
+	aliases     map[string][]string
 	cursor      int
 	filter      string
 	filterInput string
@@ -35,13 +39,20 @@ type backlinkDisplay struct {
 
 type noteViewModel struct {
 	note       *model.Note
+	body       string
 	backlinks  []backlinkDisplay
+	related    []*store.RelatedNote
 	scroll     int
 	confirming string
+	feedback   string
+	linkFocus  int // 1-based index of the focused link, 0 for none
+	revealLink bool
+	history    []*model.Note
 }
 
 type notesLoadedMsg struct {
-	notes []*model.Note
+	notes   []*model.Note
+	aliases map[string][]string
 }
 
 type noteBacklinksMsg struct {
@@ -49,13 +60,78 @@ type noteBacklinksMsg struct {
 	backlinks []backlinkDisplay
 }
 
+type noteRelatedMsg struct {
+	noteID  string
+	related []*store.RelatedNote
+}
+
 type noteEditedMsg struct {
 	note *model.Note
 }
 
+type noteEmbedsMsg struct {
+	noteID string
+	body   string
+}
+
+type noteFeedbackMsg struct {
+	text string
+}
+
+type linkTargetMsg struct {
+	note  *model.Note
+	card  *model.Card
+	board *model.Board
+}
+
 func (a *App) switchToNoteView(note *model.Note) tea.Cmd {
 	a.mode = modeNoteView
 	a.noteView = noteViewModel{note: note}
+	return tea.Batch(a.loadNoteBacklinks(note), a.loadNoteEmbeds(note), a.loadNoteRelated(note))
+}
+
+// maxRelatedNotes is how many suggestions the note view lists.
+const maxRelatedNotes = 5
+
+func (a *App) loadNoteRelated(note *model.Note) tea.Cmd {
+	return func() tea.Msg {
+		related, err := a.db.RelatedNotes(note.ID, maxRelatedNotes)
+		if err != nil {
+			return errMsg{err}
+		}
+		return noteRelatedMsg{noteID: note.ID, related: related}
+	}
+}
+
+func (a *App) loadNoteEmbeds(note *model.Note) tea.Cmd {
+	return func() tea.Msg {
+		return noteEmbedsMsg{noteID: note.ID, body: a.expandEmbeds(query.Expand(a.db, note.Body), 0)}
+	}
+}
+
+func (a *App) expandEmbeds(body string, depth int) string {
+	return model.ReplaceWikilinks(body, func(link model.ParsedLink, raw string) string {
+		if !link.Embed || link.TargetType != "note" || link.TargetRef == "" {
+			return raw
+		}
+		header := "┃ ⤷ " + link.Display
+		content := ""
+		target, err := a.db.GetNoteByRef(link.TargetRef)
+		if err != nil {
+			content = "(note not found)"
+		} else if fragment, ok := model.ExtractFragment(model.StripFrontMatter(target.Body), link); !ok {
+			content = fmt.Sprintf("(%s not found)", link.Fragment())
+		} else if depth >= model.MaxEmbedDepth {
+			content = "(embed depth exceeded)"
+		} else {
+			content = a.expandEmbeds(model.StripBlockIDs(fragment), depth+1)
+		}
+		lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+		return header + "\n┃ " + strings.Join(lines, "\n┃ ")
+	})
+}
+
+func (a *App) loadNoteBacklinks(note *model.Note) tea.Cmd {
 	return func() tea.Msg {
 		links, err := a.db.GetBacklinks("note", note.ID)
 		if err != nil {
@@ -68,6 +144,12 @@ func (a *App) switchToNoteView(note *model.Note) tea.Cmd {
 			if err == nil && sourceNote != nil {
 				label = fmt.Sprintf("[[%s]] %s", sourceNote.Slug, sourceNote.Title)
 			}
+			if bl.Fragment != "" {
+				label += " → " + bl.Fragment
+			}
+			if bl.Relation != "" {
+				label += " (" + bl.Relation + ")"
+			}
 			blds = append(blds, backlinkDisplay{label: label, context: bl.Context})
 		}
 		return noteBacklinksMsg{note: note, backlinks: blds}
@@ -76,10 +158,35 @@ func (a *App) switchToNoteView(note *model.Note) tea.Cmd {
 
 // --- Note List Mode ---
 
+// switchToNoteList opens the workspace's notes with the filter prompt
+// ready.
+func (a *App) switchToNoteList() tea.Cmd {
+	a.mode = modeNotes
+	a.noteList = noteListModel{filtering: true}
+	return a.loadNoteList(a.wsContent.workspace.ID)
+}
+
+// loadNoteList loads a workspace's notes and their aliases, which the
+// filter matches as well as titles, slugs and tags.
+func (a *App) loadNoteList(workspaceID string) tea.Cmd {
+	return func() tea.Msg {
+		notes, err := a.db.ListNotesByWorkspace(workspaceID)
+		if err != nil {
+			return errMsg{err}
+		}
+		aliases, err := a.db.ListAllNoteAliases()
+		if err != nil {
+			return errMsg{err}
+		}
+		return notesLoadedMsg{notes: notes, aliases: aliases}
+	}
+}
+
 func (a *App) updateNoteList(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case notesLoadedMsg:
 		a.noteList.notes = msg.notes
+		a.noteList.aliases = msg.aliases
 		if a.noteList.cursor >= len(msg.notes) && len(msg.notes) > 0 {
 			a.noteList.cursor = len(msg.notes) - 1
 		}
@@ -156,13 +263,23 @@ func (a *App) filteredNotes() []*model.Note {
 	for _, n := range a.noteList.notes {
 		if strings.Contains(strings.ToLower(n.Title), f) ||
 			strings.Contains(strings.ToLower(n.Slug), f) ||
-			strings.Contains(strings.ToLower(n.Tags), f) {
+			strings.Contains(strings.ToLower(n.Tags), f) ||
+			matchesAlias(a.noteList.aliases[n.ID], f) {
 			result = append(result, n)
 		}
 	}
 	return result
 }
 
+func matchesAlias(aliases []string, filter string) bool {
+	for _, alias := range aliases {
+		if strings.Contains(strings.ToLower(alias), filter) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *App) viewNoteList() string {
 	w := a.width
 	if w == 0 {
@@ -268,12 +385,38 @@ func (a *App) updateNoteView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case noteBacklinksMsg:
 		a.noteView.backlinks = msg.backlinks
 
+	case noteRelatedMsg:
+		if a.noteView.note != nil && a.noteView.note.ID == msg.noteID {
+			a.noteView.related = msg.related
+		}
+
+	case noteEmbedsMsg:
+		if a.noteView.note != nil && a.noteView.note.ID == msg.noteID {
+			a.noteView.body = msg.body
+		}
+
 	case noteEditedMsg:
 		a.noteView.note = msg.note
+		a.noteView.body = ""
+		return a, a.loadNoteEmbeds(msg.note)
+
+	case journalOpenedMsg:
+		return a, a.showJournal(msg)
 
-	case noteDeletedMsg:
+	case noteFeedbackMsg:
+		a.noteView.feedback = msg.text
+
+	case linkTargetMsg:
+		return a, a.openLinkTarget(msg)
+
+	case noteDeletedMsg, noteArchivedMsg:
 		if a.wsContent.workspace != nil {
-			return a, a.switchToWSContent(a.wsContent.workspace)
+			cmd := a.switchToWSContent(a.wsContent.workspace)
+			a.wsContent.feedback = "Note moved to trash"
+			if _, ok := msg.(noteArchivedMsg); ok {
+				a.wsContent.feedback = "Note archived"
+			}
+			return a, cmd
 		}
 		a.mode = modePicker
 		return a, a.initPicker()
@@ -291,10 +434,17 @@ func (a *App) updateNoteView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a.updateNoteViewConfirming(msg)
 		}
 
+		a.noteView.feedback = ""
 		switch msg.String() {
 		case "q":
 			return a, tea.Quit
 		case "b", "esc":
+			if cmd, ok := a.returnToTasks(); ok {
+				return a, cmd
+			}
+			if a.returnToReview() {
+				return a, nil
+			}
 			if a.wsContent.workspace != nil {
 				return a, a.switchToWSContent(a.wsContent.workspace)
 			}
@@ -302,22 +452,143 @@ func (a *App) updateNoteView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, a.initPicker()
 		case "e":
 			return a, a.editNoteExternal()
+		case "t":
+			return a, a.openToday()
+		case "[":
+			return a, a.openAdjacentJournal(-1)
+		case "]":
+			return a, a.openAdjacentJournal(1)
 		case "d":
 			a.noteView.confirming = "delete"
+		case "a":
+			a.noteView.confirming = "archive"
+		case "g":
+			return a, a.switchToGraph(a.noteView.note)
+		case "m":
+			return a, a.switchToMentions(a.noteView.note)
 		case "j", "down":
 			a.noteView.scroll++
 		case "k", "up":
 			if a.noteView.scroll > 0 {
 				a.noteView.scroll--
 			}
+		case "tab":
+			a.focusNoteLink(1)
+		case "shift+tab":
+			a.focusNoteLink(-1)
+		case "enter":
+			return a, a.followNoteLink()
+		case "backspace":
+			return a, a.noteViewBack()
 		}
 	}
 	return a, nil
 }
 
+func (a *App) noteLinks() []mdLink {
+	text := a.noteView.note.Body
+	if a.noteView.body != "" {
+		text = a.noteView.body
+	}
+	_, links := renderMarkdown(model.StripFrontMatter(text), a.width, 0)
+	return links
+}
+
+func (a *App) focusNoteLink(dir int) {
+	n := len(a.noteLinks())
+	if n == 0 {
+		return
+	}
+	focus := a.noteView.linkFocus + dir
+	switch {
+	case focus > n:
+		focus = 1
+	case focus < 1:
+		focus = n
+	}
+	a.noteView.linkFocus = focus
+	a.noteView.revealLink = true
+}
+
+func (a *App) followNoteLink() tea.Cmd {
+	links := a.noteLinks()
+	if a.noteView.linkFocus < 1 || a.noteView.linkFocus > len(links) {
+		return nil
+	}
+	link := links[a.noteView.linkFocus-1].link
+	if link.TargetRef == "" {
+		return nil
+	}
+	noteID := a.noteView.note.ID
+	return func() tea.Msg {
+		switch link.TargetType {
+		case "card":
+			card, boardID, err := a.db.ResolveCardRef(link.TargetRef)
+			if err != nil {
+				return noteFeedbackMsg{fmt.Sprintf("Card %q not found", link.TargetRef)}
+			}
+			board, err := a.db.GetBoard(boardID)
+			if err != nil {
+				return errMsg{err}
+			}
+			return linkTargetMsg{card: card, board: board}
+		case "board":
+			board, err := a.db.GetBoardByName(link.TargetRef)
+			if err != nil {
+				return errMsg{err}
+			}
+			if board == nil {
+				return noteFeedbackMsg{fmt.Sprintf("Board %q not found", link.TargetRef)}
+			}
+			return linkTargetMsg{board: board}
+		case "attachment":
+			att, err := a.db.GetAttachmentByName("note", noteID, link.TargetRef)
+			if err != nil {
+				return noteFeedbackMsg{fmt.Sprintf("Attachment %q not found", link.TargetRef)}
+			}
+			return noteFeedbackMsg{fmt.Sprintf("%s (%s) is stored at %s", att.Name, model.FormatSize(att.Size), a.db.AttachmentPath(att))}
+		default:
+			note, err := a.db.GetNoteByRef(link.TargetRef)
+			if err != nil {
+				return noteFeedbackMsg{fmt.Sprintf("Note %q not found", link.TargetRef)}
+			}
+			return linkTargetMsg{note: note}
+		}
+	}
+}
+
+// openLinkTarget follows a link from the current note, remembering the
+// note so backspace can return to it.
+func (a *App) openLinkTarget(msg linkTargetMsg) tea.Cmd {
+	history := append(a.noteView.history, a.noteView.note)
+	if msg.note != nil {
+		cmd := a.switchToNoteView(msg.note)
+		a.noteView.history = history
+		return cmd
+	}
+	cmd := a.switchToBoard(msg.board)
+	a.board.fromNote = true
+	if msg.card != nil {
+		a.board.openCardID = msg.card.ID
+	}
+	return cmd
+}
+
+func (a *App) noteViewBack() tea.Cmd {
+	n := len(a.noteView.history)
+	if n == 0 {
+		return nil
+	}
+	prev, history := a.noteView.history[n-1], a.noteView.history[:n-1]
+	cmd := a.switchToNoteView(prev)
+	a.noteView.history = history
+	return cmd
+}
+
 func (a *App) updateNoteViewConfirming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "y", "Y":
+		action := a.noteView.confirming
 		a.noteView.confirming = ""
 		note := a.noteView.note
 		if note == nil {
@@ -327,6 +598,14 @@ func (a *App) updateNoteViewConfirming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if a.wsContent.workspace != nil {
 			wsID = a.wsContent.workspace.ID
 		}
+		if action == "archive" {
+			return a, func() tea.Msg {
+				if err := a.db.ArchiveNote(note.ID); err != nil {
+					return errMsg{err}
+				}
+				return noteArchivedMsg{workspaceID: wsID}
+			}
+		}
 		return a, func() tea.Msg {
 			if err := a.db.DeleteNote(note.ID); err != nil {
 				return errMsg{err}
@@ -339,6 +618,13 @@ func (a *App) updateNoteViewConfirming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+func noteConfirmPrompt(action, title string) string {
+	if action == "archive" {
+		return fmt.Sprintf("Archive note %q?", truncate(title, 30))
+	}
+	return fmt.Sprintf("Delete note %q? (moves to trash)", truncate(title, 30))
+}
+
 // --- Note Edit (external editor) ---
 
 func resolveEditor() string {
@@ -361,27 +647,25 @@ func editorDisplayName(editor string) string {
 	return filepath.Base(editor)
 }
 
-func (a *App) editNoteExternal() tea.Cmd {
-	note := a.noteView.note
-	if note == nil {
-		return nil
-	}
-
+// EditorCommand writes the body of note to a temporary file and returns
+// the command that opens it in the user's editor, along with the file's
+// path. The caller removes the file.
+func EditorCommand(note *model.Note) (*exec.Cmd, string, error) {
 	editor := resolveEditor()
 	if editor == "" {
-		return nil
+		return nil, "", fmt.Errorf("no editor found; set $EDITOR")
 	}
 
 	tmpFile, err := os.CreateTemp("", fmt.Sprintf("kb-note-%s-*.md", note.Slug))
 	if err != nil {
-		return func() tea.Msg { return errMsg{err} }
+		return nil, "", fmt.Errorf("creating temp file: %w", err)
 	}
 	tmpPath := tmpFile.Name()
 
 	if _, err := tmpFile.WriteString(note.Body); err != nil {
 		tmpFile.Close()
 		os.Remove(tmpPath)
-		return func() tea.Msg { return errMsg{err} }
+		return nil, "", fmt.Errorf("writing temp file: %w", err)
 	}
 	tmpFile.Close()
 
@@ -389,6 +673,22 @@ func (a *App) editNoteExternal() tea.Cmd {
 	c.Stdin = os.Stdin
 	c.Stdout = os.Stdout
 	c.Stderr = os.Stderr
+	return c, tmpPath, nil
+}
+
+func (a *App) editNoteExternal() tea.Cmd {
+	note := a.noteView.note
+	if note == nil {
+		return nil
+	}
+
+	if resolveEditor() == "" {
+		return nil
+	}
+	c, tmpPath, err := EditorCommand(note)
+	if err != nil {
+		return func() tea.Msg { return errMsg{err} }
+	}
 
 	noteID := note.ID
 	db := a.db
@@ -433,13 +733,21 @@ func (a *App) viewNoteDetail() string {
 	if editor := resolveEditor(); editor != "" {
 		editHint = fmt.Sprintf("e: edit (%s)", editorDisplayName(editor))
 	}
-	statusBar := statusBarStyle.Width(w).Render(fmt.Sprintf(" j/k: scroll   %s   d: delete   b: back   q: quit", editHint))
+	dayHint := ""
+	if _, ok := journal.ParseSlug(note.Slug); ok {
+		dayHint = "[/]: prev/next day   "
+	}
+	backHint := "b: back"
+	if len(a.noteView.history) > 0 {
+		backHint = "Backspace: prev note   b: back"
+	}
+	statusBar := statusBarStyle.Width(w).Render(fmt.Sprintf(" j/k: scroll   Tab: links   Enter: follow   %s   %st: today   a: archive   d: delete   g: graph   m: mentions   %s   q: quit", editHint, dayHint, backHint))
 
 	contentH := h - lipgloss.Height(titleBar) - lipgloss.Height(statusBar) - 1
 	contentW := max(20, w-4)
 
 	if a.noteView.confirming != "" {
-		content := renderCenteredConfirm(w, contentH, fmt.Sprintf("Delete note %q?", note.Title))
+		content := renderCenteredConfirm(w, contentH, noteConfirmPrompt(a.noteView.confirming, note.Title))
 		return lipgloss.JoinVertical(lipgloss.Left, titleBar, content, statusBar)
 	}
 
@@ -450,12 +758,29 @@ func (a *App) viewNoteDetail() string {
 	if note.Tags != "" {
 		meta += "   " + labelStyle.Render("["+note.Tags+"]")
 	}
+	if a.noteView.feedback != "" {
+		meta += "   " + helpStyle.Render(a.noteView.feedback)
+	}
 	sections = append(sections, meta, "")
 
+	// Properties
+	if props := model.ParseFrontMatter(note.Body); len(props) > 0 {
+		sections = append(sections, propertiesHeader(props)...)
+		sections = append(sections, "")
+	}
+
 	// Body
-	if note.Body != "" {
-		body := lipgloss.NewStyle().Width(contentW).Render(note.Body)
-		sections = append(sections, body)
+	linkLine := -1
+	if strings.TrimSpace(model.StripFrontMatter(note.Body)) != "" {
+		text := note.Body
+		if a.noteView.body != "" {
+			text = a.noteView.body
+		}
+		body, links := renderMarkdown(model.StripFrontMatter(text), contentW, a.noteView.linkFocus)
+		if f := a.noteView.linkFocus; f > 0 && f <= len(links) {
+			linkLine = len(strings.Split(strings.Join(sections, "\n"), "\n")) + links[f-1].line
+		}
+		sections = append(sections, body...)
 	} else {
 		sections = append(sections, emptyColumnStyle.Render("(empty note)"))
 	}
@@ -476,10 +801,29 @@ func (a *App) viewNoteDetail() string {
 		}
 	}
 
+	// Related notes that are not linked yet
+	if len(a.noteView.related) > 0 {
+		sections = append(sections, "")
+		sections = append(sections, lipgloss.NewStyle().Bold(true).Underline(true).Render(
+			fmt.Sprintf("Related (%d)", len(a.noteView.related))))
+		for _, r := range a.noteView.related {
+			score := helpStyle.Render(fmt.Sprintf("%.2f", r.Score))
+			sections = append(sections, fmt.Sprintf("  [[%s]] %s  %s", r.Note.Slug, r.Note.Title, score))
+		}
+	}
+
 	allContent := strings.Join(sections, "\n")
 	lines := strings.Split(allContent, "\n")
 
 	// Apply scroll
+	if a.noteView.revealLink && linkLine >= 0 {
+		if linkLine < a.noteView.scroll {
+			a.noteView.scroll = linkLine
+		} else if linkLine >= a.noteView.scroll+contentH {
+			a.noteView.scroll = linkLine - contentH + 1
+		}
+	}
+	a.noteView.revealLink = false
 	if a.noteView.scroll > len(lines)-contentH {
 		a.noteView.scroll = max(0, len(lines)-contentH)
 	}
@@ -491,3 +835,16 @@ func (a *App) viewNoteDetail() string {
 	content := lipgloss.NewStyle().Height(contentH).Render(inner)
 	return lipgloss.JoinVertical(lipgloss.Left, titleBar, content, statusBar)
 }
+
+func propertiesHeader(props []model.Property) []string {
+	width := 0
+	for _, p := range props {
+		width = max(width, len(p.Key))
+	}
+	lines := make([]string, len(props))
+	for i, p := range props {
+		key := labelStyle.Render(fmt.Sprintf("%-*s", width, p.Key))
+		lines[i] = key + "  " + p.String()
+	}
+	return lines
+}
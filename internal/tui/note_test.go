@@ -2,12 +2,15 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
 )
 
 func testNotes() []*model.Note {
@@ -116,6 +119,42 @@ func TestNoteListFilterByTag(t *testing.T) {
 	}
 }
 
+func TestNoteListFilterByAlias(t *testing.T) {
+	app := testNoteApp(testNotes())
+	app.noteList.aliases = map[string][]string{"n3": {"ADR-7"}}
+
+	app.noteList.filter = "adr"
+	filtered := app.filteredNotes()
+
+	if len(filtered) != 1 || filtered[0].Slug != "gamma-note" {
+		t.Errorf("expected alias to match gamma-note, got %d notes", len(filtered))
+	}
+}
+
+func TestNoteListLoadsAliases(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	note, _ := app.db.CreateNote("Use SQLite", "use-sqlite", "", ws.ID)
+	app.db.CreateNote("Other", "other", "", ws.ID)
+	app.db.AddNoteAlias(note.ID, "ADR-7")
+
+	app.switchToWSContent(ws)
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if app.mode != modeNotes || !app.noteList.filtering {
+		t.Fatalf("expected the note list with the filter open, got mode %d", app.mode)
+	}
+	app.Update(cmd())
+	for _, r := range "adr-7" {
+		app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	filtered := app.filteredNotes()
+	if len(filtered) != 1 || filtered[0].ID != note.ID {
+		t.Errorf("expected the alias to find the note, got %v", filtered)
+	}
+}
+
 func TestNoteListFilterEscape(t *testing.T) {
 	app := testNoteApp(testNotes())
 
@@ -243,6 +282,34 @@ func TestNoteViewContent(t *testing.T) {
 	}
 }
 
+func TestNoteViewPropertiesHeader(t *testing.T) {
+	app := &App{
+		mode: modeNoteView,
+		noteView: noteViewModel{
+			note: &model.Note{
+				ID:        "n1",
+				Title:     "Plan",
+				Slug:      "plan",
+				Body:      "---\nstatus: draft\nowners: [ana, ben]\n---\nThe plan.",
+				UpdatedAt: time.Now(),
+			},
+		},
+		width:  80,
+		height: 30,
+	}
+
+	view := app.viewNoteDetail()
+	if !strings.Contains(view, "status  draft") || !strings.Contains(view, "owners  ana, ben") {
+		t.Errorf("expected properties header in view:\n%s", view)
+	}
+	if strings.Contains(view, "---") || strings.Contains(view, "status: draft") {
+		t.Errorf("raw front matter should not be shown:\n%s", view)
+	}
+	if !strings.Contains(view, "The plan.") {
+		t.Errorf("missing body in view:\n%s", view)
+	}
+}
+
 func TestNoteViewEmptyBody(t *testing.T) {
 	app := &App{
 		mode: modeNoteView,
@@ -292,6 +359,22 @@ func TestNoteViewWithBacklinks(t *testing.T) {
 	}
 }
 
+func TestNoteViewRelated(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	note, _ := app.db.CreateNote("Postgres indexes", "postgres-indexes", "B-tree indexes speed up postgres queries.", ws.ID)
+	app.db.CreateNote("Query planning", "query-planning", "The planner picks indexes for each query.", ws.ID)
+	app.db.CreateNote("Gardening", "gardening", "Tomatoes need sun and water.", ws.ID)
+
+	app.switchToNoteView(note)
+	app.updateNoteView(app.loadNoteRelated(note)())
+
+	view := app.viewNoteDetail()
+	if !strings.Contains(view, "Related (1)") || !strings.Contains(view, "[[query-planning]] Query planning") {
+		t.Errorf("expected related notes in view, got:\n%s", view)
+	}
+}
+
 func TestNoteViewScroll(t *testing.T) {
 	app := &App{
 		mode: modeNoteView,
@@ -512,6 +595,57 @@ func TestNoteViewDeletedMsgNoWorkspaceGoesToPicker(t *testing.T) {
 	}
 }
 
+func TestNoteViewArchiveConfirm(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	note, _ := app.db.CreateNote("Old Idea", "old-idea", "", ws.ID)
+	app.mode = modeNoteView
+	app.noteView = noteViewModel{note: note}
+	app.wsContent = wsContentModel{workspace: ws}
+
+	app.updateNoteView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if app.noteView.confirming != "archive" {
+		t.Fatalf("confirming = %q, want 'archive'", app.noteView.confirming)
+	}
+	if view := app.viewNoteDetail(); !strings.Contains(view, "Archive note") {
+		t.Error("expected archive confirmation prompt in view")
+	}
+
+	_, cmd := app.updateNoteView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	msg := cmd()
+	if _, ok := msg.(noteArchivedMsg); !ok {
+		t.Fatalf("expected noteArchivedMsg, got %T", msg)
+	}
+	if archived, _ := app.db.ListArchivedNotes(); len(archived) != 1 {
+		t.Errorf("expected 1 archived note, got %d", len(archived))
+	}
+
+	app.updateNoteView(msg)
+	if app.mode != modeWSContent {
+		t.Errorf("mode = %d, want modeWSContent (%d)", app.mode, modeWSContent)
+	}
+	if app.wsContent.feedback != "Note archived" {
+		t.Errorf("feedback = %q, want 'Note archived'", app.wsContent.feedback)
+	}
+}
+
+func TestWSContentDeleteMovesNoteToTrash(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	note, _ := app.db.CreateNote("Scratch", "scratch", "", ws.ID)
+	app.mode = modeWSContent
+	app.wsContent = wsContentModel{workspace: ws, notes: []*model.Note{note}}
+
+	app.updateWSContent(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	_, cmd := app.updateWSContent(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if _, ok := cmd().(noteDeletedMsg); !ok {
+		t.Fatal("expected noteDeletedMsg")
+	}
+	if trashed, _ := app.db.ListTrashedNotes(); len(trashed) != 1 {
+		t.Errorf("expected 1 trashed note, got %d", len(trashed))
+	}
+}
+
 func TestNoteViewErrorGoesBackToWSContent(t *testing.T) {
 	ws := &model.Workspace{ID: "ws1", Name: "Default", Kind: model.KindArea}
 	app := &App{
@@ -566,3 +700,577 @@ func TestEditNoteExternalNilNote(t *testing.T) {
 		t.Error("expected nil cmd when note is nil")
 	}
 }
+
+func testStoreApp(t *testing.T) *App {
+	t.Helper()
+	db, err := store.OpenWithPath(":memory:")
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &App{db: db, width: 80, height: 30}
+}
+
+func TestExpandEmbeds(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	app.db.CreateNote("Design", "design", "## Storage\n\nSQLite ^db\n\n## API\n\nREST", ws.ID)
+
+	got := app.expandEmbeds("Intro\n![[design#Storage]]\nOutro", 0)
+	if !strings.Contains(got, "┃ ⤷ design > Storage") {
+		t.Errorf("missing embed header: %q", got)
+	}
+	if !strings.Contains(got, "┃ SQLite") || strings.Contains(got, "^db") {
+		t.Errorf("expected embedded section without block markers: %q", got)
+	}
+	if strings.Contains(got, "REST") {
+		t.Errorf("embed should stop at the next heading: %q", got)
+	}
+
+	missing := app.expandEmbeds("![[ghost]]", 0)
+	if !strings.Contains(missing, "(note not found)") {
+		t.Errorf("expected missing note marker: %q", missing)
+	}
+
+	n, _ := app.db.GetNoteBySlug("design")
+	app.db.AddNoteAlias(n.ID, "Architecture")
+	aliased := app.expandEmbeds("![[Architecture#API]]", 0)
+	if !strings.Contains(aliased, "┃ REST") {
+		t.Errorf("expected embed through alias: %q", aliased)
+	}
+
+	plain := app.expandEmbeds("See [[design]]", 0)
+	if plain != "See [[design]]" {
+		t.Errorf("plain links should be left alone, got %q", plain)
+	}
+
+	app.db.CreateNote("Spec", "spec", "---\nstatus: draft\n---\nThe spec body", ws.ID)
+	whole := app.expandEmbeds("![[spec]]", 0)
+	if !strings.Contains(whole, "┃ The spec body") || strings.Contains(whole, "status: draft") || strings.Contains(whole, "---") {
+		t.Errorf("expected the embedded note without its front matter: %q", whole)
+	}
+}
+
+func TestNoteViewShowsEmbeddedBody(t *testing.T) {
+	app := &App{
+		mode: modeNoteView,
+		noteView: noteViewModel{
+			note: &model.Note{ID: "n1", Title: "Host", Slug: "host", Body: "![[other]]", UpdatedAt: time.Now()},
+		},
+		width:  80,
+		height: 30,
+	}
+
+	app.updateNoteView(noteEmbedsMsg{noteID: "n1", body: "┃ embedded text"})
+	view := app.viewNoteDetail()
+	if !strings.Contains(view, "embedded text") {
+		t.Errorf("expected embedded content in view")
+	}
+
+	app.updateNoteView(noteEmbedsMsg{noteID: "other", body: "stale"})
+	if app.noteView.body != "┃ embedded text" {
+		t.Errorf("embeds for another note should be ignored")
+	}
+}
+
+func TestNoteViewEvaluatesQueries(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	app.db.CreateNote("Decision", "adr-1", "Use SQLite.", ws.ID)
+	dash, _ := app.db.CreateNote("Dashboard", "dashboard", "```kb-query\nnotes Sqlite format:table\n```", ws.ID)
+
+	app.switchToNoteView(dash)
+	app.Update(app.loadNoteEmbeds(dash)())
+
+	view := app.viewNoteDetail()
+	if !strings.Contains(view, "Decision") || strings.Contains(view, "kb-query") {
+		t.Errorf("expected the query result in the note view:\n%s", view)
+	}
+}
+
+func TestPickerTodayOpensJournalNote(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KB_JOURNAL_WORKSPACE", "")
+	app := testStoreApp(t)
+	app.mode = modePicker
+
+	_, cmd := app.updatePicker(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	msg, ok := cmd().(journalOpenedMsg)
+	if !ok {
+		t.Fatal("expected journalOpenedMsg")
+	}
+	if msg.note.Slug != time.Now().Format("2006-01-02") {
+		t.Errorf("slug = %q, want today's date", msg.note.Slug)
+	}
+
+	app.updatePicker(msg)
+	if app.mode != modeNoteView {
+		t.Errorf("mode = %d, want modeNoteView (%d)", app.mode, modeNoteView)
+	}
+	if app.wsContent.workspace == nil || app.wsContent.workspace.Name != "Journal" {
+		t.Error("expected back navigation to target the journal workspace")
+	}
+	if !strings.Contains(app.viewNoteDetail(), "[/]: prev/next day") {
+		t.Error("expected day navigation hint for a journal note")
+	}
+}
+
+func TestNoteViewJournalNavigation(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	first, _ := app.db.CreateNote("First", "2026-10-01", "", ws.ID)
+	second, _ := app.db.CreateNote("Second", "2026-10-05", "", ws.ID)
+	app.mode = modeNoteView
+	app.noteView = noteViewModel{note: second}
+
+	_, cmd := app.updateNoteView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'['}})
+	msg, ok := cmd().(journalOpenedMsg)
+	if !ok || msg.note.ID != first.ID {
+		t.Fatalf("expected previous journal note, got %#v", msg)
+	}
+
+	app.updateNoteView(msg)
+	_, cmd = app.updateNoteView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'['}})
+	app.updateNoteView(cmd())
+	if app.noteView.feedback != "No earlier journal note" {
+		t.Errorf("feedback = %q", app.noteView.feedback)
+	}
+
+	app.noteView = noteViewModel{note: &model.Note{ID: "n1", Slug: "not-a-day"}}
+	if _, cmd := app.updateNoteView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}}); cmd != nil {
+		t.Error("expected no navigation from a non-journal note")
+	}
+}
+
+func TestWSContentCreateNoteFromTemplate(t *testing.T) {
+	t.Setenv("KB_TEMPLATE_DIR", t.TempDir())
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	app.mode = modeWSContent
+	app.wsContent = wsContentModel{workspace: ws}
+
+	key := func(s string) tea.Cmd {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+		if s == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		_, cmd := app.updateWSContent(msg)
+		return cmd
+	}
+
+	key("N")
+	for _, r := range "Weekly sync" {
+		key(string(r))
+	}
+	app.updateWSContent(key("enter")())
+	if app.wsContent.creating != "template" {
+		t.Fatalf("creating = %q, want 'template'", app.wsContent.creating)
+	}
+	if view := app.viewWSContent(); !strings.Contains(view, "(blank)") || !strings.Contains(view, "meeting") {
+		t.Error("expected template picker with blank and meeting entries")
+	}
+
+	for app.wsContent.template.cursor == 0 || app.wsContent.template.list[app.wsContent.template.cursor-1].Name != "meeting" {
+		key("j")
+	}
+	key("enter")
+	if app.wsContent.creating != "prompt" {
+		t.Fatalf("creating = %q, want 'prompt'", app.wsContent.creating)
+	}
+	if view := app.viewWSContent(); !strings.Contains(view, "Attendees") {
+		t.Error("expected Attendees prompt in view")
+	}
+	for _, r := range "Ana" {
+		key(string(r))
+	}
+	msg := key("enter")()
+	created, ok := msg.(noteCreatedMsg)
+	if !ok {
+		t.Fatalf("expected noteCreatedMsg, got %T", msg)
+	}
+	if !strings.Contains(created.note.Body, "Attendees: Ana") || !strings.Contains(created.note.Body, "## Agenda") {
+		t.Errorf("unexpected body: %q", created.note.Body)
+	}
+	if app.wsContent.creating != "" {
+		t.Errorf("creating = %q, want empty after create", app.wsContent.creating)
+	}
+}
+
+func TestWSContentCreateBlankNote(t *testing.T) {
+	t.Setenv("KB_TEMPLATE_DIR", t.TempDir())
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	app.mode = modeWSContent
+	app.wsContent = wsContentModel{workspace: ws, creating: "note", input: "Scratch"}
+
+	_, cmd := app.updateWSContent(tea.KeyMsg{Type: tea.KeyEnter})
+	app.updateWSContent(cmd())
+	_, cmd = app.updateWSContent(tea.KeyMsg{Type: tea.KeyEnter})
+	created, ok := cmd().(noteCreatedMsg)
+	if !ok || created.note.Body != "" || created.note.Slug != "scratch" {
+		t.Errorf("expected blank note, got %+v", created.note)
+	}
+}
+
+func TestNoteViewFollowLinks(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	target, _ := app.db.CreateNote("Target", "target", "Back to [[source]]", ws.ID)
+	source, _ := app.db.CreateNote("Source", "source", "See [[target]] and [[card:Fix login]] on [[board:sprint]]", ws.ID)
+	board, _ := app.db.CreateBoard("sprint", "", ws.ID)
+	columns, _ := app.db.ListColumns(board.ID)
+	app.db.CreateCard(columns[0].ID, "Other", model.PriorityLow)
+	card, _ := app.db.CreateCard(columns[1].ID, "Fix login", model.PriorityHigh)
+	app.switchToNoteView(source)
+
+	if cmd := app.followNoteLink(); cmd != nil {
+		t.Error("expected enter to do nothing without a focused link")
+	}
+
+	app.updateNoteView(tea.KeyMsg{Type: tea.KeyTab})
+	if app.noteView.linkFocus != 1 {
+		t.Fatalf("linkFocus = %d, want 1", app.noteView.linkFocus)
+	}
+	app.updateNoteView(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if app.noteView.linkFocus != 3 {
+		t.Errorf("shift+tab should wrap to the last link, got %d", app.noteView.linkFocus)
+	}
+	app.updateNoteView(tea.KeyMsg{Type: tea.KeyTab})
+
+	_, cmd := app.updateNoteView(tea.KeyMsg{Type: tea.KeyEnter})
+	app.updateNoteView(cmd())
+	if app.noteView.note.ID != target.ID || len(app.noteView.history) != 1 {
+		t.Fatalf("expected to follow link to target, got %q", app.noteView.note.Slug)
+	}
+
+	app.updateNoteView(tea.KeyMsg{Type: tea.KeyBackspace})
+	if app.noteView.note.ID != source.ID || len(app.noteView.history) != 0 {
+		t.Fatalf("expected backspace to return to source, got %q", app.noteView.note.Slug)
+	}
+
+	app.noteView.linkFocus = 2
+	_, cmd = app.updateNoteView(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = app.updateNoteView(cmd())
+	app.updateBoard(cmd())
+	if app.mode != modeCardView || app.cardView.card.ID != card.ID {
+		t.Fatalf("expected card viewer for linked card, mode = %d", app.mode)
+	}
+	if !strings.Contains(app.viewCardReadonly(), "Backspace: back to note") {
+		t.Error("expected hint to return to the note")
+	}
+	app.updateCardView(tea.KeyMsg{Type: tea.KeyBackspace})
+	if app.mode != modeNoteView || app.noteView.note.ID != source.ID {
+		t.Fatalf("expected backspace to return to the note, mode = %d", app.mode)
+	}
+
+	app.noteView.linkFocus = 3
+	_, cmd = app.updateNoteView(tea.KeyMsg{Type: tea.KeyEnter})
+	app.updateNoteView(cmd())
+	if app.mode != modeBoard || app.board.board.ID != board.ID {
+		t.Fatalf("expected board view for linked board, mode = %d", app.mode)
+	}
+}
+
+func TestNoteViewFollowMissingLink(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	note, _ := app.db.CreateNote("Source", "source", "[[ghost]]", ws.ID)
+	app.switchToNoteView(note)
+
+	app.updateNoteView(tea.KeyMsg{Type: tea.KeyTab})
+	_, cmd := app.updateNoteView(tea.KeyMsg{Type: tea.KeyEnter})
+	app.updateNoteView(cmd())
+	if app.noteView.feedback != `Note "ghost" not found` || app.noteView.note.ID != note.ID {
+		t.Errorf("expected not-found feedback, got %q", app.noteView.feedback)
+	}
+}
+
+func TestNoteViewFollowAttachmentLink(t *testing.T) {
+	app := testStoreApp(t)
+	app.db.SetAttachmentDir(t.TempDir())
+	ws, _ := app.db.GetDefaultWorkspace()
+	note, _ := app.db.CreateNote("Report", "report", "See ![[chart.png]]", ws.ID)
+	src := filepath.Join(t.TempDir(), "chart.png")
+	os.WriteFile(src, []byte("png"), 0o644)
+	att, _ := app.db.AttachFile("note", note.ID, src)
+	app.switchToNoteView(note)
+
+	if !strings.Contains(app.viewNoteDetail(), "📎 chart.png") {
+		t.Error("expected attachment marker in rendered note")
+	}
+	app.updateNoteView(tea.KeyMsg{Type: tea.KeyTab})
+	_, cmd := app.updateNoteView(tea.KeyMsg{Type: tea.KeyEnter})
+	app.updateNoteView(cmd())
+	if !strings.Contains(app.noteView.feedback, app.db.AttachmentPath(att)) || app.noteView.note.ID != note.ID {
+		t.Errorf("expected attachment path feedback, got %q", app.noteView.feedback)
+	}
+}
+
+func TestWSContentTagFilter(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	app.db.CreateBoard("Work", "", ws.ID)
+	app.db.CreateNote("Indexes", "indexes", "Tuning #area/backend/db", ws.ID)
+	app.db.CreateNote("Styles", "styles", "#area/frontend", ws.ID)
+	app.db.CreateNote("Ideas", "ideas", "#idea", ws.ID)
+
+	app.updateWSContent(app.switchToWSContent(ws)())
+	if len(app.wsContent.notes) != 3 || len(app.wsContent.boards) != 1 {
+		t.Fatalf("expected all content, got %d boards and %d notes", len(app.wsContent.boards), len(app.wsContent.notes))
+	}
+
+	app.updateWSContent(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'#'}})
+	if app.wsContent.creating != "tag" {
+		t.Fatalf("expected tag input, got %q", app.wsContent.creating)
+	}
+	for _, r := range "area/backend" {
+		app.updateWSContent(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd := app.updateWSContent(tea.KeyMsg{Type: tea.KeyEnter})
+	app.updateWSContent(cmd())
+	if len(app.wsContent.notes) != 1 || app.wsContent.notes[0].Slug != "indexes" || len(app.wsContent.boards) != 0 {
+		t.Fatalf("expected only the nested tagged note, got %v", app.wsContent.notes)
+	}
+	if !strings.Contains(app.viewWSContent(), "#area/backend") {
+		t.Error("expected active filter in view")
+	}
+
+	app.wsContent.tagFilter = "area"
+	app.updateWSContent(app.loadWSContent(ws.ID)())
+	if len(app.wsContent.notes) != 2 {
+		t.Errorf("expected parent tag to include children, got %d notes", len(app.wsContent.notes))
+	}
+
+	_, cmd = app.updateWSContent(tea.KeyMsg{Type: tea.KeyEsc})
+	app.updateWSContent(cmd())
+	if app.mode != modeWSContent || app.wsContent.tagFilter != "" || len(app.wsContent.notes) != 3 {
+		t.Errorf("expected esc to clear the filter, mode = %d, filter = %q", app.mode, app.wsContent.tagFilter)
+	}
+}
+
+func TestTasksView(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	app.db.CreateNote("Launch", "launch", "# Prep\n- [ ] Write post !high\n- [x] Book room\n- [ ] Ship @due(2020-01-01)", ws.ID)
+	app.db.CreateNote("Errands", "errands", "- [ ] Buy milk", ws.ID)
+
+	app.updateWSContent(app.switchToWSContent(ws)())
+	_, cmd := app.updateWSContent(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	app.Update(cmd())
+	if app.mode != modeTasks || len(app.tasks.tasks) != 3 {
+		t.Fatalf("expected 3 open tasks, mode = %d, tasks = %d", app.mode, len(app.tasks.tasks))
+	}
+	view := app.viewTasks()
+	for _, want := range []string{"Errands", "Launch", "[ ] Write post", "!high", "due 2020-01-01", "— Prep"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in view", want)
+		}
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	_, cmd = app.Update(cmd())
+	app.Update(cmd())
+	if len(app.tasks.tasks) != 2 || !strings.Contains(app.tasks.feedback, "Write post") {
+		t.Fatalf("expected toggled task to leave the open list, got %d tasks, feedback %q", len(app.tasks.tasks), app.tasks.feedback)
+	}
+	note, _ := app.db.GetNoteBySlug("launch")
+	if !strings.Contains(note.Body, "- [x] Write post !high") {
+		t.Errorf("checkbox not toggled: %q", note.Body)
+	}
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	app.Update(cmd())
+	if len(app.tasks.tasks) != 4 {
+		t.Errorf("expected all 4 tasks, got %d", len(app.tasks.tasks))
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.mode != modeNoteView || app.noteView.note.Slug != "launch" {
+		t.Fatalf("expected to open the task's note, mode = %d", app.mode)
+	}
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app.Update(cmd())
+	if app.mode != modeTasks || len(app.tasks.tasks) != 4 {
+		t.Errorf("expected esc to return to the task list, mode = %d", app.mode)
+	}
+}
+
+func TestReviewSession(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	app.db.CreateNote("Go", "go", "Goroutine? :: Lightweight thread\nChannel? :: Typed pipe", ws.ID)
+
+	app.updateWSContent(app.switchToWSContent(ws)())
+	_, cmd := app.updateWSContent(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	app.Update(cmd())
+	if app.mode != modeReview || len(app.review.queue) != 2 {
+		t.Fatalf("expected 2 due cards, mode = %d, cards = %d", app.mode, len(app.review.queue))
+	}
+	view := app.viewReview()
+	if !strings.Contains(view, "Goroutine?") || strings.Contains(view, "Lightweight") || !strings.Contains(view, "from Go (go)") {
+		t.Errorf("expected the question only:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'4'}})
+	if app.review.reviewed != 0 {
+		t.Fatal("grading before revealing the answer should be ignored")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	if !strings.Contains(app.viewReview(), "Lightweight thread") {
+		t.Error("expected the answer after space")
+	}
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'5'}})
+	app.Update(cmd())
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	app.Update(cmd())
+	if app.review.reviewed != 2 || app.review.again != 1 || app.currentFlashcard().Front != "Channel?" {
+		t.Fatalf("expected the missed card to come back, got %+v", app.review)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if app.mode != modeNoteView || app.noteView.note.Slug != "go" {
+		t.Fatalf("expected to open the source note, mode = %d", app.mode)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.mode != modeReview || app.currentFlashcard() == nil {
+		t.Errorf("expected esc to resume the session, mode = %d", app.mode)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if !strings.Contains(app.viewReview(), "Reviewed 2 card(s), missed 1.") {
+		t.Errorf("expected the session summary:\n%s", app.viewReview())
+	}
+	due, _ := app.db.ListDueFlashcards(store.FlashcardFilter{}, time.Now())
+	if len(due) != 0 {
+		t.Errorf("graded cards should not be due again today: %d", len(due))
+	}
+}
+
+func TestBoardSnapshotKey(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	board, _ := app.db.CreateBoard("Sprint", "", ws.ID)
+	app.board = boardModel{board: board}
+	app.mode = modeBoard
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	app.Update(cmd())
+	if !strings.Contains(app.board.feedback, "Snapshot saved to note \"sprint-snapshot-") {
+		t.Errorf("unexpected feedback %q", app.board.feedback)
+	}
+	notes, _ := app.db.ListNotesByTag("snapshot")
+	if len(notes) != 1 {
+		t.Errorf("expected one snapshot note, got %d", len(notes))
+	}
+}
+
+func TestNoteGraphKeysBeforeLoad(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	note, _ := app.db.CreateNote("Alone", "alone", "", ws.ID)
+
+	app.switchToNoteView(note)
+	app.switchToGraph(note)
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyRight}, {Type: tea.KeyLeft}, {Type: tea.KeyDown}} {
+		app.Update(key)
+	}
+	app.Update(errMsg{fmt.Errorf("boom")})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	if app.graph.cursor != 0 {
+		t.Errorf("cursor moved without a graph: %d", app.graph.cursor)
+	}
+}
+
+func TestNoteGraphView(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	app.db.CreateNote("Gamma", "gamma", "", ws.ID)
+	beta, _ := app.db.CreateNote("Beta", "beta", "See [[gamma]]", ws.ID)
+	alpha, _ := app.db.CreateNote("Alpha", "alpha", "See [[beta]]", ws.ID)
+	app.db.SyncNoteLinks(beta)
+	app.db.SyncNoteLinks(alpha)
+
+	app.switchToNoteView(beta)
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	app.Update(cmd())
+	if app.mode != modeGraph {
+		t.Fatalf("expected graph mode, got %d", app.mode)
+	}
+	view := app.viewGraph()
+	for _, want := range []string{"graph — Beta (depth 2)", "│ Beta │", "├─← Alpha", "└─→ Gamma"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in:\n%s", want, view)
+		}
+	}
+	if app.graphSelected().NodeID != beta.ID {
+		t.Fatalf("expected the note itself to be selected, got %+v", app.graphSelected())
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRight})
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if app.graphSelected().Label != "Gamma" {
+		t.Fatalf("expected Gamma to be selected, got %+v", app.graphSelected())
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if app.graphSelected().NodeID != beta.ID {
+		t.Errorf("expected left to select the parent, got %+v", app.graphSelected())
+	}
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}})
+	app.Update(cmd())
+	if app.graph.depth != 1 {
+		t.Errorf("expected depth 1, got %d", app.graph.depth)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = app.Update(cmd())
+	if app.mode != modeNoteView || app.noteView.note.Slug != "alpha" {
+		t.Fatalf("expected enter to open Alpha, mode = %d", app.mode)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if app.noteView.note.Slug != "beta" {
+		t.Errorf("expected backspace to return to Beta, got %s", app.noteView.note.Slug)
+	}
+}
+
+func TestNoteMentionsView(t *testing.T) {
+	app := testStoreApp(t)
+	ws, _ := app.db.GetDefaultWorkspace()
+	target, _ := app.db.CreateNote("Query Planning", "query-planning", "", ws.ID)
+	app.db.CreateNote("Standup", "standup", "Discussed query planning.\nQuery planning again.", ws.ID)
+
+	app.switchToNoteView(target)
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	app.Update(cmd())
+	if app.mode != modeMentions {
+		t.Fatalf("expected mentions mode, got %d", app.mode)
+	}
+	view := app.viewMentions()
+	for _, want := range []string{"unlinked mentions — Query Planning (2)", "[[standup]]:1", "Discussed query planning."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in:\n%s", want, view)
+		}
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	app.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	app.Update(cmd())
+	if len(app.mentions.mentions) != 1 || app.mentions.feedback != "Linked 1 mention(s)" {
+		t.Fatalf("expected one mention left after linking, got %+v (%q)", app.mentions.mentions, app.mentions.feedback)
+	}
+	standup, _ := app.db.GetNoteBySlug("standup")
+	if !strings.Contains(standup.Body, "[[query-planning|Query planning]] again.") {
+		t.Errorf("expected the selected mention to be linked, got %q", standup.Body)
+	}
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app.Update(cmd())
+	if app.mode != modeNoteView || len(app.noteView.backlinks) != 1 {
+		t.Errorf("expected the note view with the new backlink, mode = %d, backlinks = %d", app.mode, len(app.noteView.backlinks))
+	}
+}
@@ -80,6 +80,9 @@ func (a *App) updatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case boardCreatedMsg:
 		return a, a.switchToBoard(msg.board)
 
+	case journalOpenedMsg:
+		return a, a.showJournal(msg)
+
 	case errMsg:
 		a.picker.err = msg.err
 
@@ -97,6 +100,8 @@ func (a *App) updatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(a.picker.workspaces) > 0 && a.picker.cursor < len(a.picker.workspaces) {
 				return a, a.switchToWSContent(a.picker.workspaces[a.picker.cursor])
 			}
+		case "t":
+			return a, a.openToday()
 		case "q":
 			return a, tea.Quit
 		}
@@ -116,7 +121,7 @@ func (a *App) viewPicker() string {
 	}
 
 	titleBar := titleBarStyle.Width(w).Render(" kb: Select Workspace ")
-	statusBar := statusBarStyle.Width(w).Render(" j/k: select   enter: open   q: quit")
+	statusBar := statusBarStyle.Width(w).Render(" j/k: select   enter: open   t: today   q: quit")
 
 	contentHeight := h - lipgloss.Height(titleBar) - lipgloss.Height(statusBar) - 1
 
@@ -164,6 +169,8 @@ type wsContentModel struct {
 	creating   string
 	input      string
 	confirming string
+	template   templatePickerModel
+	tagFilter  string
 	err        error
 	feedback   string
 }
@@ -176,6 +183,10 @@ type noteDeletedMsg struct {
 	workspaceID string
 }
 
+type noteArchivedMsg struct {
+	workspaceID string
+}
+
 type wsContentLoadedMsg struct {
 	boards []*model.Board
 	notes  []*model.Note
@@ -214,12 +225,20 @@ func (a *App) switchToWSContent(ws *model.Workspace) tea.Cmd {
 }
 
 func (a *App) loadWSContent(workspaceID string) tea.Cmd {
+	tag := a.wsContent.tagFilter
 	return func() tea.Msg {
-		boards, err := a.db.ListBoardsByWorkspace(workspaceID)
+		notes, err := a.db.ListNotesByWorkspace(workspaceID)
 		if err != nil {
 			return errMsg{err}
 		}
-		notes, err := a.db.ListNotesByWorkspace(workspaceID)
+		if tag != "" {
+			tagged, err := a.db.ListNotesByTag(tag)
+			if err != nil {
+				return errMsg{err}
+			}
+			return wsContentLoadedMsg{notes: notesInWorkspace(tagged, notes)}
+		}
+		boards, err := a.db.ListBoardsByWorkspace(workspaceID)
 		if err != nil {
 			return errMsg{err}
 		}
@@ -227,6 +246,22 @@ func (a *App) loadWSContent(workspaceID string) tea.Cmd {
 	}
 }
 
+// notesInWorkspace keeps the notes of tagged that are also in workspace,
+// preserving the workspace order.
+func notesInWorkspace(tagged, workspace []*model.Note) []*model.Note {
+	ids := make(map[string]bool, len(tagged))
+	for _, n := range tagged {
+		ids[n.ID] = true
+	}
+	var filtered []*model.Note
+	for _, n := range workspace {
+		if ids[n.ID] {
+			filtered = append(filtered, n)
+		}
+	}
+	return filtered
+}
+
 func (a *App) updateWSContent(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case wsContentLoadedMsg:
@@ -251,13 +286,30 @@ func (a *App) updateWSContent(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, a.loadWSContent(a.wsContent.workspace.ID)
 
 	case noteDeletedMsg:
-		a.wsContent.feedback = "Note deleted"
+		a.wsContent.feedback = "Note moved to trash"
+		return a, a.loadWSContent(msg.workspaceID)
+
+	case noteArchivedMsg:
+		a.wsContent.feedback = "Note archived"
 		return a, a.loadWSContent(msg.workspaceID)
 
+	case journalOpenedMsg:
+		return a, a.showJournal(msg)
+
+	case templatesLoadedMsg:
+		a.wsContent.template = templatePickerModel{title: msg.title, list: msg.list}
+		a.wsContent.creating = "template"
+
 	case tea.KeyMsg:
 		a.wsContent.feedback = ""
 
-		if a.wsContent.creating != "" {
+		switch a.wsContent.creating {
+		case "":
+		case "template":
+			return a.updateWSContentTemplate(msg)
+		case "prompt":
+			return a.updateWSContentPrompt(msg)
+		default:
 			return a.updateWSContentCreating(msg)
 		}
 
@@ -293,7 +345,30 @@ func (a *App) updateWSContent(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if total > 0 {
 				a.wsContent.confirming = "delete"
 			}
-		case "b", "esc":
+		case "a":
+			if total > 0 && a.wsContent.selectedKind() == "note" {
+				a.wsContent.confirming = "archive"
+			}
+		case "t":
+			return a, a.openToday()
+		case "T":
+			return a, a.switchToTasks()
+		case "R":
+			return a, a.switchToReview()
+		case "#":
+			a.wsContent.creating = "tag"
+			a.wsContent.input = a.wsContent.tagFilter
+		case "/":
+			return a, a.switchToNoteList()
+		case "esc":
+			if a.wsContent.tagFilter != "" {
+				a.wsContent.tagFilter = ""
+				a.wsContent.cursor = 0
+				return a, a.loadWSContent(a.wsContent.workspace.ID)
+			}
+			a.mode = modePicker
+			return a, a.initPicker()
+		case "b":
 			a.mode = modePicker
 			return a, a.initPicker()
 		case "q":
@@ -308,22 +383,19 @@ func (a *App) updateWSContentCreating(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
 		name := strings.TrimSpace(a.wsContent.input)
-		if name == "" {
-			a.wsContent.creating = ""
-			return a, nil
-		}
 		kind := a.wsContent.creating
 		a.wsContent.creating = ""
 		wsID := a.wsContent.workspace.ID
+		if kind == "tag" {
+			a.wsContent.tagFilter = model.NormalizeTag(name)
+			a.wsContent.cursor = 0
+			return a, a.loadWSContent(wsID)
+		}
+		if name == "" {
+			return a, nil
+		}
 		if kind == "note" {
-			slug := model.Slugify(name)
-			return a, func() tea.Msg {
-				note, err := a.db.CreateNote(name, slug, "", wsID)
-				if err != nil {
-					return errMsg{err}
-				}
-				return noteCreatedMsg{note}
-			}
+			return a, a.loadNoteTemplates(name)
 		}
 		return a, func() tea.Msg {
 			board, err := a.db.CreateBoard(name, "", wsID)
@@ -350,6 +422,7 @@ func (a *App) updateWSContentCreating(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (a *App) updateWSContentConfirming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "y", "Y":
+		action := a.wsContent.confirming
 		a.wsContent.confirming = ""
 		wsID := a.wsContent.workspace.ID
 		if a.wsContent.selectedKind() == "board" {
@@ -368,6 +441,14 @@ func (a *App) updateWSContentConfirming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if note == nil {
 			return a, nil
 		}
+		if action == "archive" {
+			return a, func() tea.Msg {
+				if err := a.db.ArchiveNote(note.ID); err != nil {
+					return errMsg{err}
+				}
+				return noteArchivedMsg{workspaceID: wsID}
+			}
+		}
 		return a, func() tea.Msg {
 			if err := a.db.DeleteNote(note.ID); err != nil {
 				return errMsg{err}
@@ -400,7 +481,7 @@ func (a *App) viewWSContent() string {
 
 	ws := a.wsContent.workspace
 	titleBar := titleBarStyle.Width(w).Render(fmt.Sprintf(" kb: %s (%s) ", ws.Name, ws.Kind))
-	statusBar := statusBarStyle.Width(w).Render(" j/k: select   enter: open   n: new board   N: new note   d: delete   b: back   q: quit")
+	statusBar := statusBarStyle.Width(w).Render(" j/k: select   enter: open   n: new board   N: new note   t: today   T: tasks   R: review   /: find note   #: tag filter   a: archive   d: delete   b: back   q: quit")
 
 	contentHeight := h - lipgloss.Height(titleBar) - lipgloss.Height(statusBar) - 1
 
@@ -409,17 +490,32 @@ func (a *App) viewWSContent() string {
 		return lipgloss.JoinVertical(lipgloss.Left, titleBar, content, statusBar)
 	}
 
+	if a.wsContent.creating == "template" || a.wsContent.creating == "prompt" {
+		content := lipgloss.Place(w, contentHeight, lipgloss.Center, lipgloss.Center, a.viewTemplateDialog())
+		return lipgloss.JoinVertical(lipgloss.Left, titleBar, content, statusBar)
+	}
+
 	if a.wsContent.creating != "" {
 		label := "New board name:"
-		if a.wsContent.creating == "note" {
+		switch a.wsContent.creating {
+		case "note":
 			label = "New note title:"
+		case "tag":
+			label = "Filter notes by tag (includes nested tags):"
 		}
 		var rows []string
 		rows = append(rows, formLabelActiveStyle.Render(label))
 		rows = append(rows, "")
 		rows = append(rows, fmt.Sprintf("  %s█", a.wsContent.input))
 		rows = append(rows, "")
-		rows = append(rows, helpStyle.Render("  enter: create   esc: cancel"))
+		hint := "  enter: create   esc: cancel"
+		switch a.wsContent.creating {
+		case "note":
+			hint = "  enter: choose template   esc: cancel"
+		case "tag":
+			hint = "  enter: filter (empty clears)   esc: cancel"
+		}
+		rows = append(rows, helpStyle.Render(hint))
 
 		dialog := dialogBoxStyle.Width(50).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
 		content := lipgloss.Place(w, contentHeight, lipgloss.Center, lipgloss.Center, dialog)
@@ -441,7 +537,7 @@ func (a *App) viewWSContent() string {
 			if note != nil {
 				name = note.Title
 			}
-			prompt = fmt.Sprintf("Delete note %q?", name)
+			prompt = noteConfirmPrompt(a.wsContent.confirming, name)
 		}
 		content := renderCenteredConfirm(w, contentHeight, prompt)
 		return lipgloss.JoinVertical(lipgloss.Left, titleBar, content, statusBar)
@@ -450,6 +546,11 @@ func (a *App) viewWSContent() string {
 	var rows []string
 	idx := 0
 
+	if a.wsContent.tagFilter != "" {
+		rows = append(rows, labelStyle.Render("#"+a.wsContent.tagFilter)+helpStyle.Render("  esc: clear filter"))
+		rows = append(rows, "")
+	}
+
 	// Boards section
 	if len(a.wsContent.boards) > 0 {
 		rows = append(rows, lipgloss.NewStyle().Bold(true).Underline(true).Render("Boards"))
@@ -493,7 +594,9 @@ func (a *App) viewWSContent() string {
 		}
 	}
 
-	if len(a.wsContent.boards) == 0 && len(a.wsContent.notes) == 0 {
+	if a.wsContent.tagFilter != "" && len(a.wsContent.notes) == 0 {
+		rows = append(rows, emptyColumnStyle.Render("No notes tagged #"+a.wsContent.tagFilter+"."))
+	} else if len(a.wsContent.boards) == 0 && len(a.wsContent.notes) == 0 {
 		rows = append(rows, emptyColumnStyle.Render("No boards or notes in this workspace."))
 		rows = append(rows, "")
 		rows = append(rows, helpStyle.Render("Press n to create a board."))
@@ -0,0 +1,216 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Flashcard review (modeReview) ---
+
+var gradeLabels = []string{"forgot", "wrong", "almost", "hard", "good", "easy"}
+
+type reviewModel struct {
+	filter    store.FlashcardFilter
+	scope     string
+	queue     []*model.Flashcard
+	index     int
+	revealed  bool
+	reviewed  int
+	again     int
+	returning bool
+	err       error
+}
+
+type reviewLoadedMsg struct {
+	cards []*model.Flashcard
+}
+
+type flashcardReviewedMsg struct {
+	card  *model.Flashcard
+	grade int
+}
+
+// NewReviewApp starts the TUI in a review session of the flashcards due
+// today, limited by filter.
+func NewReviewApp(db *store.DB, filter store.FlashcardFilter) *App {
+	return &App{
+		db:     db,
+		mode:   modeReview,
+		review: reviewModel{filter: filter, scope: "all notes"},
+	}
+}
+
+func (a *App) switchToReview() tea.Cmd {
+	a.mode = modeReview
+	a.review = reviewModel{scope: "all notes"}
+	if ws := a.wsContent.workspace; ws != nil {
+		a.review.filter.WorkspaceID = ws.ID
+		a.review.scope = ws.Name
+	}
+	if tag := a.wsContent.tagFilter; tag != "" {
+		a.review.filter.Tag = tag
+		a.review.scope += "  #" + tag
+	}
+	return a.loadReview()
+}
+
+func (a *App) loadReview() tea.Cmd {
+	filter := a.review.filter
+	return func() tea.Msg {
+		cards, err := a.db.ListDueFlashcards(filter, time.Now())
+		if err != nil {
+			return errMsg{err}
+		}
+		return reviewLoadedMsg{cards: cards}
+	}
+}
+
+func (a *App) gradeFlashcard(card *model.Flashcard, grade int) tea.Cmd {
+	return func() tea.Msg {
+		reviewed, err := a.db.ReviewFlashcard(card.ID, grade, time.Now())
+		if err != nil {
+			return errMsg{err}
+		}
+		return flashcardReviewedMsg{card: reviewed, grade: grade}
+	}
+}
+
+func (a *App) currentFlashcard() *model.Flashcard {
+	if a.review.index < len(a.review.queue) {
+		return a.review.queue[a.review.index]
+	}
+	return nil
+}
+
+func (a *App) updateReview(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case reviewLoadedMsg:
+		a.review.queue = msg.cards
+		a.review.index = 0
+		a.review.revealed = false
+		a.review.err = nil
+
+	case flashcardReviewedMsg:
+		a.review.reviewed++
+		if msg.grade < 3 {
+			// Missed cards come back at the end of the session.
+			a.review.again++
+			a.review.queue = append(a.review.queue, msg.card)
+		}
+		a.review.index++
+		a.review.revealed = false
+
+	case errMsg:
+		a.review.err = msg.err
+
+	case tea.KeyMsg:
+		card := a.currentFlashcard()
+		switch key := msg.String(); key {
+		case " ", "enter":
+			if card != nil {
+				a.review.revealed = true
+			}
+		case "0", "1", "2", "3", "4", "5":
+			if card != nil && a.review.revealed {
+				return a, a.gradeFlashcard(card, int(key[0]-'0'))
+			}
+		case "s":
+			if card != nil {
+				a.review.index++
+				a.review.revealed = false
+			}
+		case "o":
+			if card != nil {
+				note, err := a.db.GetNote(card.NoteID)
+				if err != nil {
+					a.review.err = err
+					return a, nil
+				}
+				a.review.returning = true
+				return a, a.switchToNoteView(note)
+			}
+		case "b", "esc":
+			if a.wsContent.workspace != nil {
+				return a, a.switchToWSContent(a.wsContent.workspace)
+			}
+			a.mode = modePicker
+			return a, a.initPicker()
+		case "q":
+			return a, tea.Quit
+		}
+	}
+	return a, nil
+}
+
+// returnToReview resumes the review session when the current note was
+// opened from it.
+func (a *App) returnToReview() bool {
+	if !a.review.returning {
+		return false
+	}
+	a.review.returning = false
+	a.mode = modeReview
+	return true
+}
+
+func (a *App) viewReview() string {
+	w := a.width
+	if w == 0 {
+		w = 80
+	}
+	h := a.height
+	if h == 0 {
+		h = 24
+	}
+
+	remaining := max(len(a.review.queue)-a.review.index, 0)
+	titleBar := titleBarStyle.Width(w).Render(fmt.Sprintf(" kb: review (%d left) — %s ", remaining, a.review.scope))
+	hint := " space: show answer   s: skip   o: open note   b: back   q: quit"
+	if a.review.revealed {
+		hint = " 0-5: grade recall   s: skip   o: open note   b: back   q: quit"
+	}
+	statusBar := statusBarStyle.Width(w).Render(hint)
+	contentHeight := h - lipgloss.Height(titleBar) - lipgloss.Height(statusBar) - 1
+	contentW := max(20, w-4)
+
+	var rows []string
+	card := a.currentFlashcard()
+	switch {
+	case a.review.err != nil:
+		rows = append(rows, errorStyle.Render(fmt.Sprintf("Error: %v", a.review.err)))
+	case card == nil && a.review.reviewed == 0:
+		rows = append(rows, emptyColumnStyle.Render("No flashcards due."), "",
+			helpStyle.Render("Write \"Question :: Answer\" in a note to create one."))
+	case card == nil:
+		rows = append(rows, formLabelActiveStyle.Render("Session complete"), "",
+			fmt.Sprintf("Reviewed %d card(s), missed %d.", a.review.reviewed, a.review.again))
+	default:
+		rows = append(rows, helpStyle.Render(fmt.Sprintf("Card %d of %d · from %s (%s)",
+			a.review.index+1, len(a.review.queue), card.NoteTitle, card.NoteSlug)), "")
+		front, _ := renderMarkdown(card.Front, contentW, 0)
+		rows = append(rows, front...)
+		rows = append(rows, "", helpStyle.Render(strings.Repeat("─", min(contentW, 40))), "")
+		if a.review.revealed {
+			back, _ := renderMarkdown(card.Back, contentW, 0)
+			rows = append(rows, back...)
+			var grades []string
+			for i, label := range gradeLabels {
+				grades = append(grades, fmt.Sprintf("%s %s", labelStyle.Render(fmt.Sprint(i)), label))
+			}
+			rows = append(rows, "", strings.Join(grades, "   "))
+		} else {
+			rows = append(rows, helpStyle.Render("Press space to show the answer."))
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, rows...)
+	padded := lipgloss.NewStyle().Padding(1, 2).Height(contentHeight).Render(content)
+	return lipgloss.JoinVertical(lipgloss.Left, titleBar, padded, statusBar)
+}
@@ -0,0 +1,25 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/snapshot"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type boardSnapshotMsg struct {
+	note *model.Note
+}
+
+func (a *App) snapshotBoard() tea.Cmd {
+	board := a.board.board
+	return func() tea.Msg {
+		note, _, err := snapshot.Take(a.db, board, "", time.Now())
+		if err != nil {
+			return errMsg{err}
+		}
+		return boardSnapshotMsg{note: note}
+	}
+}
@@ -70,6 +70,58 @@ var (
 			Bold(true)
 )
 
+// Markdown styles used by the note viewer.
+var (
+	mdHeading1Style = lipgloss.NewStyle().
+			Bold(true).
+			Underline(true)
+
+	mdHeading2Style = lipgloss.NewStyle().
+			Bold(true)
+
+	mdHeadingStyle = lipgloss.NewStyle().
+			Bold(true).
+			Italic(true)
+
+	mdQuoteStyle = lipgloss.NewStyle().
+			Faint(true).
+			Italic(true)
+
+	mdBulletStyle = lipgloss.NewStyle().
+			Bold(true)
+
+	mdTableHeaderStyle = lipgloss.NewStyle().
+				Bold(true)
+
+	mdInlineCodeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.AdaptiveColor{Light: "5", Dark: "13"})
+
+	mdLinkStyle = lipgloss.NewStyle().
+			Underline(true).
+			Foreground(lipgloss.AdaptiveColor{Light: "4", Dark: "12"})
+
+	mdLinkFocusedStyle = lipgloss.NewStyle().
+				Bold(true).
+				Reverse(true)
+
+	mdURLStyle = lipgloss.NewStyle().
+			Underline(true)
+
+	mdKeywordStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.AdaptiveColor{Light: "4", Dark: "12"})
+
+	mdStringStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "2", Dark: "10"})
+
+	mdNumberStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "3", Dark: "11"})
+
+	mdCommentStyle = lipgloss.NewStyle().
+			Faint(true).
+			Italic(true)
+)
+
 func priorityStyle(priority string) lipgloss.Style {
 	if s, ok := priorityStyles[priority]; ok {
 		return s
@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Task index (modeTasks) ---
+
+type tasksModel struct {
+	tasks     []*model.Task
+	cursor    int
+	showAll   bool
+	allSpaces bool
+	returning bool
+	feedback  string
+	err       error
+}
+
+type tasksLoadedMsg struct {
+	tasks []*model.Task
+}
+
+type taskToggledMsg struct {
+	task *model.Task
+}
+
+func (a *App) switchToTasks() tea.Cmd {
+	a.mode = modeTasks
+	a.tasks = tasksModel{}
+	return a.loadTasks()
+}
+
+func (a *App) loadTasks() tea.Cmd {
+	filter := store.TaskFilter{Open: !a.tasks.showAll, Tag: a.wsContent.tagFilter}
+	if ws := a.wsContent.workspace; ws != nil && !a.tasks.allSpaces {
+		filter.WorkspaceID = ws.ID
+	}
+	return func() tea.Msg {
+		tasks, err := a.db.ListTasks(filter)
+		if err != nil {
+			return errMsg{err}
+		}
+		return tasksLoadedMsg{tasks: tasks}
+	}
+}
+
+func (a *App) toggleTask(task *model.Task) tea.Cmd {
+	return func() tea.Msg {
+		toggled, err := a.db.ToggleTask(task.ID)
+		if err != nil {
+			return errMsg{err}
+		}
+		return taskToggledMsg{task: toggled}
+	}
+}
+
+func (a *App) updateTasks(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tasksLoadedMsg:
+		a.tasks.tasks = msg.tasks
+		a.tasks.err = nil
+		if a.tasks.cursor >= len(msg.tasks) {
+			a.tasks.cursor = max(len(msg.tasks)-1, 0)
+		}
+
+	case taskToggledMsg:
+		if msg.task.Checked {
+			a.tasks.feedback = fmt.Sprintf("Completed %q", msg.task.Text)
+		} else {
+			a.tasks.feedback = fmt.Sprintf("Reopened %q", msg.task.Text)
+		}
+		return a, a.loadTasks()
+
+	case errMsg:
+		a.tasks.err = msg.err
+
+	case tea.KeyMsg:
+		a.tasks.feedback = ""
+		switch msg.String() {
+		case "j", "down":
+			if a.tasks.cursor < len(a.tasks.tasks)-1 {
+				a.tasks.cursor++
+			}
+		case "k", "up":
+			if a.tasks.cursor > 0 {
+				a.tasks.cursor--
+			}
+		case " ", "x":
+			if task := a.selectedTask(); task != nil {
+				return a, a.toggleTask(task)
+			}
+		case "enter":
+			if task := a.selectedTask(); task != nil {
+				note, err := a.db.GetNote(task.NoteID)
+				if err != nil {
+					a.tasks.err = err
+					return a, nil
+				}
+				a.tasks.returning = true
+				return a, a.switchToNoteView(note)
+			}
+		case "o":
+			a.tasks.showAll = !a.tasks.showAll
+			return a, a.loadTasks()
+		case "w":
+			a.tasks.allSpaces = !a.tasks.allSpaces
+			return a, a.loadTasks()
+		case "b", "esc":
+			if a.wsContent.workspace != nil {
+				return a, a.switchToWSContent(a.wsContent.workspace)
+			}
+			a.mode = modePicker
+			return a, a.initPicker()
+		case "q":
+			return a, tea.Quit
+		}
+	}
+	return a, nil
+}
+
+func (a *App) selectedTask() *model.Task {
+	if a.tasks.cursor >= 0 && a.tasks.cursor < len(a.tasks.tasks) {
+		return a.tasks.tasks[a.tasks.cursor]
+	}
+	return nil
+}
+
+// returnToTasks reopens the task list when the current note was opened
+// from it.
+func (a *App) returnToTasks() (tea.Cmd, bool) {
+	if !a.tasks.returning {
+		return nil, false
+	}
+	a.tasks.returning = false
+	a.mode = modeTasks
+	return a.loadTasks(), true
+}
+
+func (a *App) viewTasks() string {
+	w := a.width
+	if w == 0 {
+		w = 80
+	}
+	h := a.height
+	if h == 0 {
+		h = 24
+	}
+
+	scope := "all workspaces"
+	if ws := a.wsContent.workspace; ws != nil && !a.tasks.allSpaces {
+		scope = ws.Name
+	}
+	if a.wsContent.tagFilter != "" {
+		scope += "  #" + a.wsContent.tagFilter
+	}
+	state := "open"
+	if a.tasks.showAll {
+		state = "all"
+	}
+	titleBar := titleBarStyle.Width(w).Render(fmt.Sprintf(" kb: tasks (%s) — %s ", state, scope))
+	statusBar := statusBarStyle.Width(w).Render(" j/k: select   space/x: toggle   enter: open note   o: open/all   w: workspace/all   b: back   q: quit")
+
+	contentHeight := h - lipgloss.Height(titleBar) - lipgloss.Height(statusBar) - 1
+
+	if a.tasks.err != nil {
+		content := errorStyle.Render(fmt.Sprintf("Error: %v", a.tasks.err))
+		return lipgloss.JoinVertical(lipgloss.Left, titleBar, content, statusBar)
+	}
+
+	var rows []string
+	cursorRow := 0
+	noteID := ""
+	now := time.Now()
+	for i, task := range a.tasks.tasks {
+		if task.NoteID != noteID {
+			if noteID != "" {
+				rows = append(rows, "")
+			}
+			noteID = task.NoteID
+			rows = append(rows, lipgloss.NewStyle().Bold(true).Underline(true).Render(task.NoteTitle)+
+				helpStyle.Render("  "+task.NoteSlug))
+		}
+
+		cursor := "  "
+		style := formValueStyle
+		if i == a.tasks.cursor {
+			cursor = "▸ "
+			style = formLabelActiveStyle
+			cursorRow = len(rows)
+		}
+		box := "[ ]"
+		if task.Checked {
+			box = "[x]"
+			style = helpStyle
+		}
+		line := fmt.Sprintf("%s%s %s", cursor, box, style.Render(truncate(task.Text, 50)))
+		if task.Priority != "" {
+			line += "  " + labelStyle.Render("!"+string(task.Priority))
+		}
+		if task.Due != "" {
+			if task.IsOverdue(now) {
+				line += "  " + errorStyle.Render("due "+task.Due)
+			} else {
+				line += "  " + helpStyle.Render("due "+task.Due)
+			}
+		}
+		if task.Context != "" {
+			line += helpStyle.Render("  — " + truncate(task.Context, 24))
+		}
+		rows = append(rows, line)
+	}
+
+	if len(a.tasks.tasks) == 0 {
+		if a.tasks.showAll {
+			rows = append(rows, emptyColumnStyle.Render("No tasks found."))
+		} else {
+			rows = append(rows, emptyColumnStyle.Render("No open tasks."))
+		}
+		rows = append(rows, "")
+		rows = append(rows, helpStyle.Render("Add \"- [ ] something\" to a note to track it here."))
+	}
+
+	visible := contentHeight - 2
+	if a.tasks.feedback != "" {
+		visible -= 2
+	}
+	if visible > 0 && len(rows) > visible {
+		start := cursorRow - visible/2
+		start = min(max(start, 0), len(rows)-visible)
+		rows = rows[start : start+visible]
+	}
+
+	if a.tasks.feedback != "" {
+		rows = append(rows, "")
+		rows = append(rows, helpStyle.Render(a.tasks.feedback))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, rows...)
+	padded := lipgloss.NewStyle().Padding(1, 2).Height(contentHeight).Render(content)
+	return lipgloss.JoinVertical(lipgloss.Left, titleBar, padded, statusBar)
+}
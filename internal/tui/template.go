@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeryldev/kb/internal/model"
+	"github.com/jeryldev/kb/internal/templates"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type templatePickerModel struct {
+	title   string
+	list    []templates.Template
+	cursor  int
+	chosen  *templates.Template
+	prompts []string
+	answers map[string]string
+}
+
+type templatesLoadedMsg struct {
+	title string
+	list  []templates.Template
+}
+
+func (a *App) loadNoteTemplates(title string) tea.Cmd {
+	return func() tea.Msg {
+		list, err := templates.List()
+		if err != nil {
+			return errMsg{err}
+		}
+		return templatesLoadedMsg{title: title, list: list}
+	}
+}
+
+// updateWSContentTemplate handles the template list shown after a note
+// title is entered. The first entry creates a blank note.
+func (a *App) updateWSContentTemplate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tp := &a.wsContent.template
+	switch msg.String() {
+	case "j", "down":
+		if tp.cursor < len(tp.list) {
+			tp.cursor++
+		}
+	case "k", "up":
+		if tp.cursor > 0 {
+			tp.cursor--
+		}
+	case "enter":
+		if tp.cursor == 0 {
+			return a, a.createTemplateNote()
+		}
+		chosen := tp.list[tp.cursor-1]
+		tp.chosen = &chosen
+		tp.prompts = templates.Prompts(chosen.Body)
+		tp.answers = make(map[string]string)
+		if len(tp.prompts) == 0 {
+			return a, a.createTemplateNote()
+		}
+		a.wsContent.creating = "prompt"
+		a.wsContent.input = ""
+	case "esc":
+		a.wsContent.creating = ""
+		a.wsContent.template = templatePickerModel{}
+	}
+	return a, nil
+}
+
+func (a *App) updateWSContentPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tp := &a.wsContent.template
+	switch msg.String() {
+	case "enter":
+		tp.answers[tp.prompts[len(tp.answers)]] = strings.TrimSpace(a.wsContent.input)
+		a.wsContent.input = ""
+		if len(tp.answers) == len(tp.prompts) {
+			return a, a.createTemplateNote()
+		}
+	case "esc":
+		a.wsContent.creating = ""
+		a.wsContent.template = templatePickerModel{}
+	case "backspace":
+		if len(a.wsContent.input) > 0 {
+			runes := []rune(a.wsContent.input)
+			a.wsContent.input = string(runes[:len(runes)-1])
+		}
+	default:
+		if len(msg.String()) == 1 {
+			a.wsContent.input += msg.String()
+		}
+	}
+	return a, nil
+}
+
+func (a *App) createTemplateNote() tea.Cmd {
+	tp := a.wsContent.template
+	ws := a.wsContent.workspace
+	a.wsContent.creating = ""
+	a.wsContent.template = templatePickerModel{}
+
+	body := ""
+	if tp.chosen != nil {
+		body = templates.Expand(tp.chosen.Body, templates.NoteVars(tp.title, ws.Name, time.Now()), tp.answers)
+	}
+	return func() tea.Msg {
+		note, err := a.db.CreateNote(tp.title, model.Slugify(tp.title), body, ws.ID)
+		if err != nil {
+			return errMsg{err}
+		}
+		if err := a.db.SyncNoteLinks(note); err != nil {
+			return errMsg{err}
+		}
+		return noteCreatedMsg{note}
+	}
+}
+
+func (a *App) viewTemplateDialog() string {
+	tp := a.wsContent.template
+	var rows []string
+
+	if a.wsContent.creating == "prompt" {
+		label := tp.prompts[len(tp.answers)]
+		rows = append(rows, formLabelActiveStyle.Render(fmt.Sprintf("%s (%s):", label, tp.chosen.Name)))
+		rows = append(rows, "")
+		rows = append(rows, fmt.Sprintf("  %s█", a.wsContent.input))
+		rows = append(rows, "")
+		rows = append(rows, helpStyle.Render(fmt.Sprintf("  enter: next (%d/%d)   esc: cancel", len(tp.answers)+1, len(tp.prompts))))
+		return dialogBoxStyle.Width(50).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	}
+
+	rows = append(rows, formLabelActiveStyle.Render(fmt.Sprintf("Template for %q:", truncate(tp.title, 30))))
+	rows = append(rows, "")
+	names := []string{"(blank)"}
+	for _, t := range tp.list {
+		names = append(names, t.Name)
+	}
+	for i, name := range names {
+		cursor := "  "
+		style := formValueStyle
+		if i == tp.cursor {
+			cursor = "▸ "
+			style = formLabelActiveStyle
+		}
+		rows = append(rows, cursor+style.Render(name))
+	}
+	rows = append(rows, "")
+	rows = append(rows, helpStyle.Render("  j/k: select   enter: create   esc: cancel"))
+	return dialogBoxStyle.Width(50).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}